@@ -0,0 +1,184 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"html/template"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/merenbach/goldbug/internal/api"
+)
+
+// Maximum size in bytes of a request body
+const maxBodySize = 1 << 20
+
+var (
+	templateDir = flag.String("templates", "templates", "directory holding HTML templates")
+	staticDir   = flag.String("static", "static", "directory holding static assets")
+)
+
+// A cipherInfo describes a cipher for the user interface.
+type cipherInfo struct {
+	Name   string      `json:"name"`
+	Params []api.Param `json:"params"`
+}
+
+// A server hosts the user interface and the cipher API.
+type server struct {
+	templates *template.Template
+	batch     api.Batch
+}
+
+// Catalog of all supported ciphers.
+func catalog() []cipherInfo {
+	names := api.Ciphers()
+	out := make([]cipherInfo, len(names))
+	for i, name := range names {
+		params, _ := api.Params(name)
+		out[i] = cipherInfo{Name: name, Params: params}
+	}
+	return out
+}
+
+// Index serves the user interface.
+func (s *server) index(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+
+	data := struct {
+		Ciphers []cipherInfo
+	}{catalog()}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := s.templates.ExecuteTemplate(w, "index.tmpl.html", data); err != nil {
+		log.Println("Could not render index:", err)
+	}
+}
+
+// API serves cipher operations along the same routes as the Lambda function.
+func (s *server) api(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	bb, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+		return
+	}
+	body := string(bb)
+
+	// Error messages are returned as strings for display
+	errString := func(err error) string {
+		if err != nil {
+			return err.Error()
+		}
+		return ""
+	}
+
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api"), "/")
+	parts := strings.Split(path, "/")
+
+	switch {
+	case len(parts) == 1 && parts[0] == "batch":
+		results, err := s.batch.Run(r.Context(), body)
+		writeJSON(w, struct {
+			Results []api.Result `json:"results"`
+			Error   string       `json:"error,omitempty"`
+		}{results, errString(err)})
+
+	case len(parts) == 3 && parts[0] == "cipher" && parts[2] == "tableau":
+		t, err := api.Tableau(parts[1], body)
+		if err == api.ErrUnknownCipher {
+			http.NotFound(w, r)
+			return
+		}
+		writeJSON(w, struct {
+			*api.TableauResult
+			Error string `json:"error,omitempty"`
+		}{t, errString(err)})
+
+	case len(parts) == 1:
+		p, ok := api.Lookup(parts[0])
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		out, err := p(body)
+		if out == nil {
+			out = &api.Output{}
+		}
+		writeJSON(w, struct {
+			*api.Output
+			Error string `json:"error,omitempty"`
+		}{out, errString(err)})
+
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// WriteJSON writes a JSON representation of a value.
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Println("Could not write response:", err)
+	}
+}
+
+// Secure adds protective headers to every response.
+func secure(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Security-Policy", "default-src 'self'")
+		w.Header().Set("Referrer-Policy", "no-referrer")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.Header().Set("X-Frame-Options", "deny")
+		h.ServeHTTP(w, r)
+	})
+}
+
+func main() {
+	flag.Parse()
+
+	t, err := template.ParseGlob(filepath.Join(*templateDir, "*.tmpl.html"))
+	if err != nil {
+		log.Fatal("Could not parse templates:", err)
+	}
+	s := &server{templates: t, batch: api.BatchFromEnv()}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.index)
+	mux.HandleFunc("/api/", s.api)
+	mux.Handle("/static/", http.StripPrefix("/static/", http.FileServer(http.Dir(*staticDir))))
+
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "5000"
+	}
+	log.Println("Listening on port", port)
+	log.Fatal(http.ListenAndServe(":"+port, secure(mux)))
+}
@@ -38,51 +38,67 @@ type Request events.APIGatewayProxyRequest
 // https://serverless.com/framework/docs/providers/aws/events/apigateway/#lambda-proxy-integration
 type Response = events.APIGatewayProxyResponse
 
+// Batch settings, configurable through the environment
+var batch = api.BatchFromEnv()
+
 // Handler is our lambda handler invoked by the `lambda.Start` function call
 func Handler(ctx context.Context, req Request) (events.APIGatewayProxyResponse, error) {
+	if req.Resource == "/batch" {
+		results, err := batch.Run(ctx, req.Body)
+		log.Printf("finished batch processing, any error is: %+v", err)
+		br := struct {
+			Results []api.Result `json:"results"`
+			Error   string       `json:"error,omitempty"`
+		}{Results: results}
+		if err != nil {
+			br.Error = err.Error()
+		}
+		return respond(br)
+	}
+
+	if req.Resource == "/cipher/{cipher}/tableau" {
+		t, err := api.Tableau(req.PathParameters["cipher"], req.Body)
+		if err == api.ErrUnknownCipher {
+			return Response{StatusCode: http.StatusNotFound}, nil
+		}
+		log.Printf("finished tableau rendering, any error is: %+v", err)
+		tr := struct {
+			*api.TableauResult
+			Error string `json:"error,omitempty"`
+		}{TableauResult: t}
+		if err != nil {
+			tr.Error = err.Error()
+		}
+		return respond(tr)
+	}
+
 	var (
-		out string
+		out *api.Output
 		err error
 	)
 
 	if c, ok := req.PathParameters["cipher"]; ok {
-		switch c {
-		case "affine":
-			out, err = api.Affine(req.Body)
-		case "atbash":
-			out, err = api.Atbash(req.Body)
-		case "caesar":
-			out, err = api.Caesar(req.Body)
-		case "decimation":
-			out, err = api.Decimation(req.Body)
-		case "keyword":
-			out, err = api.Keyword(req.Body)
-		case "rot13":
-			out, err = api.Rot13(req.Body)
-		case "vigenere":
-			out, err = api.Vigenere(req.Body)
-		case "beaufort":
-			out, err = api.Beaufort(req.Body)
-		case "dellaporta":
-			out, err = api.DellaPorta(req.Body)
-		case "gronsfeld":
-			out, err = api.Gronsfeld(req.Body)
-		case "trithemius":
-			out, err = api.Trithemius(req.Body)
-		case "variantbeaufort":
-			out, err = api.VariantBeaufort(req.Body)
-		default:
+		p, ok := api.Lookup(c)
+		if !ok {
 			return Response{StatusCode: http.StatusNotFound}, nil
 		}
+		out, err = p(req.Body)
+	}
+	if out == nil {
+		out = &api.Output{}
 	}
 
 	log.Printf("finished processing, any error is: %+v", err)
 	mr := struct {
-		Message string `json:"message"`
-		Error   error  `json:"error"`
+		*api.Output
+		Error error `json:"error"`
 	}{out, err}
+	return respond(mr)
+}
 
-	bb, err := json.Marshal(mr)
+// Respond with a JSON representation of a value.
+func respond(v interface{}) (Response, error) {
+	bb, err := json.Marshal(v)
 	if err != nil {
 		// TODO: don't return internal server error if we can avoid it
 		return Response{StatusCode: http.StatusInternalServerError}, err
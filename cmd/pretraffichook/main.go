@@ -19,38 +19,66 @@ package main
 
 import (
 	"context"
-	"encoding/json"
+	"fmt"
 	"log"
 	"os"
 
-	"github.com/aws/aws-lambda-go/events"
 	this "github.com/aws/aws-lambda-go/lambda"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/codedeploy"
 	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/merenbach/goldbug/internal/api"
+	"github.com/merenbach/goldbug/internal/smoketest"
 )
 
-// Request is of type APIGatewayProxyResponse since we're leveraging the
-// AWS Lambda Proxy Request functionality (default behavior)
-//
-// https://serverless.com/framework/docs/providers/aws/events/apigateway/#lambda-proxy-integration
-type Request events.APIGatewayProxyRequest
+// A lambdaInvoker synchronously invokes a Lambda function version.
+type lambdaInvoker struct {
+	client       *lambda.Lambda
+	functionName string
+}
 
-// Response is of type APIGatewayProxyResponse since we're leveraging the
-// AWS Lambda Proxy Request functionality (default behavior)
-//
-// https://serverless.com/framework/docs/providers/aws/events/apigateway/#lambda-proxy-integration
-type Response events.APIGatewayProxyResponse
+// Invoke the function with a payload and return its response payload.
+func (l *lambdaInvoker) Invoke(payload []byte) ([]byte, error) {
+	output, err := l.client.Invoke(&lambda.InvokeInput{
+		FunctionName:   aws.String(l.functionName),
+		InvocationType: aws.String(lambda.InvocationTypeRequestResponse),
+		Payload:        payload,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if output.FunctionError != nil {
+		return nil, fmt.Errorf("Function error %s: %s", aws.StringValue(output.FunctionError), output.Payload)
+	}
+	return output.Payload, nil
+}
+
+// A codedeployReporter completes a lifecycle hook with a validation status.
+type codedeployReporter struct {
+	client                        *codedeploy.CodeDeploy
+	deploymentID                  *string
+	lifecycleEventHookExecutionID *string
+}
+
+// Report a status, which can be 'Succeeded' or 'Failed', to CodeDeploy.
+func (c *codedeployReporter) Report(status string) error {
+	req, err := c.client.PutLifecycleEventHookExecutionStatus(&codedeploy.PutLifecycleEventHookExecutionStatusInput{
+		DeploymentId:                  c.deploymentID,
+		LifecycleEventHookExecutionId: c.lifecycleEventHookExecutionID,
+		Status:                        aws.String(status),
+	})
+	if err != nil {
+		return err
+	}
+	log.Println("Execution ID:", aws.StringValue(req.LifecycleEventHookExecutionId))
+	return nil
+}
 
 // Handler is our lambda handler invoked by the `lambda.Start` function call
 func Handler(ctx context.Context, event codedeploy.PutLifecycleEventHookExecutionStatusInput) error {
 	log.Println("Entering PreTraffic Hook!")
 
-	// Read the DeploymentId & LifecycleEventHookExecutionId from the event payload
-	deploymentID := event.DeploymentId
-	lifecycleEventHookExecutionID := event.LifecycleEventHookExecutionId
-
 	functionToTest := os.Getenv("NewVersion")
 	log.Println("Testing new function version:", functionToTest)
 
@@ -58,61 +86,28 @@ func Handler(ctx context.Context, event codedeploy.PutLifecycleEventHookExecutio
 		SharedConfigState: session.SharedConfigEnable,
 	}))
 
-	lambdaParams := &lambda.InvokeInput{
-		FunctionName:   aws.String(functionToTest),
-		InvocationType: aws.String(lambda.InvocationTypeRequestResponse),
-	}
-
-	lambdaResult := "Failed"
-
-	client := lambda.New(sess)
-	// result, err := client.Invoke(&lambda.InvokeInput{FunctionName: aws.String("MyGetItemsFunction"), Payload: payload})
-	output, err := client.Invoke(lambdaParams)
-	if err != nil {
-		log.Println(err)
-		return err
+	invoker := &lambdaInvoker{
+		client:       lambda.New(sess),
+		functionName: functionToTest,
 	}
 
-	// Check the response for valid results
-	// The response will be a JSON payload with statusCode and body properties. ie:
-	// {
-	//		"statusCode": 200,
-	//		"body": 51
-	// }
-
-	var result Response
-	if err := json.Unmarshal(output.Payload, &result); err != nil {
-		return err
-	}
-	log.Println("Result:", result)
-
-	lambdaResult = "Succeeded"
-
-	// re := regexp.MustCompile(`^output$`)
-	// if re.MatchString(result.Body) {
-	// 	lambdaResult = "Succeeded"
-	// 	log.Println("Validation testing succeeded!")
-	// } else {
-	// 	lambdaResult = "Failed"
-	// 	log.Println("Validation testing failed!")
-	// }
-
-	// Complete the PreTraffic Hook by sending CodeDeploy the validation status
-	var params = &codedeploy.PutLifecycleEventHookExecutionStatusInput{
-		DeploymentId:                  deploymentID,
-		LifecycleEventHookExecutionId: lifecycleEventHookExecutionID,
-		Status:                        aws.String(lambdaResult), // status can be 'Succeeded' or 'Failed'
+	// Read the DeploymentId & LifecycleEventHookExecutionId from the event payload
+	reporter := &codedeployReporter{
+		client:                        codedeploy.New(sess),
+		deploymentID:                  event.DeploymentId,
+		lifecycleEventHookExecutionID: event.LifecycleEventHookExecutionId,
 	}
 
-	// Pass AWS CodeDeploy the prepared validation test results.
-	codedeployClient := codedeploy.New(sess)
-	req, err := codedeployClient.PutLifecycleEventHookExecutionStatus(params)
+	// An empty suite fails, so a broken fixture still blocks the deployment
+	vectors, err := smoketest.Vectors(api.Ciphers())
 	if err != nil {
-		return err
+		log.Println("Could not load test vectors:", err)
 	}
-	log.Println("Execution ID:", req.LifecycleEventHookExecutionId)
 
-	return nil
+	// Pass AWS CodeDeploy the prepared validation test results.
+	status, err := smoketest.Hook(&smoketest.Suite{Vectors: vectors}, invoker, reporter)
+	log.Println("Reported status:", status)
+	return err
 }
 
 func main() {
@@ -0,0 +1,130 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package alphabet provides named alphabets and checks custom ones.
+package alphabet
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"unicode/utf8"
+)
+
+// Preset alphabets.
+const (
+	// Latin is the 26-letter Latin alphabet.
+	Latin = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+	// Latin25 is the Latin alphabet without J, as used in 5x5 squares.
+	Latin25 = "ABCDEFGHIKLMNOPQRSTUVWXYZ"
+
+	// Alphanumeric is the Latin alphabet followed by the ten digits, as used in 6x6 squares.
+	Alphanumeric = "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+	// Greek is the 24-letter Greek alphabet.
+	Greek = "ΑΒΓΔΕΖΗΘΙΚΛΜΝΞΟΠΡΣΤΥΦΧΨΩ"
+
+	// Cyrillic is the 33-letter Russian alphabet.
+	Cyrillic = "АБВГДЕЁЖЗИЙКЛМНОПРСТУФХЦЧШЩЪЫЬЭЮЯ"
+
+	// Hebrew is the 22-letter Hebrew alphabet, without final forms.
+	Hebrew = "אבגדהוזחטיכלמנסעפצקרשת"
+
+	// Arabic is the 28-letter Arabic alphabet in hijā'ī order.
+	Arabic = "ابتثجحخدذرزسشصضطظعغفقكلمنهوي"
+
+	// ASCII is the 95 printable ASCII characters, from space to tilde.
+	ASCII = " !\"#$%&'()*+,-./0123456789:;<=>?@ABCDEFGHIJKLMNOPQRSTUVWXYZ[\\]^_`abcdefghijklmnopqrstuvwxyz{|}~"
+)
+
+// Presets by name.
+var presets = map[string]string{
+	"latin":        Latin,
+	"latin25":      Latin25,
+	"alphanumeric": Alphanumeric,
+	"greek":        Greek,
+	"cyrillic":     Cyrillic,
+	"hebrew":       Hebrew,
+	"arabic":       Arabic,
+	"ascii":        ASCII,
+}
+
+// Lookup a preset alphabet by name.
+func Lookup(name string) (string, bool) {
+	s, ok := presets[name]
+	return s, ok
+}
+
+// Names of all preset alphabets in sorted order.
+func Names() []string {
+	out := make([]string, 0, len(presets))
+	for k := range presets {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Unique checks that an alphabet has no duplicate runes.
+func Unique(s string) error {
+	seen := make(map[rune]struct{})
+	for _, r := range s {
+		if _, ok := seen[r]; ok {
+			return fmt.Errorf("Alphabet contains duplicate character %q", r)
+		}
+		seen[r] = struct{}{}
+	}
+	return nil
+}
+
+// Validate an alphabet, which must be nonempty and have no duplicate runes.
+func Validate(s string) error {
+	if s == "" {
+		return errors.New("Alphabet must not be empty")
+	}
+	return Unique(s)
+}
+
+// ValidateLength validates an alphabet and checks that its length lies between min and max, inclusive.
+// A max of zero imposes no maximum.
+func ValidateLength(s string, min int, max int) error {
+	if err := Validate(s); err != nil {
+		return err
+	}
+	n := utf8.RuneCountInString(s)
+	if n < min {
+		return fmt.Errorf("Alphabet must have at least %d characters, but has %d", min, n)
+	}
+	if max > 0 && n > max {
+		return fmt.Errorf("Alphabet must have at most %d characters, but has %d", max, n)
+	}
+	return nil
+}
+
+// Resolve a preset name or a custom alphabet into a valid alphabet.
+// Preset names take precedence over custom alphabets with the same spelling.
+// Resolve returns an empty string, signifying the default alphabet, for empty input.
+func Resolve(s string) (string, error) {
+	if s == "" {
+		return "", nil
+	}
+	if p, ok := Lookup(s); ok {
+		return p, nil
+	}
+	if err := Validate(s); err != nil {
+		return "", err
+	}
+	return s, nil
+}
@@ -0,0 +1,89 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alphabet
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestPresets(t *testing.T) {
+	lengths := map[string]int{
+		"latin":        26,
+		"latin25":      25,
+		"alphanumeric": 36,
+		"greek":        24,
+		"cyrillic":     33,
+		"hebrew":       22,
+		"arabic":       28,
+		"ascii":        95,
+	}
+
+	for _, name := range Names() {
+		s, _ := Lookup(name)
+		if err := Validate(s); err != nil {
+			t.Errorf("Preset %q is invalid: %v", name, err)
+		}
+		if n := utf8.RuneCountInString(s); n != lengths[name] {
+			t.Errorf("Expected preset %q to have length %d, but got %d", name, lengths[name], n)
+		}
+	}
+	if len(Names()) != len(lengths) {
+		t.Errorf("Expected %d presets, but got %d", len(lengths), len(Names()))
+	}
+}
+
+func TestValidateLength(t *testing.T) {
+	tables := []struct {
+		s   string
+		min int
+		max int
+		ok  bool
+	}{
+		{"ABC", 1, 0, true},
+		{"", 0, 0, false},
+		{"ABCA", 1, 0, false},
+		{"ABC", 4, 0, false},
+		{"ABC", 1, 2, false},
+		{"ΑΒΓ", 3, 3, true},
+	}
+
+	for _, table := range tables {
+		if err := ValidateLength(table.s, table.min, table.max); (err == nil) != table.ok {
+			t.Errorf("Unexpected result validating %q with length in [%d, %d]: %v", table.s, table.min, table.max, err)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	tables := []struct {
+		s        string
+		expected string
+		ok       bool
+	}{
+		{"", "", true},
+		{"latin25", Latin25, true},
+		{"greek", Greek, true},
+		{"XYZ", "XYZ", true},
+		{"XYZX", "", false},
+	}
+
+	for _, table := range tables {
+		out, err := Resolve(table.s)
+		if (err == nil) != table.ok || out != table.expected {
+			t.Errorf("Expected %q to resolve to %q, but got %q (error: %v)", table.s, table.expected, out, err)
+		}
+	}
+}
@@ -17,27 +17,181 @@ package api
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 
+	"github.com/merenbach/goldbug/internal/alphabet"
+	"github.com/merenbach/goldbug/internal/format"
+	"github.com/merenbach/goldbug/internal/masc"
+	"github.com/merenbach/goldbug/internal/normalize"
+	"github.com/merenbach/goldbug/internal/prng"
+	"github.com/merenbach/goldbug/internal/trace"
 	"github.com/merenbach/goldbug/pkg/affine"
 	"github.com/merenbach/goldbug/pkg/atbash"
 	"github.com/merenbach/goldbug/pkg/beaufort"
 	"github.com/merenbach/goldbug/pkg/caesar"
+	"github.com/merenbach/goldbug/pkg/chaocipher"
+	"github.com/merenbach/goldbug/pkg/checkerboard"
 	"github.com/merenbach/goldbug/pkg/decimation"
 	"github.com/merenbach/goldbug/pkg/dellaporta"
+	"github.com/merenbach/goldbug/pkg/enigma"
 	"github.com/merenbach/goldbug/pkg/gronsfeld"
+	"github.com/merenbach/goldbug/pkg/homophonic"
 	"github.com/merenbach/goldbug/pkg/keyword"
+	"github.com/merenbach/goldbug/pkg/lorenz"
+	"github.com/merenbach/goldbug/pkg/m209"
+	"github.com/merenbach/goldbug/pkg/nihilist"
+	"github.com/merenbach/goldbug/pkg/otp"
+	"github.com/merenbach/goldbug/pkg/polybius"
+	"github.com/merenbach/goldbug/pkg/portax"
+	"github.com/merenbach/goldbug/pkg/railfence"
 	"github.com/merenbach/goldbug/pkg/rot13"
+	"github.com/merenbach/goldbug/pkg/scytale"
+	"github.com/merenbach/goldbug/pkg/solitaire"
 	"github.com/merenbach/goldbug/pkg/trithemius"
 	"github.com/merenbach/goldbug/pkg/variantbeaufort"
+	"github.com/merenbach/goldbug/pkg/vernam"
+	"github.com/merenbach/goldbug/pkg/vic"
 	"github.com/merenbach/goldbug/pkg/vigenere"
 )
 
 // MascBaseConfig is a base configuration for a monoalphabetic substitution cipher operation
 type mascBaseConfig struct {
 	Alphabet string `json:"alphabet"`
+	Explain  bool   `json:"explain"`
 	Message  string `json:"message"`
 	Reverse  bool   `json:"reverse"`
 	Strict   bool   `json:"strict"`
+
+	Case              string `json:"case"`
+	StripDiacritics   bool   `json:"stripDiacritics"`
+	Symbols           string `json:"symbols"`
+	SymbolReplacement string `json:"symbolReplacement"`
+
+	GroupSize      int    `json:"groupSize"`
+	GroupDelimiter string `json:"groupDelimiter"`
+	GroupsPerLine  int    `json:"groupsPerLine"`
+	Padding        string `json:"padding"`
+}
+
+// Case modes, keyed by name.
+var caseModes = map[string]normalize.Case{
+	"":         normalize.ExactCase,
+	"exact":    normalize.ExactCase,
+	"preserve": normalize.PreserveCase,
+	"upper":    normalize.UpperCase,
+	"lower":    normalize.LowerCase,
+}
+
+// Symbol modes, keyed by name.
+var symbolModes = map[string]normalize.SymbolMode{
+	"":        normalize.KeepSymbols,
+	"keep":    normalize.KeepSymbols,
+	"drop":    normalize.DropSymbols,
+	"replace": normalize.ReplaceSymbols,
+}
+
+// Trithemius restart modes, keyed by name.
+var restartModes = map[string]trithemius.Restart{
+	"":     trithemius.Continuous,
+	"none": trithemius.Continuous,
+	"word": trithemius.PerWord,
+	"line": trithemius.PerLine,
+}
+
+// Lorenz limitations, keyed by name.
+var limitations = map[string]lorenz.Limitation{
+	"":         lorenz.NoLimitation,
+	"none":     lorenz.NoLimitation,
+	"chi2":     lorenz.Chi2,
+	"chi2psi1": lorenz.Chi2Psi1,
+}
+
+// One-time pad combinations, keyed by name.
+var combinations = map[string]otp.Combination{
+	"":         otp.Vigenere,
+	"vigenere": otp.Vigenere,
+	"beaufort": otp.Beaufort,
+}
+
+// Homophone selections, keyed by name.
+var selections = map[string]homophonic.Selection{
+	"":       homophonic.Cyclic,
+	"cyclic": homophonic.Cyclic,
+	"random": homophonic.Random,
+}
+
+// Normalizer for text as described by this configuration.
+func (c *mascBaseConfig) normalizer() (normalize.Normalizer, error) {
+	var n normalize.Normalizer
+
+	m, ok := caseModes[c.Case]
+	if !ok {
+		return n, fmt.Errorf("Unknown case mode %q", c.Case)
+	}
+	sm, ok := symbolModes[c.Symbols]
+	if !ok {
+		return n, fmt.Errorf("Unknown symbol mode %q", c.Symbols)
+	}
+	n.Case, n.Symbols, n.StripDiacritics = m, sm, c.StripDiacritics
+
+	if sm == normalize.ReplaceSymbols {
+		r := []rune(c.SymbolReplacement)
+		if len(r) != 1 {
+			return n, errors.New("Symbol replacement must be a single character")
+		}
+		n.Replacement = r[0]
+	}
+	return n, nil
+}
+
+// ResolveAlphabet replaces a preset alphabet name with the alphabet itself and validates the result.
+func (c *mascBaseConfig) resolveAlphabet() error {
+	a, err := alphabet.Resolve(c.Alphabet)
+	if err != nil {
+		return err
+	}
+	c.Alphabet = a
+	return nil
+}
+
+// Formatter for output as described by this configuration.
+func (c *mascBaseConfig) formatter() (*format.Formatter, error) {
+	a, err := alphabet.Resolve(c.Alphabet)
+	if err != nil {
+		return nil, err
+	}
+	if a == "" {
+		a = masc.Alphabet
+	}
+
+	f := format.Formatter{
+		Alphabet:      a,
+		GroupSize:     c.GroupSize,
+		Delimiter:     c.GroupDelimiter,
+		GroupsPerLine: c.GroupsPerLine,
+	}
+	p, err := c.padding()
+	if err != nil {
+		return nil, err
+	}
+	f.Padding = p
+	return &f, nil
+}
+
+// Padding rune described by this configuration, or zero for none.
+func (c *mascBaseConfig) padding() (rune, error) {
+	return single(c.Padding, "Padding")
+}
+
+// Single rune described by a named setting, or zero for none.
+func single(s string, name string) (rune, error) {
+	switch r := []rune(s); len(r) {
+	case 0:
+		return 0, nil
+	case 1:
+		return r[0], nil
+	}
+	return 0, fmt.Errorf("%s must be a single character", name)
 }
 
 // MascBaseConfig is a base configuration for a polyalphabetic substitution cipher operation
@@ -46,151 +200,521 @@ type pascBaseConfig struct {
 	Countersign string `json:"countersign"`
 }
 
+// A cipher enciphers and deciphers messages.
+type cipher interface {
+	Encipher(string) (string, error)
+	Decipher(string) (string, error)
+}
+
+// A labeler writes ciphertext with its own labels rather than with the runes of its alphabet.
+type labeler interface {
+	Labels() (string, error)
+}
+
+// A tracer enciphers and deciphers messages while recording how each rune was produced.
+type tracer interface {
+	EncipherTrace(string) (string, trace.Trace, error)
+	DecipherTrace(string) (string, trace.Trace, error)
+}
+
+// ErrNoTrace is returned when an explanation is requested from a cipher that cannot provide one.
+var ErrNoTrace = errors.New("Cipher does not support explanations")
+
+// An Output holds the result of a cipher operation.
+// The trace is present only if an explanation was requested.
+type Output struct {
+	Message string      `json:"message"`
+	Trace   trace.Trace `json:"trace,omitempty"`
+}
+
+// Run a JSON payload with a cipher created from that same payload.
+func run(s string, f func(string) (cipher, error)) (*Output, error) {
+	var payload mascBaseConfig
+	if err := json.Unmarshal([]byte(s), &payload); err != nil {
+		return nil, err
+	}
+
+	c, err := f(s)
+	if err != nil {
+		return nil, err
+	}
+
+	ff, err := payload.formatter()
+	if err != nil {
+		return nil, err
+	}
+	if l, ok := c.(labeler); ok {
+		if ff.Alphabet, err = l.Labels(); err != nil {
+			return nil, err
+		}
+	}
+	if payload.Reverse && ff.GroupSize > 0 {
+		payload.Message = ff.Unformat(payload.Message)
+	}
+
+	var out Output
+	if payload.Explain {
+		t, ok := c.(tracer)
+		if !ok {
+			return nil, ErrNoTrace
+		}
+		if payload.Reverse {
+			out.Message, out.Trace, err = t.DecipherTrace(payload.Message)
+		} else {
+			out.Message, out.Trace, err = t.EncipherTrace(payload.Message)
+		}
+	} else if payload.Reverse {
+		out.Message, err = c.Decipher(payload.Message)
+	} else {
+		out.Message, err = c.Encipher(payload.Message)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !payload.Reverse {
+		out.Message = ff.Format(out.Message)
+	}
+	return &out, nil
+}
+
+// Process a JSON payload with a cipher created from that same payload.
+func process(s string, f func(string) (cipher, error)) (string, error) {
+	out, err := run(s, f)
+	if err != nil {
+		return "", err
+	}
+	return out.Message, nil
+}
+
 // Affine cipher processing
 func Affine(s string) (string, error) {
+	return process(s, newAffine)
+}
+
+// NewAffine creates a cipher from a JSON payload.
+func newAffine(s string) (cipher, error) {
 	var payload struct {
 		mascBaseConfig
 		Shift      int `json:"shift"`
 		Multiplier int `json:"multiplier"`
 	}
 	if err := json.Unmarshal([]byte(s), &payload); err != nil {
-		return "", err
+		return nil, err
 	}
-
-	c := affine.Cipher{
-		Alphabet:  payload.Alphabet,
-		Slope:     payload.Multiplier,
-		Intercept: payload.Shift,
-		Strict:    payload.Strict,
+	n, err := payload.normalizer()
+	if err != nil {
+		return nil, err
+	}
+	if err := payload.resolveAlphabet(); err != nil {
+		return nil, err
 	}
 
-	if payload.Reverse {
-		return c.Decipher(payload.Message)
+	c := &affine.Cipher{
+		Alphabet:   payload.Alphabet,
+		Slope:      payload.Multiplier,
+		Intercept:  payload.Shift,
+		Strict:     payload.Strict,
+		Normalizer: n,
 	}
-	return c.Encipher(payload.Message)
+	return c, nil
 }
 
 // Atbash cipher processing
 func Atbash(s string) (string, error) {
+	return process(s, newAtbash)
+}
+
+// NewAtbash creates a cipher from a JSON payload.
+func newAtbash(s string) (cipher, error) {
 	var payload struct {
 		mascBaseConfig
 	}
 	if err := json.Unmarshal([]byte(s), &payload); err != nil {
-		return "", err
+		return nil, err
 	}
-
-	c := atbash.Cipher{
-		Alphabet: payload.Alphabet,
-		Strict:   payload.Strict,
+	n, err := payload.normalizer()
+	if err != nil {
+		return nil, err
+	}
+	if err := payload.resolveAlphabet(); err != nil {
+		return nil, err
 	}
 
-	if payload.Reverse {
-		return c.Decipher(payload.Message)
+	c := &atbash.Cipher{
+		Alphabet:   payload.Alphabet,
+		Strict:     payload.Strict,
+		Normalizer: n,
 	}
-	return c.Encipher(payload.Message)
+	return c, nil
 }
 
 // Caesar cipher processing
 func Caesar(s string) (string, error) {
+	return process(s, newCaesar)
+}
+
+// NewCaesar creates a cipher from a JSON payload.
+func newCaesar(s string) (cipher, error) {
 	var payload struct {
 		mascBaseConfig
 		Shift int `json:"shift"`
 	}
 	if err := json.Unmarshal([]byte(s), &payload); err != nil {
-		return "", err
+		return nil, err
+	}
+	n, err := payload.normalizer()
+	if err != nil {
+		return nil, err
+	}
+	if err := payload.resolveAlphabet(); err != nil {
+		return nil, err
 	}
 
-	c := caesar.Cipher{
-		Alphabet: payload.Alphabet,
-		Shift:    payload.Shift,
-		Strict:   payload.Strict,
+	c := &caesar.Cipher{
+		Alphabet:   payload.Alphabet,
+		Shift:      payload.Shift,
+		Strict:     payload.Strict,
+		Normalizer: n,
 	}
+	return c, nil
+}
 
-	if payload.Reverse {
-		return c.Decipher(payload.Message)
+// Chaocipher processing
+func Chaocipher(s string) (string, error) {
+	return process(s, newChaocipher)
+}
+
+// NewChaocipher creates a cipher from a JSON payload.
+func newChaocipher(s string) (cipher, error) {
+	var payload struct {
+		mascBaseConfig
+		Left  string `json:"left"`
+		Right string `json:"right"`
+	}
+	if err := json.Unmarshal([]byte(s), &payload); err != nil {
+		return nil, err
 	}
-	return c.Encipher(payload.Message)
+	n, err := payload.normalizer()
+	if err != nil {
+		return nil, err
+	}
+	if err := payload.resolveAlphabet(); err != nil {
+		return nil, err
+	}
+
+	c := &chaocipher.Cipher{
+		Alphabet:   payload.Alphabet,
+		Left:       payload.Left,
+		Right:      payload.Right,
+		Strict:     payload.Strict,
+		Normalizer: n,
+	}
+	return c, nil
 }
 
 // Decimation cipher processing
 func Decimation(s string) (string, error) {
+	return process(s, newDecimation)
+}
+
+// NewDecimation creates a cipher from a JSON payload.
+func newDecimation(s string) (cipher, error) {
 	var payload struct {
 		mascBaseConfig
 		Multiplier int `json:"multiplier"`
 	}
 	if err := json.Unmarshal([]byte(s), &payload); err != nil {
-		return "", err
+		return nil, err
+	}
+	n, err := payload.normalizer()
+	if err != nil {
+		return nil, err
+	}
+	if err := payload.resolveAlphabet(); err != nil {
+		return nil, err
 	}
 
-	c := decimation.Cipher{
+	c := &decimation.Cipher{
 		Alphabet:   payload.Alphabet,
 		Multiplier: payload.Multiplier,
 		Strict:     payload.Strict,
+		Normalizer: n,
+	}
+	return c, nil
+}
+
+// Homophonic cipher processing
+func Homophonic(s string) (string, error) {
+	return process(s, newHomophonic)
+}
+
+// NewHomophonic creates a cipher from a JSON payload.
+// A table given in the payload is used as-is, and otherwise one is generated from the alphabet, codes, code symbols and keyword.
+func newHomophonic(s string) (cipher, error) {
+	var payload struct {
+		mascBaseConfig
+		Table       homophonic.Table `json:"table"`
+		Codes       int              `json:"codes"`
+		CodeSymbols string           `json:"codeSymbols"`
+		Keyword     string           `json:"keyword"`
+		Selection   string           `json:"selection"`
+		Seed        *int64           `json:"seed"`
+	}
+	if err := json.Unmarshal([]byte(s), &payload); err != nil {
+		return nil, err
+	}
+	n, err := payload.normalizer()
+	if err != nil {
+		return nil, err
+	}
+	if err := payload.resolveAlphabet(); err != nil {
+		return nil, err
+	}
+
+	sel, ok := selections[payload.Selection]
+	if !ok {
+		return nil, fmt.Errorf("Unknown selection %q", payload.Selection)
+	}
+
+	t := payload.Table
+	if t == nil {
+		g := &homophonic.Generator{
+			Alphabet: payload.Alphabet,
+			Codes:    payload.Codes,
+			Symbols:  payload.CodeSymbols,
+			Keyword:  payload.Keyword,
+		}
+		if t, err = g.Table(); err != nil {
+			return nil, err
+		}
 	}
 
-	if payload.Reverse {
-		return c.Decipher(payload.Message)
+	c := &homophonic.Cipher{
+		Table:      t,
+		Selection:  sel,
+		Seed:       payload.Seed,
+		Strict:     payload.Strict,
+		Normalizer: n,
 	}
-	return c.Encipher(payload.Message)
+	return c, nil
 }
 
 // Keyword cipher processing
 func Keyword(s string) (string, error) {
+	return process(s, newKeyword)
+}
+
+// NewKeyword creates a cipher from a JSON payload.
+func newKeyword(s string) (cipher, error) {
 	var payload struct {
 		mascBaseConfig
 		Keyword string `json:"keyword"`
 	}
 	if err := json.Unmarshal([]byte(s), &payload); err != nil {
-		return "", err
+		return nil, err
 	}
-	c := keyword.Cipher{
-		Alphabet: payload.Alphabet,
-		Keyword:  payload.Keyword,
-		Strict:   payload.Strict,
+	n, err := payload.normalizer()
+	if err != nil {
+		return nil, err
 	}
-
-	if payload.Reverse {
-		return c.Decipher(payload.Message)
+	if err := payload.resolveAlphabet(); err != nil {
+		return nil, err
+	}
+	c := &keyword.Cipher{
+		Alphabet:   payload.Alphabet,
+		Keyword:    payload.Keyword,
+		Strict:     payload.Strict,
+		Normalizer: n,
 	}
-	return c.Encipher(payload.Message)
+	return c, nil
 }
 
 // Rot13 cipher processing
 func Rot13(s string) (string, error) {
+	return process(s, newRot13)
+}
+
+// NewRot13 creates a cipher from a JSON payload.
+func newRot13(s string) (cipher, error) {
 	var payload struct {
 		mascBaseConfig
 	}
 	if err := json.Unmarshal([]byte(s), &payload); err != nil {
-		return "", err
+		return nil, err
+	}
+	n, err := payload.normalizer()
+	if err != nil {
+		return nil, err
+	}
+	if err := payload.resolveAlphabet(); err != nil {
+		return nil, err
 	}
 
-	c := rot13.Cipher{}
+	c := &rot13.Cipher{
+		Strict:     payload.Strict,
+		Normalizer: n,
+	}
+	return c, nil
+}
+
+// OTP cipher processing
+func OTP(s string) (string, error) {
+	return process(s, newOTP)
+}
 
-	if payload.Reverse {
-		return c.Decipher(payload.Message)
+// NewOTP creates a cipher from a JSON payload.
+func newOTP(s string) (cipher, error) {
+	var payload struct {
+		mascBaseConfig
+		Pad         string `json:"pad"`
+		Combination string `json:"combination"`
 	}
-	return c.Encipher(payload.Message)
+	if err := json.Unmarshal([]byte(s), &payload); err != nil {
+		return nil, err
+	}
+	n, err := payload.normalizer()
+	if err != nil {
+		return nil, err
+	}
+	if err := payload.resolveAlphabet(); err != nil {
+		return nil, err
+	}
+
+	comb, ok := combinations[payload.Combination]
+	if !ok {
+		return nil, fmt.Errorf("Unknown combination %q", payload.Combination)
+	}
+
+	c := &otp.Cipher{
+		Alphabet:    payload.Alphabet,
+		Pad:         payload.Pad,
+		Combination: comb,
+		Strict:      payload.Strict,
+		Normalizer:  n,
+	}
+	return c, nil
+}
+
+// Vernam cipher processing
+func Vernam(s string) (string, error) {
+	return process(s, newVernam)
+}
+
+// NewVernam creates a cipher from a JSON payload.
+func newVernam(s string) (cipher, error) {
+	var payload struct {
+		mascBaseConfig
+		Countersign string `json:"countersign"`
+		Pad         string `json:"pad"`
+		Generator   string `json:"generator"`
+		Modulus     int    `json:"modulus"`
+		Multiplier  int    `json:"multiplier"`
+		Increment   int    `json:"increment"`
+		Seed        int    `json:"seed"`
+		Seeds       []int  `json:"seeds"`
+		Taps        []int  `json:"taps"`
+	}
+	if err := json.Unmarshal([]byte(s), &payload); err != nil {
+		return nil, err
+	}
+	n, err := payload.normalizer()
+	if err != nil {
+		return nil, err
+	}
+
+	c := &vernam.Cipher{
+		Key:        payload.Countersign,
+		Pad:        payload.Pad,
+		Normalizer: n,
+	}
+	switch payload.Generator {
+	case "":
+	case "lcg":
+		g := &prng.LCG{Modulus: payload.Modulus, Multiplier: payload.Multiplier, Increment: payload.Increment, Seed: payload.Seed}
+		c.Generator = g.Iterator
+	case "alfg":
+		g := &prng.LFG{Modulus: payload.Modulus, Seed: payload.Seeds, Taps: payload.Taps}
+		c.Generator = g.IteratorA
+	case "mlfg":
+		g := &prng.LFG{Modulus: payload.Modulus, Seed: payload.Seeds, Taps: payload.Taps}
+		c.Generator = g.IteratorM
+	default:
+		return nil, fmt.Errorf("Unknown generator %q", payload.Generator)
+	}
+	return c, nil
+}
+
+// VIC cipher processing
+func VIC(s string) (string, error) {
+	return process(s, newVIC)
+}
+
+// NewVIC creates a cipher from a JSON payload.
+func newVIC(s string) (cipher, error) {
+	var payload struct {
+		mascBaseConfig
+		Phrase         string `json:"phrase"`
+		Date           string `json:"date"`
+		PersonalNumber int    `json:"personalNumber"`
+		Indicator      string `json:"indicator"`
+		Blanks         []int  `json:"blanks"`
+	}
+	if err := json.Unmarshal([]byte(s), &payload); err != nil {
+		return nil, err
+	}
+	n, err := payload.normalizer()
+	if err != nil {
+		return nil, err
+	}
+	if err := payload.resolveAlphabet(); err != nil {
+		return nil, err
+	}
+
+	c := &vic.Cipher{
+		Phrase:         payload.Phrase,
+		Date:           payload.Date,
+		PersonalNumber: payload.PersonalNumber,
+		Indicator:      payload.Indicator,
+		Alphabet:       payload.Alphabet,
+		Blanks:         payload.Blanks,
+		Normalizer:     n,
+	}
+	return c, nil
 }
 
 // Vigenere cipher processing
 func Vigenere(s string) (string, error) {
+	return process(s, newVigenere)
+}
+
+// NewVigenere creates a cipher from a JSON payload.
+func newVigenere(s string) (cipher, error) {
 	var payload struct {
 		pascBaseConfig
 		TextAutoclave bool `json:"textAutoclave"`
 		KeyAutoclave  bool `json:"keyAutoclave"`
 	}
 	if err := json.Unmarshal([]byte(s), &payload); err != nil {
-		return "", err
+		return nil, err
+	}
+	n, err := payload.normalizer()
+	if err != nil {
+		return nil, err
+	}
+	if err := payload.resolveAlphabet(); err != nil {
+		return nil, err
 	}
 
 	if payload.TextAutoclave && payload.KeyAutoclave {
-		return "", errors.New("Text autoclave and key autoclave are mutually exclusive")
+		return nil, errors.New("Text autoclave and key autoclave are mutually exclusive")
 	}
 
-	c := vigenere.Cipher{
-		Alphabet: payload.Alphabet,
-		Key:      payload.Countersign,
-		Strict:   payload.Strict,
+	c := &vigenere.Cipher{
+		Alphabet:   payload.Alphabet,
+		Key:        payload.Countersign,
+		Strict:     payload.Strict,
+		Normalizer: n,
 	}
 
 	if payload.TextAutoclave {
@@ -199,112 +723,510 @@ func Vigenere(s string) (string, error) {
 		c.Autokey = vigenere.KeyAutokey
 	}
 
-	if payload.Reverse {
-		return c.Decipher(payload.Message)
-	}
-	return c.Encipher(payload.Message)
+	return c, nil
 }
 
 // Beaufort cipher processing
 func Beaufort(s string) (string, error) {
+	return process(s, newBeaufort)
+}
+
+// NewBeaufort creates a cipher from a JSON payload.
+func newBeaufort(s string) (cipher, error) {
 	var payload struct {
 		pascBaseConfig
 	}
 	if err := json.Unmarshal([]byte(s), &payload); err != nil {
-		return "", err
+		return nil, err
 	}
-
-	c := beaufort.Cipher{
-		Alphabet: payload.Alphabet,
-		Key:      payload.Countersign,
-		Strict:   payload.Strict,
+	n, err := payload.normalizer()
+	if err != nil {
+		return nil, err
+	}
+	if err := payload.resolveAlphabet(); err != nil {
+		return nil, err
 	}
 
-	if payload.Reverse {
-		return c.Decipher(payload.Message)
+	c := &beaufort.Cipher{
+		Alphabet:   payload.Alphabet,
+		Key:        payload.Countersign,
+		Strict:     payload.Strict,
+		Normalizer: n,
 	}
-	return c.Encipher(payload.Message)
+	return c, nil
 }
 
 // DellaPorta cipher processing
 func DellaPorta(s string) (string, error) {
+	return process(s, newDellaPorta)
+}
+
+// NewDellaPorta creates a cipher from a JSON payload.
+func newDellaPorta(s string) (cipher, error) {
 	var payload struct {
 		pascBaseConfig
+		Keyword string `json:"keyword"`
 	}
 	if err := json.Unmarshal([]byte(s), &payload); err != nil {
-		return "", err
+		return nil, err
+	}
+	n, err := payload.normalizer()
+	if err != nil {
+		return nil, err
+	}
+	if err := payload.resolveAlphabet(); err != nil {
+		return nil, err
 	}
 
-	c := dellaporta.Cipher{
-		Alphabet: payload.Alphabet,
-		Key:      payload.Countersign,
-		Strict:   payload.Strict,
+	c := &dellaporta.Cipher{
+		Alphabet:   payload.Alphabet,
+		Key:        payload.Countersign,
+		Keyword:    payload.Keyword,
+		Strict:     payload.Strict,
+		Normalizer: n,
+	}
+	return c, nil
+}
+
+// Enigma machine processing
+func Enigma(s string) (string, error) {
+	return process(s, newEnigma)
+}
+
+// NewEnigma creates a cipher from a JSON payload.
+func newEnigma(s string) (cipher, error) {
+	var payload struct {
+		mascBaseConfig
+		Rotors    string `json:"rotors"`
+		Reflector string `json:"reflector"`
+		Rings     string `json:"rings"`
+		Positions string `json:"positions"`
+		Plugboard string `json:"plugboard"`
+	}
+	if err := json.Unmarshal([]byte(s), &payload); err != nil {
+		return nil, err
+	}
+	n, err := payload.normalizer()
+	if err != nil {
+		return nil, err
+	}
+
+	c := &enigma.Cipher{
+		Rotors:     payload.Rotors,
+		Reflector:  payload.Reflector,
+		Rings:      payload.Rings,
+		Positions:  payload.Positions,
+		Plugboard:  payload.Plugboard,
+		Strict:     payload.Strict,
+		Normalizer: n,
+	}
+	return c, nil
+}
+
+// Portax cipher processing
+func Portax(s string) (string, error) {
+	return process(s, newPortax)
+}
+
+// NewPortax creates a cipher from a JSON payload.
+func newPortax(s string) (cipher, error) {
+	var payload struct {
+		pascBaseConfig
+	}
+	if err := json.Unmarshal([]byte(s), &payload); err != nil {
+		return nil, err
+	}
+	n, err := payload.normalizer()
+	if err != nil {
+		return nil, err
+	}
+	if err := payload.resolveAlphabet(); err != nil {
+		return nil, err
+	}
+
+	c := &portax.Cipher{
+		Alphabet:   payload.Alphabet,
+		Key:        payload.Countersign,
+		Normalizer: n,
+	}
+	return c, nil
+}
+
+// Lorenz machine processing
+func Lorenz(s string) (string, error) {
+	return process(s, newLorenz)
+}
+
+// NewLorenz creates a cipher from a JSON payload.
+func newLorenz(s string) (cipher, error) {
+	var payload struct {
+		mascBaseConfig
+		Patterns   *lorenz.Patterns `json:"patterns"`
+		Positions  []int            `json:"positions"`
+		Limitation string           `json:"limitation"`
+		P5         bool             `json:"p5"`
+	}
+	if err := json.Unmarshal([]byte(s), &payload); err != nil {
+		return nil, err
+	}
+	n, err := payload.normalizer()
+	if err != nil {
+		return nil, err
+	}
+	l, ok := limitations[payload.Limitation]
+	if !ok {
+		return nil, fmt.Errorf("Unknown limitation %q", payload.Limitation)
+	}
+
+	c := &lorenz.Cipher{
+		Patterns:   payload.Patterns,
+		Positions:  payload.Positions,
+		Limitation: l,
+		P5:         payload.P5,
+		Normalizer: n,
+	}
+	return c, nil
+}
+
+// M209 machine processing
+func M209(s string) (string, error) {
+	return process(s, newM209)
+}
+
+// NewM209 creates a cipher from a JSON payload.
+func newM209(s string) (cipher, error) {
+	var payload struct {
+		mascBaseConfig
+		Pins      []string `json:"pins"`
+		Lugs      string   `json:"lugs"`
+		Positions string   `json:"positions"`
+	}
+	if err := json.Unmarshal([]byte(s), &payload); err != nil {
+		return nil, err
+	}
+	n, err := payload.normalizer()
+	if err != nil {
+		return nil, err
+	}
+
+	c := &m209.Cipher{
+		Pins:       payload.Pins,
+		Lugs:       payload.Lugs,
+		Positions:  payload.Positions,
+		Strict:     payload.Strict,
+		Normalizer: n,
+	}
+	return c, nil
+}
+
+// Checkerboard cipher processing
+func Checkerboard(s string) (string, error) {
+	return process(s, newCheckerboard)
+}
+
+// NewCheckerboard creates a cipher from a JSON payload.
+func newCheckerboard(s string) (cipher, error) {
+	var payload struct {
+		mascBaseConfig
+		Digits string `json:"digits"`
+		Blanks []int  `json:"blanks"`
+	}
+	if err := json.Unmarshal([]byte(s), &payload); err != nil {
+		return nil, err
+	}
+	n, err := payload.normalizer()
+	if err != nil {
+		return nil, err
+	}
+	if err := payload.resolveAlphabet(); err != nil {
+		return nil, err
+	}
+
+	c := &checkerboard.Cipher{
+		Alphabet:   payload.Alphabet,
+		Digits:     payload.Digits,
+		Blanks:     payload.Blanks,
+		Strict:     payload.Strict,
+		Normalizer: n,
+	}
+	return c, nil
+}
+
+// Nihilist cipher processing
+func Nihilist(s string) (string, error) {
+	return process(s, newNihilist)
+}
+
+// NewNihilist creates a cipher from a JSON payload.
+func newNihilist(s string) (cipher, error) {
+	var payload struct {
+		pascBaseConfig
+		Keyword string `json:"keyword"`
+		Groups  bool   `json:"groups"`
 	}
+	if err := json.Unmarshal([]byte(s), &payload); err != nil {
+		return nil, err
+	}
+	n, err := payload.normalizer()
+	if err != nil {
+		return nil, err
+	}
+	if err := payload.resolveAlphabet(); err != nil {
+		return nil, err
+	}
+
+	c := &nihilist.Cipher{
+		Alphabet:   payload.Alphabet,
+		Keyword:    payload.Keyword,
+		Key:        payload.Countersign,
+		Groups:     payload.Groups,
+		Normalizer: n,
+	}
+	return c, nil
+}
+
+// Polybius square processing
+func Polybius(s string) (string, error) {
+	return process(s, newPolybius)
+}
+
+// NewPolybius creates a cipher from a JSON payload.
+func newPolybius(s string) (cipher, error) {
+	var payload struct {
+		mascBaseConfig
+		RowLabels    string `json:"rowLabels"`
+		ColumnLabels string `json:"columnLabels"`
+		Merge        string `json:"merge"`
+	}
+	if err := json.Unmarshal([]byte(s), &payload); err != nil {
+		return nil, err
+	}
+	n, err := payload.normalizer()
+	if err != nil {
+		return nil, err
+	}
+	if err := payload.resolveAlphabet(); err != nil {
+		return nil, err
+	}
+
+	c := &polybius.Cipher{
+		Alphabet:     payload.Alphabet,
+		RowLabels:    payload.RowLabels,
+		ColumnLabels: payload.ColumnLabels,
+		Merge:        payload.Merge,
+		Strict:       payload.Strict,
+		Normalizer:   n,
+	}
+	return c, nil
+}
+
+// Railfence cipher processing
+func Railfence(s string) (string, error) {
+	return process(s, newRailfence)
+}
 
-	if payload.Reverse {
-		return c.Decipher(payload.Message)
+// NewRailfence creates a cipher from a JSON payload.
+func newRailfence(s string) (cipher, error) {
+	var payload struct {
+		pascBaseConfig
+		Rows   int    `json:"rows"`
+		Offset int    `json:"offset"`
+		Nulls  string `json:"nulls"`
+	}
+	if err := json.Unmarshal([]byte(s), &payload); err != nil {
+		return nil, err
+	}
+	p, err := single(payload.Nulls, "Nulls")
+	if err != nil {
+		return nil, err
+	}
+
+	c := &railfence.Cipher{
+		Rows:    payload.Rows,
+		Offset:  payload.Offset,
+		Padding: p,
+		Key:     payload.Countersign,
 	}
-	return c.Encipher(payload.Message)
+	return c, nil
+}
+
+// NihilistTransposition cipher processing
+func NihilistTransposition(s string) (string, error) {
+	return process(s, newNihilistTransposition)
+}
+
+// NewNihilistTransposition creates a cipher from a JSON payload.
+func newNihilistTransposition(s string) (cipher, error) {
+	var payload struct {
+		pascBaseConfig
+		ByColumn bool   `json:"byColumn"`
+		Nulls    string `json:"nulls"`
+	}
+	if err := json.Unmarshal([]byte(s), &payload); err != nil {
+		return nil, err
+	}
+	p, err := single(payload.Nulls, "Nulls")
+	if err != nil {
+		return nil, err
+	}
+
+	c := &nihilist.Transposition{
+		Key:      payload.Countersign,
+		ByColumn: payload.ByColumn,
+		Padding:  p,
+	}
+	return c, nil
 }
 
 // Gronsfeld cipher processing
 func Gronsfeld(s string) (string, error) {
+	return process(s, newGronsfeld)
+}
+
+// NewGronsfeld creates a cipher from a JSON payload.
+func newGronsfeld(s string) (cipher, error) {
 	var payload struct {
 		pascBaseConfig
 	}
 	if err := json.Unmarshal([]byte(s), &payload); err != nil {
-		return "", err
+		return nil, err
+	}
+	n, err := payload.normalizer()
+	if err != nil {
+		return nil, err
+	}
+	if err := payload.resolveAlphabet(); err != nil {
+		return nil, err
 	}
 
-	c := gronsfeld.Cipher{
-		Alphabet: payload.Alphabet,
-		Key:      payload.Countersign,
-		Strict:   payload.Strict,
+	c := &gronsfeld.Cipher{
+		Alphabet:   payload.Alphabet,
+		Key:        payload.Countersign,
+		Strict:     payload.Strict,
+		Normalizer: n,
 	}
+	return c, nil
+}
 
-	if payload.Reverse {
-		return c.Decipher(payload.Message)
+// Scytale cipher processing
+func Scytale(s string) (string, error) {
+	return process(s, newScytale)
+}
+
+// NewScytale creates a cipher from a JSON payload.
+func newScytale(s string) (cipher, error) {
+	var payload struct {
+		Turns int `json:"turns"`
 	}
-	return c.Encipher(payload.Message)
+	if err := json.Unmarshal([]byte(s), &payload); err != nil {
+		return nil, err
+	}
+
+	c := &scytale.Cipher{
+		Turns: payload.Turns,
+	}
+	return c, nil
+}
+
+// Solitaire cipher processing
+func Solitaire(s string) (string, error) {
+	return process(s, newSolitaire)
+}
+
+// NewSolitaire creates a cipher from a JSON payload.
+func newSolitaire(s string) (cipher, error) {
+	var payload struct {
+		mascBaseConfig
+		Countersign string         `json:"countersign"`
+		Deck        solitaire.Deck `json:"deck"`
+	}
+	if err := json.Unmarshal([]byte(s), &payload); err != nil {
+		return nil, err
+	}
+	n, err := payload.normalizer()
+	if err != nil {
+		return nil, err
+	}
+	p, err := payload.padding()
+	if err != nil {
+		return nil, err
+	}
+
+	c := &solitaire.Cipher{
+		Deck:       payload.Deck,
+		Key:        payload.Countersign,
+		Padding:    p,
+		Strict:     payload.Strict,
+		Normalizer: n,
+	}
+	return c, nil
 }
 
 // Trithemius cipher processing
 func Trithemius(s string) (string, error) {
+	return process(s, newTrithemius)
+}
+
+// NewTrithemius creates a cipher from a JSON payload.
+func newTrithemius(s string) (cipher, error) {
 	var payload struct {
-		pascBaseConfig
+		mascBaseConfig
+		Offset  int    `json:"offset"`
+		Step    int    `json:"step"`
+		Restart string `json:"restart"`
 	}
 	if err := json.Unmarshal([]byte(s), &payload); err != nil {
-		return "", err
+		return nil, err
 	}
-
-	c := trithemius.Cipher{
-		Alphabet: payload.Alphabet,
-		Strict:   payload.Strict,
+	n, err := payload.normalizer()
+	if err != nil {
+		return nil, err
+	}
+	if err := payload.resolveAlphabet(); err != nil {
+		return nil, err
+	}
+	restart, ok := restartModes[payload.Restart]
+	if !ok {
+		return nil, fmt.Errorf("Unknown restart mode %q", payload.Restart)
 	}
 
-	if payload.Reverse {
-		return c.Decipher(payload.Message)
+	c := &trithemius.Cipher{
+		Alphabet:   payload.Alphabet,
+		Offset:     payload.Offset,
+		Step:       payload.Step,
+		Restart:    restart,
+		Strict:     payload.Strict,
+		Normalizer: n,
 	}
-	return c.Encipher(payload.Message)
+	return c, nil
 }
 
 // VariantBeaufort cipher processing
 func VariantBeaufort(s string) (string, error) {
+	return process(s, newVariantBeaufort)
+}
+
+// NewVariantBeaufort creates a cipher from a JSON payload.
+func newVariantBeaufort(s string) (cipher, error) {
 	var payload struct {
 		pascBaseConfig
 	}
 	if err := json.Unmarshal([]byte(s), &payload); err != nil {
-		return "", err
+		return nil, err
 	}
-
-	c := variantbeaufort.Cipher{
-		Alphabet: payload.Alphabet,
-		Key:      payload.Countersign,
-		Strict:   payload.Strict,
+	n, err := payload.normalizer()
+	if err != nil {
+		return nil, err
+	}
+	if err := payload.resolveAlphabet(); err != nil {
+		return nil, err
 	}
 
-	if payload.Reverse {
-		return c.Decipher(payload.Message)
+	c := &variantbeaufort.Cipher{
+		Alphabet:   payload.Alphabet,
+		Key:        payload.Countersign,
+		Strict:     payload.Strict,
+		Normalizer: n,
 	}
-	return c.Encipher(payload.Message)
+	return c, nil
 }
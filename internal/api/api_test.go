@@ -0,0 +1,522 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExplain(t *testing.T) {
+	// Messages must have even length for the Portax cipher
+	const payload = `{"message": "HELLOWORLD", "countersign": "KEY", "multiplier": 1, "rows": 3, "turns": 5, "explain": true}`
+
+	// Settings for ciphers that have no usable defaults
+	extras := map[string]string{
+		"otp": `"pad": "XMCKLQWERT", `,
+		"vic": `"phrase": "I DREAM OF JEANNIE WITH THE LIGHT BROWN HAIR", "date": "741776", "personalNumber": 6, "indicator": "77651", `,
+	}
+
+	for _, name := range Ciphers() {
+		if name == "pipeline" {
+			// Pipelines compose stages, each with its own trace
+			continue
+		}
+		p, _ := Lookup(name)
+		out, err := p(strings.Replace(payload, "{", "{"+extras[name], 1))
+		if err != nil {
+			t.Errorf("Cipher %q: %v", name, err)
+			continue
+		}
+		if len(out.Trace) != len("HELLOWORLD") {
+			t.Errorf("Cipher %q: expected %d events, but got %d", name, len("HELLOWORLD"), len(out.Trace))
+		}
+	}
+}
+
+func TestExplain_unsupported(t *testing.T) {
+	ciphers["slow"] = func(s string) (cipher, error) {
+		return slowCipher(time.Millisecond), nil
+	}
+	defer delete(ciphers, "slow")
+
+	p, _ := Lookup("slow")
+	if _, err := p(`{"explain": true}`); err != ErrNoTrace {
+		t.Errorf("Expected error %v, but got %v", ErrNoTrace, err)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tables := []struct {
+		payload  string
+		expected string
+	}{
+		{`{"message": "Hello, World!", "shift": 3}`, "Kello, Zorld!"},
+		{`{"message": "Hello, World!", "shift": 3, "case": "preserve"}`, "Khoor, Zruog!"},
+		{`{"message": "Straße!", "shift": 3, "case": "upper", "stripDiacritics": true}`, "VWUDVVH!"},
+		{`{"message": "Hello, World!", "shift": 3, "case": "lower", "symbols": "drop"}`, "helloworld"},
+		{`{"message": "Hi there", "shift": 3, "case": "upper", "symbols": "replace", "symbolReplacement": "X"}`, "KLAWKHUH"},
+	}
+
+	p, _ := Lookup("caesar")
+	for _, table := range tables {
+		if out, err := p(table.payload); err != nil {
+			t.Errorf("Payload %s: %v", table.payload, err)
+		} else if out.Message != table.expected {
+			t.Errorf("Payload %s: expected %q, but got %q", table.payload, table.expected, out.Message)
+		}
+	}
+
+	if _, err := p(`{"message": "A", "case": "title"}`); err == nil {
+		t.Error("Expected error for unknown case mode")
+	}
+}
+
+func TestAlphabet(t *testing.T) {
+	tables := []struct {
+		cipher   string
+		payload  string
+		expected string
+	}{
+		{"caesar", `{"message": "ΑΒΓ", "shift": 1, "alphabet": "greek"}`, "ΒΓΔ"},
+		{"caesar", `{"message": "HIJ", "shift": 1, "alphabet": "latin25"}`, "IKJ"},
+		{"atbash", `{"message": "AZ09", "alphabet": "alphanumeric"}`, "9KJA"},
+		{"vigenere", `{"message": "ПРИВЕТ", "countersign": "КЛЮЧ", "alphabet": "cyrillic"}`, "ЪЬЖЩПЮ"},
+	}
+
+	for _, table := range tables {
+		p, _ := Lookup(table.cipher)
+		if out, err := p(table.payload); err != nil {
+			t.Errorf("Payload %s: %v", table.payload, err)
+		} else if out.Message != table.expected {
+			t.Errorf("Payload %s: expected %q, but got %q", table.payload, table.expected, out.Message)
+		}
+	}
+
+	p, _ := Lookup("caesar")
+	if _, err := p(`{"message": "A", "alphabet": "ABCA"}`); err == nil {
+		t.Error("Expected error for alphabet with duplicate characters")
+	}
+}
+
+func TestTrithemius(t *testing.T) {
+	p, _ := Lookup("trithemius")
+
+	out, err := p(`{"message": "HELLO WORLD", "step": 3, "restart": "word"}`)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if out.Message != "HHRUA WRXUP" {
+		t.Errorf("Expected %q, but got %q", "HHRUA WRXUP", out.Message)
+	}
+
+	if _, err := p(`{"message": "A", "restart": "sentence"}`); err == nil {
+		t.Error("Expected error for unknown restart mode")
+	}
+}
+
+func TestRailfence(t *testing.T) {
+	p, _ := Lookup("railfence")
+
+	out, err := p(`{"message": "WEAREDISCOVEREDFLEEATONCE", "rows": 3, "nulls": "X"}`)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if out.Message != "WECRLTEERDSOEEFEAOCXXAIVDENX" {
+		t.Errorf("Expected %q, but got %q", "WECRLTEERDSOEEFEAOCXXAIVDENX", out.Message)
+	}
+
+	out, err = p(`{"message": "ERDSOEEFEAOCAIVDENWECRLTE", "rows": 3, "countersign": "312", "reverse": true}`)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if out.Message != "WEAREDISCOVEREDFLEEATONCE" {
+		t.Errorf("Expected %q, but got %q", "WEAREDISCOVEREDFLEEATONCE", out.Message)
+	}
+
+	out, err = p(`{"message": "WEAREDISCOVEREDFLEEATONCE", "rows": 3, "nulls": "X", "groupSize": 5, "padding": "Q"}`)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if out.Message != "WECRL TEERD SOEEF EAOCX XAIVD ENXQQ" {
+		t.Errorf("Expected %q, but got %q", "WECRL TEERD SOEEF EAOCX XAIVD ENXQQ", out.Message)
+	}
+
+	out, err = p(`{"message": "WECRL TEERD SOEEF EAOCX XAIVD ENX", "rows": 3, "groupSize": 5, "reverse": true}`)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if out.Message != "WEAREDISCOVEREDFLEEATONCEXXX" {
+		t.Errorf("Expected %q, but got %q", "WEAREDISCOVEREDFLEEATONCEXXX", out.Message)
+	}
+
+	if _, err := p(`{"message": "A"}`); err == nil {
+		t.Error("Expected error for missing rows")
+	}
+	if _, err := p(`{"message": "A", "rows": 3, "nulls": "XY"}`); err == nil {
+		t.Error("Expected error for multiple nulls")
+	}
+}
+
+func TestPolybius(t *testing.T) {
+	p, _ := Lookup("polybius")
+
+	out, err := p(`{"message": "Hello, world", "case": "upper", "strict": true, "groupSize": 5}`)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if out.Message != "23153 13134 52344 23114" {
+		t.Errorf("Expected %q, but got %q", "23153 13134 52344 23114", out.Message)
+	}
+
+	out, err = p(`{"message": "DFAXFAFAFG", "rowLabels": "ADFGX", "columnLabels": "ADFGX", "reverse": true}`)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if out.Message != "HELLO" {
+		t.Errorf("Expected %q, but got %q", "HELLO", out.Message)
+	}
+}
+
+func TestEnigma(t *testing.T) {
+	p, _ := Lookup("enigma")
+
+	out, err := p(`{"message": "aaaaa aaaaa", "case": "upper", "strict": true, "groupSize": 5}`)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if out.Message != "BDZGO WCXLT" {
+		t.Errorf("Expected %q, but got %q", "BDZGO WCXLT", out.Message)
+	}
+
+	out, err = p(`{"message": "BDZGO WCXLT", "rotors": "I II III", "reflector": "B", "rings": "01 01 01", "positions": "AAA", "groupSize": 5, "reverse": true}`)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if out.Message != "AAAAAAAAAA" {
+		t.Errorf("Expected %q, but got %q", "AAAAAAAAAA", out.Message)
+	}
+}
+
+func TestM209(t *testing.T) {
+	p, _ := Lookup("m209")
+
+	const settings = `"pins": ["ABDHIKMNSTVW", "ADEGJKLORSUX", "ABGHJLMNRSTUX", "CEFHIMNPSTU", "BDEFHIMNPS", "ABDHKNOQ"], "lugs": "3-6 0-6 1-6 1-5 4-5 0-4*4 2-0*10 2-5*2 0-5*6", "positions": "GAQLMB"`
+	out, err := p(`{"message": "attack at dawn", "case": "upper", "groupSize": 5, ` + settings + `}`)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if out.Message != "ZSOAR YVCBN BYGV" {
+		t.Errorf("Expected %q, but got %q", "ZSOAR YVCBN BYGV", out.Message)
+	}
+
+	out, err = p(`{"message": "ZSOAR YVCBN BYGV", "groupSize": 5, "reverse": true, ` + settings + `}`)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if out.Message != "ATTACK AT DAWN" {
+		t.Errorf("Expected %q, but got %q", "ATTACK AT DAWN", out.Message)
+	}
+}
+
+func TestSolitaire(t *testing.T) {
+	p, _ := Lookup("solitaire")
+
+	out, err := p(`{"message": "solitaire", "countersign": "cryptonomicon", "case": "upper", "padding": "X", "groupSize": 5}`)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if out.Message != "KIRAK SFJAN" {
+		t.Errorf("Expected %q, but got %q", "KIRAK SFJAN", out.Message)
+	}
+
+	out, err = p(`{"message": "EXKYI", "deck": ["AC", "2C", "3C", "4C", "5C", "6C", "7C", "8C", "9C", "10C", "JC", "QC", "KC", "AD", "2D", "3D", "4D", "5D", "6D", "7D", "8D", "9D", "10D", "JD", "QD", "KD", "AH", "2H", "3H", "4H", "5H", "6H", "7H", "8H", "9H", "10H", "JH", "QH", "KH", "AS", "2S", "3S", "4S", "5S", "6S", "7S", "8S", "9S", "10S", "JS", "QS", "KS", "A", "B"], "reverse": true}`)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if out.Message != "AAAAA" {
+		t.Errorf("Expected %q, but got %q", "AAAAA", out.Message)
+	}
+}
+
+func TestOTP(t *testing.T) {
+	p, _ := Lookup("otp")
+
+	tables := []struct {
+		payload  string
+		expected string
+	}{
+		{`{"message": "hello", "pad": "XMCKL", "case": "upper"}`, "EQNVZ"},
+		{`{"message": "EQNVZ", "pad": "XMCKL", "reverse": true}`, "HELLO"},
+		{`{"message": "HELLO", "pad": "XMCKL", "combination": "beaufort"}`, "QIRZX"},
+		{`{"message": "QIRZX", "pad": "XMCKL", "combination": "beaufort", "reverse": true}`, "HELLO"},
+	}
+	for _, table := range tables {
+		if out, err := p(table.payload); err != nil {
+			t.Errorf("Payload %s: %v", table.payload, err)
+		} else if out.Message != table.expected {
+			t.Errorf("Payload %s: expected %q, but got %q", table.payload, table.expected, out.Message)
+		}
+	}
+
+	if _, err := p(`{"message": "HELLO", "pad": "XMCKL", "combination": "variant"}`); err == nil {
+		t.Error("Expected error for unknown combination")
+	}
+	if _, err := p(`{"message": "HELLO", "pad": "XMCK"}`); err == nil {
+		t.Error("Expected error for short pad")
+	}
+}
+
+func TestCheckerboard(t *testing.T) {
+	p, _ := Lookup("checkerboard")
+
+	tables := []struct {
+		payload  string
+		expected string
+	}{
+		{`{"message": "attack at dawn", "case": "upper", "symbols": "drop", "groupSize": 5}`, "31132 12731 22365 5"},
+		{`{"message": "31132 12731 22365 5", "groupSize": 5, "reverse": true}`, "ATTACKATDAWN"},
+		{`{"message": "MEET AT 10.", "alphabet": "ATONESIRBCDFGHJKLMPQUVWXYZ.0123456789", "digits": "9876543210", "blanks": [8, 1, 4]}`, "59337 97 181950"},
+	}
+	for _, table := range tables {
+		if out, err := p(table.payload); err != nil {
+			t.Errorf("Payload %s: %v", table.payload, err)
+		} else if out.Message != table.expected {
+			t.Errorf("Payload %s: expected %q, but got %q", table.payload, table.expected, out.Message)
+		}
+	}
+
+	if _, err := p(`{"message": "HELLO", "blanks": [10]}`); err == nil {
+		t.Error("Expected error for blank out of range")
+	}
+}
+
+func TestVIC(t *testing.T) {
+	p, _ := Lookup("vic")
+
+	const settings = `"phrase": "I DREAM OF JEANNIE WITH THE LIGHT BROWN HAIR", "date": "741776", "personalNumber": 6`
+	tables := []struct {
+		payload  string
+		expected string
+	}{
+		{`{"message": "Hello world", "case": "upper", "symbols": "drop", "indicator": "77651", "groupSize": 5, ` + settings + `}`, "77651 08744 17030 37006 0"},
+		{`{"message": "77651 08744 17030 37006 0", "groupSize": 5, "reverse": true, ` + settings + `}`, "HELLOWORLD"},
+		{`{"message": "Meet me at noon, ok?", "case": "upper", "indicator": "77651", ` + settings + `}`, "7765105232600411933194"},
+	}
+	for _, table := range tables {
+		if out, err := p(table.payload); err != nil {
+			t.Errorf("Payload %s: %v", table.payload, err)
+		} else if out.Message != table.expected {
+			t.Errorf("Payload %s: expected %q, but got %q", table.payload, table.expected, out.Message)
+		}
+	}
+
+	if _, err := p(`{"message": "HELLO", "indicator": "7765", ` + settings + `}`); err == nil {
+		t.Error("Expected error for short indicator")
+	}
+	if _, err := p(`{"message": "77651 08744 17030 37006 0", "reverse": true, ` + settings + `}`); err == nil {
+		t.Error("Expected error for non-digit ciphertext")
+	}
+}
+
+func TestNihilist(t *testing.T) {
+	p, _ := Lookup("nihilist")
+
+	tables := []struct {
+		payload  string
+		expected string
+	}{
+		{`{"message": "dynamite winter palace", "case": "upper", "keyword": "ZEBRAS", "countersign": "RUSSIAN"}`, "37 106 62 36 67 47 86 26 104 53 62 77 27 55 57 66 55 36 54 27"},
+		{`{"message": "DYNAMITE WINTER PALACE", "keyword": "ZEBRAS", "countersign": "RUSSIAN", "groups": true, "groupSize": 5}`, "37066 23667 47862 60453 62772 75557 66553 65427"},
+		{`{"message": "37066 23667 47862 60453 62772 75557 66553 65427", "keyword": "ZEBRAS", "countersign": "RUSSIAN", "groups": true, "groupSize": 5, "reverse": true}`, "DYNAMITEWINTERPALACE"},
+	}
+	for _, table := range tables {
+		if out, err := p(table.payload); err != nil {
+			t.Errorf("Payload %s: %v", table.payload, err)
+		} else if out.Message != table.expected {
+			t.Errorf("Payload %s: expected %q, but got %q", table.payload, table.expected, out.Message)
+		}
+	}
+
+	p, _ = Lookup("nihilisttransposition")
+	tables = []struct {
+		payload  string
+		expected string
+	}{
+		{`{"message": "WEAREDISCOVEREDFLEEATONCE", "countersign": "ZEBRA", "byColumn": true, "groupSize": 5}`, "EDOAE NRSEA OEILE CECER TVDFW"},
+		{`{"message": "ABCDEFG", "countersign": "CAB", "nulls": "X"}`, "EFDXXGBCA"},
+		{`{"message": "EFDXXGBCA", "countersign": "CAB", "reverse": true}`, "ABCDEFGXX"},
+	}
+	for _, table := range tables {
+		if out, err := p(table.payload); err != nil {
+			t.Errorf("Payload %s: %v", table.payload, err)
+		} else if out.Message != table.expected {
+			t.Errorf("Payload %s: expected %q, but got %q", table.payload, table.expected, out.Message)
+		}
+	}
+}
+
+func TestHomophonic(t *testing.T) {
+	p, _ := Lookup("homophonic")
+
+	tables := []struct {
+		payload  string
+		expected string
+	}{
+		{`{"message": "attack at dawn", "case": "upper", "symbols": "drop", "groupSize": 5}`, "00194 12602 10455 60358 2213"},
+		{`{"message": "00194 12602 10455 60358 2213", "groupSize": 5, "reverse": true}`, "ATTACKATDAWN"},
+		{`{"message": "ATTACK AT DAWN", "selection": "random", "seed": 1}`, "269156582710 2696 03692236"},
+		{`{"message": "BEAD A DEED", "table": {"A": ["#", "$"], "B": ["("], "C": ["@", ")"], "D": ["*", "!"], "E": ["%", "&", "^"]}}`, "(%#* $ !&^*"},
+		{`{"message": "BAD", "alphabet": "ABCDE", "codeSymbols": "!@#$%^&*()", "keyword": "SECRET"}`, "(#*"},
+	}
+	for _, table := range tables {
+		if out, err := p(table.payload); err != nil {
+			t.Errorf("Payload %s: %v", table.payload, err)
+		} else if out.Message != table.expected {
+			t.Errorf("Payload %s: expected %q, but got %q", table.payload, table.expected, out.Message)
+		}
+	}
+
+	if _, err := p(`{"message": "HELLO", "selection": "sequential"}`); err == nil {
+		t.Error("Expected error for unknown selection")
+	}
+}
+
+func TestVernam(t *testing.T) {
+	p, _ := Lookup("vernam")
+
+	tables := []struct {
+		payload  string
+		expected string
+	}{
+		{`{"message": "attack at dawn", "countersign": "vernam", "groupSize": 5}`, "XZGKF WG4G3 R8FF"},
+		{`{"message": "XZGKF WG4G3 R8FF", "countersign": "VERNAM", "groupSize": 5, "reverse": true}`, "ATTACK AT DAWN"},
+		{`{"message": "HELLO WORLD", "generator": "lcg", "modulus": 1024, "multiplier": 5, "increment": 1, "seed": 7}`, "3SUOE9AHYY8"},
+		{`{"message": "PJ9AYAJ48GT", "generator": "alfg", "modulus": 32, "seeds": [1, 2, 3, 4, 5, 6, 7], "taps": [1, 7], "reverse": true}`, "HELLO WORLD"},
+	}
+	for _, table := range tables {
+		if out, err := p(table.payload); err != nil {
+			t.Errorf("Payload %s: %v", table.payload, err)
+		} else if out.Message != table.expected {
+			t.Errorf("Payload %s: expected %q, but got %q", table.payload, table.expected, out.Message)
+		}
+	}
+
+	if _, err := p(`{"message": "HELLO", "generator": "xorshift"}`); err == nil {
+		t.Error("Expected error for unknown generator")
+	}
+	if _, err := p(`{"message": "HELLO", "generator": "alfg", "seeds": [1], "taps": [1]}`); err == nil {
+		t.Error("Expected error for missing modulus")
+	}
+	if _, err := p(`{"message": "HELLO", "generator": "mlfg", "modulus": 32}`); err == nil {
+		t.Error("Expected error for missing seeds")
+	}
+}
+
+func TestLorenz(t *testing.T) {
+	p, _ := Lookup("lorenz")
+
+	tables := []struct {
+		payload  string
+		expected string
+	}{
+		{`{"message": "hello world", "groupSize": 5}`, "MI3RS X54D5 E"},
+		{`{"message": "MI3RS X54D5 E", "groupSize": 5, "reverse": true}`, "HELLO WORLD"},
+		{`{"message": "HELLO WORLD", "limitation": "chi2"}`, "MXWZXAM5PCH"},
+		{`{"message": "MI3ZXAM5T4O", "limitation": "chi2psi1", "p5": true, "positions": [1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1], "reverse": true}`, "HELLO WORLD"},
+	}
+	for _, table := range tables {
+		if out, err := p(table.payload); err != nil {
+			t.Errorf("Payload %s: %v", table.payload, err)
+		} else if out.Message != table.expected {
+			t.Errorf("Payload %s: expected %q, but got %q", table.payload, table.expected, out.Message)
+		}
+	}
+
+	if _, err := p(`{"message": "HELLO", "limitation": "chi3"}`); err == nil {
+		t.Error("Expected error for unknown limitation")
+	}
+}
+
+func TestFormat(t *testing.T) {
+	p, _ := Lookup("vigenere")
+
+	out, err := p(`{"message": "ATTACK AT DAWN", "countersign": "LEMON", "groupSize": 5, "groupsPerLine": 2, "padding": "X"}`)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if out.Message != "LXFOP VEFRN\nHRXXX" {
+		t.Errorf("Expected %q, but got %q", "LXFOP VEFRN\nHRXXX", out.Message)
+	}
+
+	out, err = p(`{"message": "LXFOP VEFRN\nHR", "countersign": "LEMON", "groupSize": 5, "reverse": true}`)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if out.Message != "ATTACKATDAWN" {
+		t.Errorf("Expected %q, but got %q", "ATTACKATDAWN", out.Message)
+	}
+}
+
+func TestPipeline(t *testing.T) {
+	const payload = `{"pipeline": [{"cipher": "keyword", "keyword": "KANGAROO"}, {"cipher": "caesar", "shift": 3}], "message": "HELLO"}`
+
+	p, _ := Lookup("pipeline")
+	out, err := p(payload)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if out.Message != "FUKKO" {
+		t.Errorf("Expected %q, but got %q", "FUKKO", out.Message)
+	}
+
+	out, err = p(`{"pipeline": [{"cipher": "keyword", "keyword": "KANGAROO"}, {"cipher": "caesar", "shift": 3}], "message": "FUKKO", "reverse": true}`)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if out.Message != "HELLO" {
+		t.Errorf("Expected %q, but got %q", "HELLO", out.Message)
+	}
+
+	for _, bad := range []string{
+		`{"pipeline": [], "message": "HELLO"}`,
+		`{"pipeline": [{"shift": 3}], "message": "HELLO"}`,
+		`{"pipeline": [{"cipher": "nonesuch"}], "message": "HELLO"}`,
+	} {
+		if _, err := p(bad); err == nil {
+			t.Errorf("Expected error for payload %s", bad)
+		}
+	}
+}
+
+func TestStage_MarshalJSON(t *testing.T) {
+	var p Pipeline
+	if err := json.Unmarshal([]byte(`[{"cipher": "caesar", "shift": 3}]`), &p); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(p) != 1 || p[0].Cipher != "caesar" || string(p[0].Params["shift"]) != "3" {
+		t.Errorf("Unexpected pipeline %+v", p)
+	}
+
+	b, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if string(b) != `[{"cipher":"caesar","shift":3}]` {
+		t.Errorf("Expected %s, but got %s", `[{"cipher":"caesar","shift":3}]`, b)
+	}
+}
@@ -0,0 +1,223 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/merenbach/goldbug/internal/trace"
+)
+
+// Defaults for batch processing.
+const (
+	DefaultBatchSize    = 100
+	DefaultBatchWorkers = 8
+	DefaultBatchTimeout = time.Second
+)
+
+// A Job describes a single cipher operation within a batch.
+type Job struct {
+	Cipher  string          `json:"cipher"`
+	Params  json.RawMessage `json:"params"`
+	Message string          `json:"message"`
+	Reverse bool            `json:"reverse"`
+}
+
+// A Result holds the outcome of a single job within a batch.
+type Result struct {
+	Message string      `json:"message"`
+	Trace   trace.Trace `json:"trace,omitempty"`
+	Error   string      `json:"error,omitempty"`
+}
+
+// A Batch processes many jobs concurrently with a bounded worker pool.
+type Batch struct {
+	// MaxSize is the maximum number of jobs accepted in one batch.
+	MaxSize int
+
+	// Workers is the maximum number of jobs to process at once.
+	Workers int
+
+	// Timeout is the maximum time allowed for each job.
+	Timeout time.Duration
+
+	// Lookup finds the processor for each job, defaulting to the package Lookup.
+	Lookup func(name string) (Processor, bool)
+}
+
+// BatchFromEnv configures batch processing from environment variables.
+// BatchFromEnv reads BATCH_MAX_SIZE, BATCH_WORKERS, and BATCH_TIMEOUT, using defaults for any unset.
+func BatchFromEnv() Batch {
+	var b Batch
+	if v, err := strconv.Atoi(os.Getenv("BATCH_MAX_SIZE")); err == nil {
+		b.MaxSize = v
+	}
+	if v, err := strconv.Atoi(os.Getenv("BATCH_WORKERS")); err == nil {
+		b.Workers = v
+	}
+	if v, err := time.ParseDuration(os.Getenv("BATCH_TIMEOUT")); err == nil {
+		b.Timeout = v
+	}
+	return b
+}
+
+// Payload merges job parameters with the message and direction.
+func (j *Job) payload() (string, error) {
+	fields := make(map[string]json.RawMessage)
+	if len(j.Params) > 0 && string(j.Params) != "null" {
+		if err := json.Unmarshal(j.Params, &fields); err != nil {
+			return "", err
+		}
+	}
+
+	msg, err := json.Marshal(j.Message)
+	if err != nil {
+		return "", err
+	}
+	rev, err := json.Marshal(j.Reverse)
+	if err != nil {
+		return "", err
+	}
+	fields["message"], fields["reverse"] = msg, rev
+
+	out, err := json.Marshal(fields)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// ErrNoWorker is returned when a batch job waits too long for a cipher from an earlier job to finish.
+var ErrNoWorker = errors.New("No worker available")
+
+// Run a job, giving up if it runs longer than the timeout.
+// A cipher goroutine holds a slot in the in-flight semaphore until it finishes,
+// even after a timeout, so abandoned work never exceeds the semaphore capacity.
+// The job waits up to the timeout for a free slot before its own timer starts.
+func (j *Job) run(ctx context.Context, timeout time.Duration, lookup func(string) (Processor, bool), inflight chan struct{}) (*Output, error) {
+	p, ok := lookup(j.Cipher)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownCipher, j.Cipher)
+	}
+
+	s, err := j.payload()
+	if err != nil {
+		return nil, err
+	}
+
+	type outcome struct {
+		out *Output
+		err error
+	}
+	wait := time.NewTimer(timeout)
+	select {
+	case inflight <- struct{}{}:
+		wait.Stop()
+	case <-wait.C:
+		return nil, ErrNoWorker
+	case <-ctx.Done():
+		wait.Stop()
+		return nil, ctx.Err()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// Buffered so that the goroutine can finish after a timeout
+	done := make(chan outcome, 1)
+	go func() {
+		defer func() { <-inflight }()
+		defer func() {
+			if r := recover(); r != nil {
+				done <- outcome{nil, fmt.Errorf("Cipher %q failed: %v", j.Cipher, r)}
+			}
+		}()
+		out, err := p(s)
+		done <- outcome{out, err}
+	}()
+
+	select {
+	case o := <-done:
+		return o.out, o.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Run a batch of jobs described by a JSON array.
+// Run returns results in the same order as the jobs.
+func (b *Batch) Run(ctx context.Context, s string) ([]Result, error) {
+	var jobs []Job
+	if err := json.Unmarshal([]byte(s), &jobs); err != nil {
+		return nil, err
+	}
+
+	maxSize := b.MaxSize
+	if maxSize <= 0 {
+		maxSize = DefaultBatchSize
+	}
+	if len(jobs) > maxSize {
+		return nil, fmt.Errorf("Batch size %d exceeds maximum of %d", len(jobs), maxSize)
+	}
+
+	workers := b.Workers
+	if workers <= 0 {
+		workers = DefaultBatchWorkers
+	}
+	timeout := b.Timeout
+	if timeout <= 0 {
+		timeout = DefaultBatchTimeout
+	}
+	lookup := b.Lookup
+	if lookup == nil {
+		lookup = Lookup
+	}
+
+	results := make([]Result, len(jobs))
+	indices := make(chan int)
+	inflight := make(chan struct{}, workers)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				out, err := jobs[i].run(ctx, timeout, lookup, inflight)
+
+				if err != nil {
+					results[i].Error = err.Error()
+				} else {
+					results[i].Message, results[i].Trace = out.Message, out.Trace
+				}
+			}
+		}()
+	}
+
+	for i := range jobs {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+
+	return results, nil
+}
@@ -0,0 +1,228 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBatch_Run(t *testing.T) {
+	const jobs = `[
+		{"cipher": "caesar", "params": {"shift": 3}, "message": "HELLO"},
+		{"cipher": "caesar", "params": {"shift": 3}, "message": "KHOOR", "reverse": true},
+		{"cipher": "nonesuch", "message": "HELLO"},
+		{"cipher": "rot13", "message": "URYYB"},
+		{"cipher": "vigenere", "params": {"countersign": "KEY", "textAutoclave": true, "keyAutoclave": true}, "message": "HELLO"}
+	]`
+
+	expected := []struct {
+		message string
+		failed  bool
+	}{
+		{"KHOOR", false},
+		{"HELLO", false},
+		{"", true},
+		{"HELLO", false},
+		{"", true},
+	}
+
+	b := Batch{Workers: 2}
+	results, err := b.Run(context.Background(), jobs)
+	if err != nil {
+		t.Fatal("Could not run batch:", err)
+	}
+	if len(results) != len(expected) {
+		t.Fatalf("Expected %d results, but got %d", len(expected), len(results))
+	}
+	for i, e := range expected {
+		if results[i].Message != e.message {
+			t.Errorf("Expected job %d to produce %q, but got %q", i, e.message, results[i].Message)
+		}
+		if (results[i].Error != "") != e.failed {
+			t.Errorf("Expected job %d failure to be %t, but got error %q", i, e.failed, results[i].Error)
+		}
+	}
+}
+
+func TestBatch_RunMaxSize(t *testing.T) {
+	b := Batch{MaxSize: 1}
+	if _, err := b.Run(context.Background(), `[{"cipher": "rot13"}, {"cipher": "rot13"}]`); err == nil {
+		t.Error("Expected oversized batch to fail")
+	}
+}
+
+// Lookup processors from a fixed set, falling back to the registry.
+func lookupWith(extra map[string]func(string) (cipher, error)) func(string) (Processor, bool) {
+	return func(name string) (Processor, bool) {
+		if f, ok := extra[name]; ok {
+			return func(s string) (*Output, error) {
+				return run(s, f)
+			}, true
+		}
+		return Lookup(name)
+	}
+}
+
+func TestBatch_RunTimeout(t *testing.T) {
+	b := Batch{
+		Timeout: 10 * time.Millisecond,
+		Lookup: lookupWith(map[string]func(string) (cipher, error){
+			"slow": func(s string) (cipher, error) {
+				return slowCipher(time.Second), nil
+			},
+		}),
+	}
+	results, err := b.Run(context.Background(), `[{"cipher": "slow"}, {"cipher": "rot13", "message": "URYYB"}]`)
+	if err != nil {
+		t.Fatal("Could not run batch:", err)
+	}
+	if results[0].Error == "" {
+		t.Error("Expected slow job to time out")
+	}
+	if results[1].Message != "HELLO" || results[1].Error != "" {
+		t.Errorf("Expected fast job to succeed, but got %+v", results[1])
+	}
+}
+
+func TestBatch_RunPanic(t *testing.T) {
+	b := Batch{
+		Lookup: lookupWith(map[string]func(string) (cipher, error){
+			"panicky": func(s string) (cipher, error) {
+				return panickyCipher{}, nil
+			},
+		}),
+	}
+	results, err := b.Run(context.Background(), `[{"cipher": "panicky"}, {"cipher": "rot13", "message": "URYYB"}]`)
+	if err != nil {
+		t.Fatal("Could not run batch:", err)
+	}
+	if results[0].Error == "" {
+		t.Error("Expected panicking job to fail")
+	}
+	if results[1].Message != "HELLO" || results[1].Error != "" {
+		t.Errorf("Expected fast job to succeed, but got %+v", results[1])
+	}
+}
+
+func TestBatch_RunInflight(t *testing.T) {
+	var running, peak int32
+	b := Batch{
+		Workers: 2,
+		Timeout: time.Millisecond,
+		Lookup: lookupWith(map[string]func(string) (cipher, error){
+			"slow": func(s string) (cipher, error) {
+				return countingCipher{&running, &peak, 20 * time.Millisecond}, nil
+			},
+		}),
+	}
+	if _, err := b.Run(context.Background(), `[{"cipher": "slow"}, {"cipher": "slow"}, {"cipher": "slow"}, {"cipher": "slow"}, {"cipher": "slow"}, {"cipher": "slow"}]`); err != nil {
+		t.Fatal("Could not run batch:", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if p := atomic.LoadInt32(&peak); p > 2 {
+		t.Errorf("Expected at most 2 ciphers in flight, but got %d", p)
+	}
+}
+
+func TestBatch_RunQueued(t *testing.T) {
+	tables := []struct {
+		hang   time.Duration
+		failed error
+	}{
+		// The hung ciphers finish while the fast job waits for a slot
+		{70 * time.Millisecond, nil},
+		// The hung ciphers outlast the wait
+		{time.Second, ErrNoWorker},
+	}
+
+	for _, table := range tables {
+		hang := table.hang
+		b := Batch{
+			Workers: 2,
+			Timeout: 50 * time.Millisecond,
+			Lookup: lookupWith(map[string]func(string) (cipher, error){
+				"slow": func(s string) (cipher, error) {
+					return slowCipher(hang), nil
+				},
+			}),
+		}
+		results, err := b.Run(context.Background(), `[{"cipher": "slow"}, {"cipher": "slow"}, {"cipher": "rot13", "message": "URYYB"}]`)
+		if err != nil {
+			t.Fatal("Could not run batch:", err)
+		}
+		for i := 0; i < 2; i++ {
+			if results[i].Error != context.DeadlineExceeded.Error() {
+				t.Errorf("Expected hung job %d to time out, but got %+v", i, results[i])
+			}
+		}
+		if table.failed == nil {
+			if results[2].Message != "HELLO" || results[2].Error != "" {
+				t.Errorf("Expected queued job to succeed after %v, but got %+v", hang, results[2])
+			}
+		} else if results[2].Error != table.failed.Error() {
+			t.Errorf("Expected queued job to fail with %q after %v, but got %+v", table.failed, hang, results[2])
+		}
+	}
+}
+
+// A slowCipher takes a fixed amount of time to do nothing.
+type slowCipher time.Duration
+
+func (c slowCipher) Encipher(s string) (string, error) {
+	time.Sleep(time.Duration(c))
+	return s, nil
+}
+
+func (c slowCipher) Decipher(s string) (string, error) {
+	time.Sleep(time.Duration(c))
+	return s, nil
+}
+
+// A panickyCipher panics on every operation.
+type panickyCipher struct{}
+
+func (panickyCipher) Encipher(s string) (string, error) {
+	panic("boom")
+}
+
+func (panickyCipher) Decipher(s string) (string, error) {
+	panic("boom")
+}
+
+// A countingCipher records the peak number of concurrent operations.
+type countingCipher struct {
+	running, peak *int32
+	d             time.Duration
+}
+
+func (c countingCipher) Encipher(s string) (string, error) {
+	n := atomic.AddInt32(c.running, 1)
+	defer atomic.AddInt32(c.running, -1)
+	for {
+		p := atomic.LoadInt32(c.peak)
+		if n <= p || atomic.CompareAndSwapInt32(c.peak, p, n) {
+			break
+		}
+	}
+	time.Sleep(c.d)
+	return s, nil
+}
+
+func (c countingCipher) Decipher(s string) (string, error) {
+	return c.Encipher(s)
+}
@@ -0,0 +1,109 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/merenbach/goldbug/pkg/pipeline"
+)
+
+// Registered at initialization, since building a pipeline refers back to the cipher registry.
+func init() {
+	ciphers["pipeline"] = newPipeline
+	params["pipeline"] = []Param{{Name: "pipeline", Type: jsonParam, Default: "[]"}}
+}
+
+// A Stage of a pipeline names a cipher alongside its settings.
+// A stage is represented in JSON as a single object, such as {"cipher": "caesar", "shift": 3}.
+type Stage struct {
+	Cipher string
+	Params map[string]json.RawMessage
+}
+
+// MarshalJSON merges the cipher name into the settings.
+func (s Stage) MarshalJSON() ([]byte, error) {
+	fields := make(map[string]json.RawMessage, len(s.Params)+1)
+	for k, v := range s.Params {
+		fields[k] = v
+	}
+
+	name, err := json.Marshal(s.Cipher)
+	if err != nil {
+		return nil, err
+	}
+	fields["cipher"] = name
+	return json.Marshal(fields)
+}
+
+// UnmarshalJSON separates the cipher name from the settings.
+func (s *Stage) UnmarshalJSON(b []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+
+	name, ok := fields["cipher"]
+	if !ok {
+		return errors.New("Pipeline stage must name a cipher")
+	}
+	if err := json.Unmarshal(name, &s.Cipher); err != nil {
+		return err
+	}
+	delete(fields, "cipher")
+	s.Params = fields
+	return nil
+}
+
+// A Pipeline describes a sequence of cipher stages.
+// Text normalization applies within each stage, while formatting and explanations apply only to the pipeline as a whole.
+type Pipeline []Stage
+
+// Build a composite cipher from this description.
+func (p Pipeline) build() (pipeline.Pipeline, error) {
+	if len(p) == 0 {
+		return nil, errors.New("Pipeline must have at least one stage")
+	}
+
+	out := make(pipeline.Pipeline, len(p))
+	for i, stage := range p {
+		f, ok := ciphers[stage.Cipher]
+		if !ok {
+			return nil, fmt.Errorf("Stage %d: %w: %q", i+1, ErrUnknownCipher, stage.Cipher)
+		}
+
+		s, err := json.Marshal(stage.Params)
+		if err != nil {
+			return nil, err
+		}
+		if out[i], err = f(string(s)); err != nil {
+			return nil, fmt.Errorf("Stage %d: %w", i+1, err)
+		}
+	}
+	return out, nil
+}
+
+// NewPipeline creates a cipher from a JSON payload.
+func newPipeline(s string) (cipher, error) {
+	var payload struct {
+		Pipeline Pipeline `json:"pipeline"`
+	}
+	if err := json.Unmarshal([]byte(s), &payload); err != nil {
+		return nil, err
+	}
+	return payload.Pipeline.build()
+}
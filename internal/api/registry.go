@@ -0,0 +1,240 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"sort"
+
+	"github.com/merenbach/goldbug/internal/alphabet"
+)
+
+// A Processor runs a cipher operation described by a JSON payload.
+type Processor func(string) (*Output, error)
+
+// Constructors for each supported cipher, keyed by route name.
+var ciphers = map[string]func(string) (cipher, error){
+	"affine":                newAffine,
+	"atbash":                newAtbash,
+	"beaufort":              newBeaufort,
+	"caesar":                newCaesar,
+	"chaocipher":            newChaocipher,
+	"checkerboard":          newCheckerboard,
+	"decimation":            newDecimation,
+	"dellaporta":            newDellaPorta,
+	"enigma":                newEnigma,
+	"gronsfeld":             newGronsfeld,
+	"homophonic":            newHomophonic,
+	"keyword":               newKeyword,
+	"lorenz":                newLorenz,
+	"m209":                  newM209,
+	"nihilist":              newNihilist,
+	"nihilisttransposition": newNihilistTransposition,
+	"otp":                   newOTP,
+	"polybius":              newPolybius,
+	"portax":                newPortax,
+	"railfence":             newRailfence,
+	"rot13":                 newRot13,
+	"scytale":               newScytale,
+	"solitaire":             newSolitaire,
+	"trithemius":            newTrithemius,
+	"variantbeaufort":       newVariantBeaufort,
+	"vernam":                newVernam,
+	"vic":                   newVIC,
+	"vigenere":              newVigenere,
+}
+
+// A Param describes a cipher setting accepted in a JSON payload.
+type Param struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Default string `json:"default,omitempty"`
+
+	// Options, if any, are suggested values for a string setting.
+	Options []string `json:"options,omitempty"`
+}
+
+// Parameter types, named for their JSON equivalents.
+const (
+	stringParam  = "string"
+	numberParam  = "number"
+	booleanParam = "boolean"
+	jsonParam    = "json"
+)
+
+// TextParams are settings for normalizing text before substitution.
+func textParams(extra ...Param) []Param {
+	return append([]Param{
+		{Name: "strict", Type: booleanParam},
+		{Name: "case", Type: stringParam, Default: "exact", Options: []string{"exact", "preserve", "upper", "lower"}},
+		{Name: "stripDiacritics", Type: booleanParam},
+		{Name: "symbols", Type: stringParam, Default: "keep", Options: []string{"keep", "drop", "replace"}},
+		{Name: "symbolReplacement", Type: stringParam},
+		{Name: "groupSize", Type: numberParam},
+		{Name: "groupDelimiter", Type: stringParam},
+		{Name: "groupsPerLine", Type: numberParam},
+		{Name: "padding", Type: stringParam},
+	}, extra...)
+}
+
+// MascParams are settings common to monoalphabetic substitution ciphers.
+func mascParams(extra ...Param) []Param {
+	return append([]Param{
+		{Name: "alphabet", Type: stringParam, Options: alphabet.Names()},
+	}, textParams(extra...)...)
+}
+
+// PascParams are settings common to polyalphabetic substitution ciphers.
+func pascParams(extra ...Param) []Param {
+	return mascParams(append([]Param{
+		{Name: "countersign", Type: stringParam},
+	}, extra...)...)
+}
+
+// Without returns settings with the named ones removed, for ciphers that ignore them.
+func without(pp []Param, names ...string) []Param {
+	out := make([]Param, 0, len(pp))
+	for _, p := range pp {
+		keep := true
+		for _, name := range names {
+			if p.Name == name {
+				keep = false
+			}
+		}
+		if keep {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// TranspositionParams are settings common to transposition ciphers.
+func transpositionParams(extra ...Param) []Param {
+	return append(extra,
+		Param{Name: "padding", Type: stringParam},
+		Param{Name: "groupSize", Type: numberParam},
+		Param{Name: "groupDelimiter", Type: stringParam},
+		Param{Name: "groupsPerLine", Type: numberParam},
+	)
+}
+
+// EnigmaParams are settings for an Enigma machine.
+func enigmaParams() []Param {
+	return textParams(
+		Param{Name: "rotors", Type: stringParam, Default: "I II III"},
+		Param{Name: "reflector", Type: stringParam, Default: "B", Options: []string{"B", "C", "B-thin", "C-thin"}},
+		Param{Name: "rings", Type: stringParam, Default: "AAA"},
+		Param{Name: "positions", Type: stringParam, Default: "AAA"},
+		Param{Name: "plugboard", Type: stringParam},
+	)
+}
+
+// HomophonicParams are settings for a homophonic cipher, whose table is given or else generated.
+func homophonicParams() []Param {
+	return mascParams(
+		Param{Name: "table", Type: jsonParam},
+		Param{Name: "codes", Type: numberParam, Default: "100"},
+		Param{Name: "codeSymbols", Type: stringParam},
+		Param{Name: "keyword", Type: stringParam},
+		Param{Name: "selection", Type: stringParam, Default: "cyclic", Options: []string{"cyclic", "random"}},
+		Param{Name: "seed", Type: numberParam},
+	)
+}
+
+// VernamParams are settings for a Vernam cipher, whose key tape comes from a countersign, a pad or a generator.
+func vernamParams() []Param {
+	return textParams(
+		Param{Name: "countersign", Type: stringParam},
+		Param{Name: "pad", Type: stringParam},
+		Param{Name: "generator", Type: stringParam, Options: []string{"lcg", "alfg", "mlfg"}},
+		Param{Name: "modulus", Type: numberParam},
+		Param{Name: "multiplier", Type: numberParam},
+		Param{Name: "increment", Type: numberParam},
+		Param{Name: "seed", Type: numberParam},
+		Param{Name: "seeds", Type: jsonParam},
+		Param{Name: "taps", Type: jsonParam},
+	)
+}
+
+// VicParams are settings for a VIC cipher, whose keys derive from a phrase, a date, a personal number and an indicator.
+func vicParams() []Param {
+	return without(mascParams(
+		Param{Name: "phrase", Type: stringParam},
+		Param{Name: "date", Type: stringParam},
+		Param{Name: "personalNumber", Type: numberParam},
+		Param{Name: "indicator", Type: stringParam},
+		Param{Name: "blanks", Type: jsonParam},
+	), "strict")
+}
+
+// Settings for each supported cipher, keyed by route name.
+var params = map[string][]Param{
+	"affine":                mascParams(Param{Name: "shift", Type: numberParam}, Param{Name: "multiplier", Type: numberParam, Default: "1"}),
+	"atbash":                mascParams(),
+	"beaufort":              pascParams(),
+	"caesar":                mascParams(Param{Name: "shift", Type: numberParam}),
+	"chaocipher":            mascParams(Param{Name: "left", Type: stringParam}, Param{Name: "right", Type: stringParam}),
+	"checkerboard":          mascParams(Param{Name: "digits", Type: stringParam, Default: "0123456789"}, Param{Name: "blanks", Type: jsonParam}),
+	"decimation":            mascParams(Param{Name: "multiplier", Type: numberParam, Default: "1"}),
+	"dellaporta":            pascParams(Param{Name: "keyword", Type: stringParam}),
+	"enigma":                enigmaParams(),
+	"gronsfeld":             pascParams(),
+	"homophonic":            homophonicParams(),
+	"keyword":               mascParams(Param{Name: "keyword", Type: stringParam}),
+	"lorenz":                textParams(Param{Name: "patterns", Type: jsonParam}, Param{Name: "positions", Type: jsonParam}, Param{Name: "limitation", Type: stringParam, Default: "none", Options: []string{"none", "chi2", "chi2psi1"}}, Param{Name: "p5", Type: booleanParam}),
+	"m209":                  textParams(Param{Name: "pins", Type: jsonParam}, Param{Name: "lugs", Type: stringParam}, Param{Name: "positions", Type: stringParam, Default: "AAAAAA"}),
+	"nihilist":              without(pascParams(Param{Name: "keyword", Type: stringParam}, Param{Name: "groups", Type: booleanParam}), "strict"),
+	"nihilisttransposition": transpositionParams(Param{Name: "countersign", Type: stringParam}, Param{Name: "byColumn", Type: booleanParam}, Param{Name: "nulls", Type: stringParam}),
+	"otp":                   mascParams(Param{Name: "pad", Type: stringParam}, Param{Name: "combination", Type: stringParam, Default: "vigenere", Options: []string{"vigenere", "beaufort"}}),
+	"polybius":              mascParams(Param{Name: "rowLabels", Type: stringParam}, Param{Name: "columnLabels", Type: stringParam}, Param{Name: "merge", Type: stringParam}),
+	"portax":                without(pascParams(), "strict"),
+	"railfence":             transpositionParams(Param{Name: "rows", Type: numberParam, Default: "3"}, Param{Name: "offset", Type: numberParam}, Param{Name: "countersign", Type: stringParam}, Param{Name: "nulls", Type: stringParam}),
+	"rot13":                 textParams(),
+	"scytale":               transpositionParams(Param{Name: "turns", Type: numberParam, Default: "5"}),
+	"solitaire":             textParams(Param{Name: "countersign", Type: stringParam}, Param{Name: "deck", Type: jsonParam}),
+	"trithemius":            mascParams(Param{Name: "offset", Type: numberParam}, Param{Name: "step", Type: numberParam, Default: "1"}, Param{Name: "restart", Type: stringParam, Default: "none", Options: []string{"none", "word", "line"}}),
+	"variantbeaufort":       pascParams(),
+	"vernam":                vernamParams(),
+	"vic":                   vicParams(),
+	"vigenere":              pascParams(Param{Name: "textAutoclave", Type: booleanParam}, Param{Name: "keyAutoclave", Type: booleanParam}),
+}
+
+// Lookup the processor for a cipher by route name.
+func Lookup(name string) (Processor, bool) {
+	f, ok := ciphers[name]
+	if !ok {
+		return nil, false
+	}
+	return func(s string) (*Output, error) {
+		return run(s, f)
+	}, true
+}
+
+// Ciphers returns the route names of all supported ciphers in sorted order.
+func Ciphers() []string {
+	out := make([]string, 0, len(ciphers))
+	for k := range ciphers {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Params returns the settings accepted by a cipher, aside from the message and direction.
+func Params(name string) ([]Param, bool) {
+	if _, ok := ciphers[name]; !ok {
+		return nil, false
+	}
+	return params[name], true
+}
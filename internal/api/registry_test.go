@@ -0,0 +1,49 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import "testing"
+
+func TestParams(t *testing.T) {
+	for name := range ciphers {
+		if _, ok := params[name]; !ok {
+			t.Errorf("Cipher %q has no parameter description", name)
+		}
+	}
+	for name := range params {
+		if _, ok := ciphers[name]; !ok {
+			t.Errorf("Parameter description %q has no cipher", name)
+		}
+	}
+}
+
+func TestParams_unsupported(t *testing.T) {
+	tables := []struct {
+		cipher string
+		param  string
+	}{
+		{"nihilist", "strict"},
+		{"portax", "strict"},
+		{"vic", "strict"},
+	}
+
+	for _, table := range tables {
+		for _, p := range params[table.cipher] {
+			if p.Name == table.param {
+				t.Errorf("Expected cipher %q not to advertise unsupported parameter %q", table.cipher, table.param)
+			}
+		}
+	}
+}
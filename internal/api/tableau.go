@@ -0,0 +1,112 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrUnknownCipher is returned when a cipher cannot be found by name.
+var ErrUnknownCipher = errors.New("Unknown cipher")
+
+// A tableauer represents its tableau both as printable text and as a matrix.
+type tableauer interface {
+	Tableau() (string, error)
+	TableauMatrix() ([][]string, error)
+}
+
+// A gridder renders the transposition grid for a particular message both as printable text and as a matrix.
+type gridder interface {
+	EnciphermentGrid(string) (string, error)
+	DeciphermentGrid(string) (string, error)
+	EnciphermentMatrix(string) ([][]string, error)
+	DeciphermentMatrix(string) ([][]string, error)
+}
+
+// A TableauResult holds printable and structured representations of a tableau.
+type TableauResult struct {
+	Text   string     `json:"text"`
+	Matrix [][]string `json:"matrix"`
+}
+
+// Tableau for a cipher by route name, configured by a JSON payload.
+// Tableau renders the grid for the payload message when the cipher is a transposition.
+func Tableau(name string, s string) (*TableauResult, error) {
+	f, ok := ciphers[name]
+	if !ok {
+		return nil, ErrUnknownCipher
+	}
+
+	c, err := f(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if g, ok := c.(gridder); ok {
+		var payload mascBaseConfig
+		if err := json.Unmarshal([]byte(s), &payload); err != nil {
+			return nil, err
+		}
+		if payload.Message != "" {
+			return grid(g, payload)
+		}
+	}
+
+	t, ok := c.(tableauer)
+	if !ok {
+		return nil, fmt.Errorf("Cipher %q has no tableau", name)
+	}
+
+	text, err := t.Tableau()
+	if err != nil {
+		return nil, err
+	}
+	matrix, err := t.TableauMatrix()
+	if err != nil {
+		return nil, err
+	}
+	return &TableauResult{Text: text, Matrix: matrix}, nil
+}
+
+// Grid for a transposition cipher, rendered for the payload message.
+func grid(g gridder, payload mascBaseConfig) (*TableauResult, error) {
+	ff, err := payload.formatter()
+	if err != nil {
+		return nil, err
+	}
+
+	var text string
+	var matrix [][]string
+	if payload.Reverse {
+		if ff.GroupSize > 0 {
+			payload.Message = ff.Unformat(payload.Message)
+		}
+		if text, err = g.DeciphermentGrid(payload.Message); err != nil {
+			return nil, err
+		}
+		matrix, err = g.DeciphermentMatrix(payload.Message)
+	} else {
+		if text, err = g.EnciphermentGrid(payload.Message); err != nil {
+			return nil, err
+		}
+		matrix, err = g.EnciphermentMatrix(payload.Message)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &TableauResult{Text: text, Matrix: matrix}, nil
+}
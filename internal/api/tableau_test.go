@@ -0,0 +1,95 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTableau(t *testing.T) {
+	out, err := Tableau("caesar", `{"alphabet": "ABCDE", "shift": 2}`)
+	if err != nil {
+		t.Fatal("Could not render tableau:", err)
+	}
+
+	const text = "PT: ABCDE\nCT: CDEAB"
+	if out.Text != text {
+		t.Errorf("Expected text %q, but got %q", text, out.Text)
+	}
+
+	matrix := [][]string{
+		{"PT", "A", "B", "C", "D", "E"},
+		{"CT", "C", "D", "E", "A", "B"},
+	}
+	if !reflect.DeepEqual(out.Matrix, matrix) {
+		t.Errorf("Expected matrix %q, but got %q", matrix, out.Matrix)
+	}
+
+	if _, err := Tableau("nonesuch", `{}`); err != ErrUnknownCipher {
+		t.Errorf("Expected unknown cipher error, but got %v", err)
+	}
+}
+
+func TestTableau_grid(t *testing.T) {
+	tables := []struct {
+		name    string
+		payload string
+		text    string
+		matrix  [][]string
+	}{
+		{"railfence", `{"rows": 3, "message": "WEAREDISCOVERED"}`, "W   E   C   R\n E R D S O E E\n  A   I   V   D", nil},
+		{"railfence", `{"rows": 3, "message": "WECRERDSOEEAIVD", "reverse": true}`, "W   E   C   R\n E R D S O E E\n  A   I   V   D", nil},
+		{"railfence", `{"rows": 3, "message": "WE ARE"}`, "W   R\n E A E\n   ", [][]string{
+			{"W", "", "", "", "R", ""},
+			{"", "E", "", "A", "", "E"},
+			{"", "", " ", "", "", ""},
+		}},
+		{"scytale", `{"turns": 3, "message": "WE ARE DONE"}`, "WE \nARE\n DO\nNE", [][]string{
+			{"W", "E", " "},
+			{"A", "R", "E"},
+			{" ", "D", "O"},
+			{"N", "E", ""},
+		}},
+		{"nihilisttransposition", `{"countersign": "CAB", "message": "WEAREDISCOVER"}`, "EDR\nSCI\nEAW\n\n  R\nVEO", [][]string{
+			{"E", "D", "R"},
+			{"S", "C", "I"},
+			{"E", "A", "W"},
+			{"", "", "R"},
+			{"", "", ""},
+			{"V", "E", "O"},
+		}},
+	}
+
+	for _, table := range tables {
+		out, err := Tableau(table.name, table.payload)
+		if err != nil {
+			t.Errorf("Could not render %s grid: %v", table.name, err)
+			continue
+		}
+		if out.Text != table.text {
+			t.Errorf("Expected %s grid %q, but got %q", table.name, table.text, out.Text)
+		}
+		for _, row := range out.Matrix {
+			if len(row) != len(out.Matrix[0]) {
+				t.Errorf("Expected %s matrix rows of equal width, but got %q", table.name, out.Matrix)
+				break
+			}
+		}
+		if table.matrix != nil && !reflect.DeepEqual(out.Matrix, table.matrix) {
+			t.Errorf("Expected %s matrix %q, but got %q", table.name, table.matrix, out.Matrix)
+		}
+	}
+}
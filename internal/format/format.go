@@ -0,0 +1,102 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package format arranges ciphertext into groups and lines.
+package format
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/merenbach/goldbug/internal/stringutil"
+)
+
+// A Formatter groups the letters of a message into blocks.
+type Formatter struct {
+	// Alphabet of runes to keep, in either case; all letters and digits are kept if empty.
+	Alphabet string
+
+	// GroupSize is the number of runes per group.
+	GroupSize int
+
+	// Delimiter between groups; a single space is used if empty.
+	Delimiter string
+
+	// GroupsPerLine is the number of groups per line, or zero not to wrap lines.
+	GroupsPerLine int
+
+	// Padding fills the final group with nulls, unless it is zero.
+	Padding rune
+}
+
+// Keep returns a function reporting whether a rune belongs in formatted output.
+func (f *Formatter) keep() func(rune) bool {
+	if f.Alphabet == "" {
+		return func(r rune) bool {
+			return unicode.IsLetter(r) || unicode.IsDigit(r)
+		}
+	}
+
+	m := make(map[rune]struct{})
+	for _, r := range f.Alphabet {
+		m[r] = struct{}{}
+	}
+	return func(r rune) bool {
+		for _, c := range [...]rune{r, unicode.ToUpper(r), unicode.ToLower(r)} {
+			if _, ok := m[c]; ok {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Unformat a message by removing all runes not kept by this formatter, including delimiters and line breaks.
+func (f *Formatter) Unformat(s string) string {
+	keep := f.keep()
+	return strings.Map(func(r rune) rune {
+		if keep(r) {
+			return r
+		}
+		return (-1)
+	}, s)
+}
+
+// Format a message into groups.
+// Format returns the message unchanged if the group size is not positive.
+func (f *Formatter) Format(s string) string {
+	if f.GroupSize <= 0 {
+		return s
+	}
+
+	delimiter := f.Delimiter
+	if delimiter == "" {
+		delimiter = " "
+	}
+
+	groups := stringutil.GroupString(f.Unformat(s), f.GroupSize, f.Padding)
+	if f.GroupsPerLine <= 0 {
+		return strings.Join(groups, delimiter)
+	}
+
+	lines := make([]string, 0, len(groups)/f.GroupsPerLine+1)
+	for i := 0; i < len(groups); i += f.GroupsPerLine {
+		end := i + f.GroupsPerLine
+		if end > len(groups) {
+			end = len(groups)
+		}
+		lines = append(lines, strings.Join(groups[i:end], delimiter))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,44 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package format
+
+import "testing"
+
+func TestFormatter(t *testing.T) {
+	const alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+	tables := []struct {
+		f         Formatter
+		input     string
+		formatted string
+		restored  string
+	}{
+		{Formatter{Alphabet: alphabet}, "HELLO, WORLD", "HELLO, WORLD", "HELLOWORLD"},
+		{Formatter{Alphabet: alphabet, GroupSize: 5}, "ATTACK AT DAWN!", "ATTAC KATDA WN", "ATTACKATDAWN"},
+		{Formatter{Alphabet: alphabet, GroupSize: 5, Padding: 'X'}, "ATTACK AT DAWN!", "ATTAC KATDA WNXXX", "ATTACKATDAWNXXX"},
+		{Formatter{Alphabet: alphabet, GroupSize: 3, Delimiter: "-", GroupsPerLine: 2}, "ATTACK AT DAWN", "ATT-ACK\nATD-AWN", "ATTACKATDAWN"},
+		{Formatter{Alphabet: alphabet, GroupSize: 4, GroupsPerLine: 2}, "Attack at dawn", "Atta ckat\ndawn", "Attackatdawn"},
+		{Formatter{GroupSize: 2}, "ΑΒΓ ΔΕ", "ΑΒ ΓΔ Ε", "ΑΒΓΔΕ"},
+	}
+
+	for _, table := range tables {
+		if out := table.f.Format(table.input); out != table.formatted {
+			t.Errorf("Expected %q to format as %q, but got %q", table.input, table.formatted, out)
+		}
+		if out := table.f.Unformat(table.formatted); out != table.restored {
+			t.Errorf("Expected %q to unformat as %q, but got %q", table.formatted, table.restored, out)
+		}
+	}
+}
@@ -17,13 +17,17 @@ package grid
 import (
 	"sort"
 	"strings"
+
+	"github.com/merenbach/goldbug/internal/trace"
 )
 
 // A cell holds a rune alongside table coordinates.
+// The index records the order in which the cell was filled.
 type cell struct {
-	Row  int
-	Col  int
-	Rune rune
+	Row   int
+	Col   int
+	Rune  rune
+	Index int
 }
 
 // A Grid is a slice of cells.
@@ -59,6 +63,18 @@ func (g Grid) ReadByCol() string {
 	return g2.contents()
 }
 
+// Rekey moves each cell to the row and column given for its current row and column.
+func (g Grid) Rekey(rows []int, cols []int) {
+	for i := range g {
+		g[i].Row = rows[g[i].Row]
+		g[i].Col = cols[g[i].Col]
+	}
+
+	// Sorting by row first leaves cells sorted by column with rows as the tiebreaker, as the other methods assume.
+	g.sortByRow()
+	g.sortByCol()
+}
+
 // Printable version of this grid.
 func (g Grid) Printable() string {
 	g2 := make(Grid, len(g))
@@ -89,6 +105,45 @@ func (g Grid) Printable() string {
 	return out.String()
 }
 
+// Matrix of this grid, with a row of equal width for each row up to the last one filled.
+// Empty cells are empty strings.
+func (g Grid) Matrix() [][]string {
+	var rows, cols int
+	for _, c := range g {
+		if c.Row >= rows {
+			rows = c.Row + 1
+		}
+		if c.Col >= cols {
+			cols = c.Col + 1
+		}
+	}
+
+	out := make([][]string, rows)
+	for i := range out {
+		out[i] = make([]string, cols)
+	}
+	for _, c := range g {
+		out[c.Row][c.Col] = string(c.Rune)
+	}
+	return out
+}
+
+// Trace the placement of each rune, in the order in which the grid was filled.
+func (g Grid) Trace() trace.Trace {
+	out := make(trace.Trace, len(g))
+	for _, c := range g {
+		out[c.Index] = trace.Event{
+			Index:  c.Index,
+			Input:  c.Rune,
+			Row:    c.Row,
+			Col:    c.Col,
+			Output: c.Rune,
+			Action: trace.Transcoded,
+		}
+	}
+	return out
+}
+
 // Contents of this grid in the current order.
 func (g Grid) contents() string {
 	var b strings.Builder
@@ -102,6 +157,7 @@ func (g Grid) contents() string {
 func (g Grid) fill(s string) {
 	for i, r := range []rune(s)[:len(g)] {
 		g[i].Rune = r
+		g[i].Index = i
 	}
 }
 
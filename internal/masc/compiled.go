@@ -0,0 +1,75 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package masc
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/merenbach/goldbug/internal/normalize"
+	"github.com/merenbach/goldbug/internal/runetab"
+)
+
+// A Compiled tableau holds precomputed lookup tables.
+// A Compiled tableau is immutable and safe for concurrent use.
+type Compiled struct {
+	strict     bool
+	normalizer normalize.Normalizer
+
+	ptRunes []rune
+	ctRunes []rune
+	pts     *runetab.Index
+	cts     *runetab.Index
+}
+
+// Compile this tableau into lookup tables indexed by rune.
+func (t *Tableau) Compile() (*Compiled, error) {
+	ptRunes, ctRunes := []rune(t.PtAlphabet), []rune(t.CtAlphabet)
+	if len(ptRunes) != len(ctRunes) {
+		return nil, errors.New("The first two arguments must have equal length")
+	}
+
+	return &Compiled{
+		strict:     t.Strict,
+		normalizer: t.Normalizer,
+		ptRunes:    ptRunes,
+		ctRunes:    ctRunes,
+		pts:        runetab.New(ptRunes),
+		cts:        runetab.New(ctRunes),
+	}, nil
+}
+
+// Encipher a string.
+func (c *Compiled) Encipher(s string) (string, error) {
+	return c.translate(s, c.pts, c.ctRunes), nil
+}
+
+// Decipher a string.
+func (c *Compiled) Decipher(s string) (string, error) {
+	return c.translate(s, c.cts, c.ptRunes), nil
+}
+
+// Translate a string by looking up the position of each rune in one alphabet and replacing it with the rune at that position in another.
+func (c *Compiled) translate(s string, src *runetab.Index, dst []rune) string {
+	return strings.Map(func(r rune) rune {
+		f := c.normalizer.Fold(r, src.Has)
+		if i, ok := src.Get(f); ok {
+			return c.normalizer.Restore(r, f, dst[i])
+		} else if !c.strict {
+			return r
+		}
+		return (-1)
+	}, c.normalizer.Normalize(s))
+}
@@ -16,7 +16,10 @@ package masc
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/merenbach/goldbug/internal/normalize"
+	"github.com/merenbach/goldbug/internal/trace"
 	"github.com/merenbach/goldbug/internal/translation"
 )
 
@@ -29,6 +32,12 @@ type Tableau struct {
 	CtAlphabet string
 
 	Strict bool
+
+	// Normalizer prepares input text before translation.
+	Normalizer normalize.Normalizer
+
+	// Trace, if set, receives an event for each input rune.
+	Trace *trace.Trace
 }
 
 // Encipher a string.
@@ -38,7 +47,7 @@ func (t *Tableau) Encipher(s string) (string, error) {
 		Dst:    t.CtAlphabet,
 		Strict: t.Strict,
 	}
-	return tt.Translate(s)
+	return t.translate(t.Normalizer.Normalize(s), &tt)
 }
 
 // Decipher a string.
@@ -48,10 +57,56 @@ func (t *Tableau) Decipher(s string) (string, error) {
 		Dst:    t.PtAlphabet,
 		Strict: t.Strict,
 	}
-	return tt.Translate(s)
+	return t.translate(t.Normalizer.Normalize(s), &tt)
+}
+
+// Translate a string, folding case and recording an event for each rune as needed.
+// Both alphabets share columns, so the column recorded is the position of the input rune in the source alphabet.
+func (t *Tableau) translate(s string, tt *translation.Table) (string, error) {
+	if t.Trace == nil && t.Normalizer.Case != normalize.PreserveCase {
+		return tt.Translate(s)
+	}
+
+	m, err := tt.Map()
+	if err != nil {
+		return "", err
+	}
+	present := func(r rune) bool {
+		_, ok := m[r]
+		return ok
+	}
+
+	cols := make(map[rune]int)
+	for i, r := range []rune(tt.Src) {
+		cols[r] = i
+	}
+
+	var out strings.Builder
+	for i, r := range []rune(s) {
+		f := t.Normalizer.Fold(r, present)
+		if o, ok := m[f]; ok {
+			o = t.Normalizer.Restore(r, f, o)
+			t.Trace.Add(trace.Event{Index: i, Input: r, Row: 0, Col: cols[f], Output: o, Action: trace.Transcoded})
+			out.WriteRune(o)
+		} else if !tt.Strict {
+			t.Trace.Pass(i, r, 0)
+			out.WriteRune(r)
+		} else {
+			t.Trace.Skip(i, r, 0)
+		}
+	}
+	return out.String(), nil
 }
 
 // Printable representation of this tableau.
 func (t *Tableau) Printable() (string, error) {
 	return fmt.Sprintf("PT: %s\nCT: %s", t.PtAlphabet, t.CtAlphabet), nil
 }
+
+// Matrix representation of this tableau, with row labels in the first column.
+func (t *Tableau) Matrix() ([][]string, error) {
+	return [][]string{
+		append([]string{"PT"}, strings.Split(t.PtAlphabet, "")...),
+		append([]string{"CT"}, strings.Split(t.CtAlphabet, "")...),
+	}, nil
+}
@@ -13,3 +13,81 @@
 // limitations under the License.
 
 package masc
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/merenbach/goldbug/internal/normalize"
+	"github.com/merenbach/goldbug/internal/trace"
+)
+
+func TestTableau_Trace(t *testing.T) {
+	var tr trace.Trace
+	tableau := Tableau{
+		PtAlphabet: "ABC",
+		CtAlphabet: "CAB",
+		Strict:     true,
+		Trace:      &tr,
+	}
+
+	expected := trace.Trace{
+		{Index: 0, Input: 'A', Row: 0, Col: 0, Output: 'C', Action: trace.Transcoded},
+		{Index: 1, Input: '-', Row: (-1), Col: (-1), Action: trace.Skipped},
+		{Index: 2, Input: 'C', Row: 0, Col: 2, Output: 'B', Action: trace.Transcoded},
+	}
+
+	if out, err := tableau.Encipher("A-C"); err != nil {
+		t.Error("Error:", err)
+	} else if out != "CB" {
+		t.Errorf("Expected %q, but got %q", "CB", out)
+	}
+	if !reflect.DeepEqual(tr, expected) {
+		t.Errorf("Expected trace %+v, but got %+v", expected, tr)
+	}
+}
+
+func TestTableau_PreserveCase(t *testing.T) {
+	tableau := Tableau{
+		PtAlphabet: Alphabet,
+		CtAlphabet: "DEFGHIJKLMNOPQRSTUVWXYZABC",
+		Normalizer: normalize.Normalizer{Case: normalize.PreserveCase},
+	}
+
+	if out, err := tableau.Encipher("Hello, World!"); err != nil {
+		t.Error("Error:", err)
+	} else if out != "Khoor, Zruog!" {
+		t.Errorf("Expected %q, but got %q", "Khoor, Zruog!", out)
+	}
+	if out, err := tableau.Decipher("Khoor, Zruog!"); err != nil {
+		t.Error("Error:", err)
+	} else if out != "Hello, World!" {
+		t.Errorf("Expected %q, but got %q", "Hello, World!", out)
+	}
+}
+
+func TestTableau_Compile(t *testing.T) {
+	tables := []Tableau{
+		{PtAlphabet: Alphabet, CtAlphabet: "ZYXWVUTSRQPONMLKJIHGFEDCBA"},
+		{PtAlphabet: Alphabet, CtAlphabet: "ZYXWVUTSRQPONMLKJIHGFEDCBA", Strict: true},
+		{PtAlphabet: Alphabet, CtAlphabet: "DEFGHIJKLMNOPQRSTUVWXYZABC", Normalizer: normalize.Normalizer{Case: normalize.PreserveCase}},
+		{PtAlphabet: "ΑΒΓΔ", CtAlphabet: "ΔΓΒΑ"},
+	}
+
+	for _, tableau := range tables {
+		c, err := tableau.Compile()
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		for _, s := range []string{"Hello, World!", "ΑΒΓ ΔΕ"} {
+			expected, _ := tableau.Encipher(s)
+			if out, _ := c.Encipher(s); out != expected {
+				t.Errorf("Expected compiled encipherment of %q to be %q, but got %q", s, expected, out)
+			}
+			expected, _ = tableau.Decipher(s)
+			if out, _ := c.Decipher(s); out != expected {
+				t.Errorf("Expected compiled decipherment of %q to be %q, but got %q", s, expected, out)
+			}
+		}
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package normalize
+
+// Decompositions of precomposed letters into their base letters.
+// These cover the Latin-1 Supplement, Latin Extended-A and -B, Greek and Cyrillic blocks,
+// along with ligatures and letters such as "ß" that lack a canonical decomposition.
+// Й and Ё are left alone, since the Russian alphabet counts them as letters of their own.
+var decompositions = map[rune]string{
+	'À': "A", 'Á': "A", 'Â': "A", 'Ã': "A", 'Ä': "A", 'Å': "A",
+	'Æ': "AE", 'Ç': "C", 'È': "E", 'É': "E", 'Ê': "E", 'Ë': "E",
+	'Ì': "I", 'Í': "I", 'Î': "I", 'Ï': "I", 'Ð': "D", 'Ñ': "N",
+	'Ò': "O", 'Ó': "O", 'Ô': "O", 'Õ': "O", 'Ö': "O", 'Ø': "O",
+	'Ù': "U", 'Ú': "U", 'Û': "U", 'Ü': "U", 'Ý': "Y", 'Þ': "TH",
+	'ß': "ss", 'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a",
+	'å': "a", 'æ': "ae", 'ç': "c", 'è': "e", 'é': "e", 'ê': "e",
+	'ë': "e", 'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ð': "d",
+	'ñ': "n", 'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o",
+	'ø': "o", 'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ý': "y",
+	'þ': "th", 'ÿ': "y", 'Ā': "A", 'ā': "a", 'Ă': "A", 'ă': "a",
+	'Ą': "A", 'ą': "a", 'Ć': "C", 'ć': "c", 'Ĉ': "C", 'ĉ': "c",
+	'Ċ': "C", 'ċ': "c", 'Č': "C", 'č': "c", 'Ď': "D", 'ď': "d",
+	'Đ': "D", 'đ': "d", 'Ē': "E", 'ē': "e", 'Ĕ': "E", 'ĕ': "e",
+	'Ė': "E", 'ė': "e", 'Ę': "E", 'ę': "e", 'Ě': "E", 'ě': "e",
+	'Ĝ': "G", 'ĝ': "g", 'Ğ': "G", 'ğ': "g", 'Ġ': "G", 'ġ': "g",
+	'Ģ': "G", 'ģ': "g", 'Ĥ': "H", 'ĥ': "h", 'Ħ': "H", 'ħ': "h",
+	'Ĩ': "I", 'ĩ': "i", 'Ī': "I", 'ī': "i", 'Ĭ': "I", 'ĭ': "i",
+	'Į': "I", 'į': "i", 'İ': "I", 'ı': "i", 'Ĳ': "IJ", 'ĳ': "ij",
+	'Ĵ': "J", 'ĵ': "j", 'Ķ': "K", 'ķ': "k", 'Ĺ': "L", 'ĺ': "l",
+	'Ļ': "L", 'ļ': "l", 'Ľ': "L", 'ľ': "l", 'Ŀ': "L", 'ŀ': "l",
+	'Ł': "L", 'ł': "l", 'Ń': "N", 'ń': "n", 'Ņ': "N", 'ņ': "n",
+	'Ň': "N", 'ň': "n", 'Ō': "O", 'ō': "o", 'Ŏ': "O", 'ŏ': "o",
+	'Ő': "O", 'ő': "o", 'Œ': "OE", 'œ': "oe", 'Ŕ': "R", 'ŕ': "r",
+	'Ŗ': "R", 'ŗ': "r", 'Ř': "R", 'ř': "r", 'Ś': "S", 'ś': "s",
+	'Ŝ': "S", 'ŝ': "s", 'Ş': "S", 'ş': "s", 'Š': "S", 'š': "s",
+	'Ţ': "T", 'ţ': "t", 'Ť': "T", 'ť': "t", 'Ũ': "U", 'ũ': "u",
+	'Ū': "U", 'ū': "u", 'Ŭ': "U", 'ŭ': "u", 'Ů': "U", 'ů': "u",
+	'Ű': "U", 'ű': "u", 'Ų': "U", 'ų': "u", 'Ŵ': "W", 'ŵ': "w",
+	'Ŷ': "Y", 'ŷ': "y", 'Ÿ': "Y", 'Ź': "Z", 'ź': "z", 'Ż': "Z",
+	'ż': "z", 'Ž': "Z", 'ž': "z", 'Ơ': "O", 'ơ': "o", 'Ư': "U",
+	'ư': "u", 'Ǎ': "A", 'ǎ': "a", 'Ǐ': "I", 'ǐ': "i", 'Ǒ': "O",
+	'ǒ': "o", 'Ǔ': "U", 'ǔ': "u", 'Ǖ': "U", 'ǖ': "u", 'Ǘ': "U",
+	'ǘ': "u", 'Ǚ': "U", 'ǚ': "u", 'Ǜ': "U", 'ǜ': "u", 'Ǟ': "A",
+	'ǟ': "a", 'Ǡ': "A", 'ǡ': "a", 'Ǣ': "AE", 'ǣ': "ae", 'Ǧ': "G",
+	'ǧ': "g", 'Ǩ': "K", 'ǩ': "k", 'Ǫ': "O", 'ǫ': "o", 'Ǭ': "O",
+	'ǭ': "o", 'Ǯ': "Ʒ", 'ǯ': "ʒ", 'ǰ': "j", 'Ǵ': "G", 'ǵ': "g",
+	'Ǹ': "N", 'ǹ': "n", 'Ǻ': "A", 'ǻ': "a", 'Ǽ': "AE", 'ǽ': "ae",
+	'Ǿ': "O", 'ǿ': "o", 'Ȁ': "A", 'ȁ': "a", 'Ȃ': "A", 'ȃ': "a",
+	'Ȅ': "E", 'ȅ': "e", 'Ȇ': "E", 'ȇ': "e", 'Ȉ': "I", 'ȉ': "i",
+	'Ȋ': "I", 'ȋ': "i", 'Ȍ': "O", 'ȍ': "o", 'Ȏ': "O", 'ȏ': "o",
+	'Ȑ': "R", 'ȑ': "r", 'Ȓ': "R", 'ȓ': "r", 'Ȕ': "U", 'ȕ': "u",
+	'Ȗ': "U", 'ȗ': "u", 'Ș': "S", 'ș': "s", 'Ț': "T", 'ț': "t",
+	'Ȟ': "H", 'ȟ': "h", 'Ȧ': "A", 'ȧ': "a", 'Ȩ': "E", 'ȩ': "e",
+	'Ȫ': "O", 'ȫ': "o", 'Ȭ': "O", 'ȭ': "o", 'Ȯ': "O", 'ȯ': "o",
+	'Ȱ': "O", 'ȱ': "o", 'Ȳ': "Y", 'ȳ': "y", 'Ά': "Α", 'Έ': "Ε",
+	'Ή': "Η", 'Ί': "Ι", 'Ό': "Ο", 'Ύ': "Υ", 'Ώ': "Ω", 'ΐ': "ι",
+	'Ϊ': "Ι", 'Ϋ': "Υ", 'ά': "α", 'έ': "ε", 'ή': "η", 'ί': "ι",
+	'ΰ': "υ", 'ϊ': "ι", 'ϋ': "υ", 'ό': "ο", 'ύ': "υ", 'ώ': "ω",
+	'Ѐ': "Е", 'Ѓ': "Г", 'Ї': "І", 'Ќ': "К", 'Ѝ': "И", 'Ў': "У",
+	'ѐ': "е", 'ѓ': "г", 'ї': "і", 'ќ': "к", 'ѝ': "и", 'ў': "у",
+	'Ѷ': "Ѵ", 'ѷ': "ѵ", 'Ӂ': "Ж", 'ӂ': "ж", 'Ӑ': "А", 'ӑ': "а",
+	'Ӓ': "А", 'ӓ': "а", 'Ӗ': "Е", 'ӗ': "е", 'Ӛ': "Ә", 'ӛ': "ә",
+	'Ӝ': "Ж", 'ӝ': "ж", 'Ӟ': "З", 'ӟ': "з", 'Ӣ': "И", 'ӣ': "и",
+	'Ӥ': "И", 'ӥ': "и", 'Ӧ': "О", 'ӧ': "о", 'Ӫ': "Ө", 'ӫ': "ө",
+	'Ӭ': "Э", 'ӭ': "э", 'Ӯ': "У", 'ӯ': "у", 'Ӱ': "У", 'ӱ': "у",
+	'Ӳ': "У", 'ӳ': "у", 'Ӵ': "Ч", 'ӵ': "ч", 'Ӹ': "Ы", 'ӹ': "ы",
+	'ẞ': "SS",
+}
@@ -0,0 +1,135 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package normalize prepares text for substitution ciphers.
+package normalize
+
+import (
+	"strings"
+	"unicode"
+)
+
+// A Case determines how letter case is treated.
+type Case uint8
+
+const (
+	// ExactCase matches runes exactly as given.
+	ExactCase Case = iota
+
+	// PreserveCase matches runes regardless of case, then restores the case of each rune.
+	PreserveCase
+
+	// UpperCase folds all text to upper case.
+	UpperCase
+
+	// LowerCase folds all text to lower case.
+	LowerCase
+)
+
+// A SymbolMode determines how symbols are treated.
+// A symbol is any rune that is neither a letter nor a digit.
+type SymbolMode uint8
+
+const (
+	// KeepSymbols leaves symbols to the cipher, which passes them through unless strict.
+	KeepSymbols SymbolMode = iota
+
+	// DropSymbols removes symbols.
+	DropSymbols
+
+	// ReplaceSymbols substitutes a replacement rune for each symbol.
+	ReplaceSymbols
+)
+
+// A Normalizer prepares text for a cipher.
+// The zero value leaves text unchanged.
+type Normalizer struct {
+	Case            Case
+	StripDiacritics bool
+	Symbols         SymbolMode
+	Replacement     rune
+}
+
+// Normalize a string by stripping diacritics, handling symbols and folding case, in that order.
+func (n Normalizer) Normalize(s string) string {
+	if n.StripDiacritics {
+		var b strings.Builder
+		for _, r := range s {
+			if d, ok := decompositions[r]; ok {
+				b.WriteString(d)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+		s = b.String()
+	}
+
+	switch n.Symbols {
+	case DropSymbols:
+		s = strings.Map(func(r rune) rune {
+			if isSymbol(r) {
+				return (-1)
+			}
+			return r
+		}, s)
+	case ReplaceSymbols:
+		s = strings.Map(func(r rune) rune {
+			if isSymbol(r) {
+				return n.Replacement
+			}
+			return r
+		}, s)
+	}
+
+	switch n.Case {
+	case UpperCase:
+		s = strings.ToUpper(s)
+	case LowerCase:
+		s = strings.ToLower(s)
+	}
+
+	return s
+}
+
+// Fold a rune for lookup.
+// Fold returns the rune itself if present, or, when preserving case, its counterpart in the other case if that is present instead.
+func (n Normalizer) Fold(r rune, present func(rune) bool) rune {
+	if n.Case != PreserveCase || present(r) {
+		return r
+	}
+	for _, f := range [...]rune{unicode.ToUpper(r), unicode.ToLower(r)} {
+		if f != r && present(f) {
+			return f
+		}
+	}
+	return r
+}
+
+// Restore the case of rune `r` to output rune `o` if `r` was folded to rune `f` for lookup.
+func (n Normalizer) Restore(r rune, f rune, o rune) rune {
+	switch {
+	case r == f:
+		return o
+	case unicode.IsLower(r):
+		return unicode.ToLower(o)
+	case unicode.IsUpper(r):
+		return unicode.ToUpper(o)
+	}
+	return o
+}
+
+// IsSymbol determines whether a rune is neither a letter nor a digit.
+func isSymbol(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
@@ -0,0 +1,114 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package normalize
+
+import (
+	"strings"
+	"testing"
+	"unicode"
+)
+
+func TestNormalizer_Normalize(t *testing.T) {
+	tables := []struct {
+		n        Normalizer
+		input    string
+		expected string
+	}{
+		{Normalizer{}, "Straße, café!", "Straße, café!"},
+		{Normalizer{Case: UpperCase}, "Hello, World!", "HELLO, WORLD!"},
+		{Normalizer{Case: LowerCase}, "Hello, World!", "hello, world!"},
+		{Normalizer{Case: PreserveCase}, "Hello, World!", "Hello, World!"},
+		{Normalizer{Case: UpperCase, StripDiacritics: true}, "Straße, café!", "STRASSE, CAFE!"},
+		{Normalizer{StripDiacritics: true}, "Æsop's Œuvre, Łódź", "AEsop's OEuvre, Lodz"},
+		{Normalizer{StripDiacritics: true}, "Ǣ ǣ Ǽ ǽ Ǿ ǿ", "AE ae AE ae O o"},
+		{Normalizer{StripDiacritics: true}, "ЙОД, ёж, Ѐ", "ЙОД, ёж, Е"},
+		{Normalizer{StripDiacritics: true}, "\u0374\u037e\u0387\u0385\u03d3\u03d4", "\u0374\u037e\u0387\u0385\u03d3\u03d4"},
+		{Normalizer{Symbols: DropSymbols}, "Hello, World 42!", "HelloWorld42"},
+		{Normalizer{Symbols: ReplaceSymbols, Replacement: 'X'}, "Hi, there", "HiXXthere"},
+	}
+
+	for _, table := range tables {
+		if out := table.n.Normalize(table.input); out != table.expected {
+			t.Errorf("Expected %q to normalize to %q, but got %q", table.input, table.expected, out)
+		}
+	}
+}
+
+func TestDecompositions(t *testing.T) {
+	for r, d := range decompositions {
+		if !unicode.IsLetter(r) {
+			t.Errorf("Expected only letters to decompose, but %q does", r)
+		}
+		if d == string(r) {
+			t.Errorf("Expected %q not to decompose to itself", r)
+		}
+		for _, o := range d {
+			if !unicode.IsLetter(o) {
+				t.Errorf("Expected %q to decompose to letters, but got %q", r, d)
+			}
+			if _, ok := decompositions[o]; ok {
+				t.Errorf("Expected %q to decompose fully, but got %q", r, d)
+			}
+		}
+	}
+}
+
+func TestNormalizer_Fold(t *testing.T) {
+	present := func(r rune) bool {
+		return strings.ContainsRune("ABCxyz", r)
+	}
+
+	tables := []struct {
+		c        Case
+		input    rune
+		expected rune
+	}{
+		{ExactCase, 'a', 'a'},
+		{PreserveCase, 'A', 'A'},
+		{PreserveCase, 'a', 'A'},
+		{PreserveCase, 'X', 'x'},
+		{PreserveCase, 'q', 'q'},
+		{PreserveCase, '!', '!'},
+	}
+
+	for _, table := range tables {
+		n := Normalizer{Case: table.c}
+		if out := n.Fold(table.input, present); out != table.expected {
+			t.Errorf("Expected %q to fold to %q, but got %q", table.input, table.expected, out)
+		}
+	}
+}
+
+func TestNormalizer_Restore(t *testing.T) {
+	var n Normalizer
+
+	tables := []struct {
+		input    rune
+		folded   rune
+		output   rune
+		expected rune
+	}{
+		{'A', 'A', 'q', 'q'},
+		{'a', 'A', 'Q', 'q'},
+		{'X', 'x', 'b', 'B'},
+		{'a', 'A', '7', '7'},
+	}
+
+	for _, table := range tables {
+		if out := n.Restore(table.input, table.folded, table.output); out != table.expected {
+			t.Errorf("Expected %q folded to %q to restore %q as %q, but got %q", table.input, table.folded, table.output, table.expected, out)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pasc
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/merenbach/goldbug/internal/normalize"
+	"github.com/merenbach/goldbug/internal/runetab"
+)
+
+// A Compiled table holds precomputed lookup tables for a reciprocal table.
+// A Compiled table is immutable and safe for concurrent use.
+type Compiled struct {
+	strict     bool
+	normalizer normalize.Normalizer
+
+	ptRunes []rune
+	pts     *runetab.Index
+	keys    *runetab.Index
+	rows    [][]rune
+	cts     []*runetab.Index
+}
+
+// Compile this table into lookup tables indexed by rune.
+func (tr *ReciprocalTable) Compile() (*Compiled, error) {
+	ptRunes := []rune(tr.PtAlphabet)
+	keyRunes := []rune(tr.KeyAlphabet)
+	if len(keyRunes) != len(tr.CtAlphabets) {
+		return nil, errors.New("Row headers must have same rune length as rows slice")
+	}
+
+	c := Compiled{
+		strict:     tr.Strict,
+		normalizer: tr.Normalizer,
+		ptRunes:    ptRunes,
+		pts:        runetab.New(ptRunes),
+		keys:       runetab.New(keyRunes),
+		rows:       make([][]rune, len(keyRunes)),
+		cts:        make([]*runetab.Index, len(keyRunes)),
+	}
+	for i, s := range tr.CtAlphabets {
+		row := []rune(s)
+		if len(row) != len(ptRunes) {
+			return nil, errors.New("The first two arguments must have equal length")
+		}
+		c.rows[i], c.cts[i] = row, runetab.New(row)
+	}
+	return &c, nil
+}
+
+// Compile this tabula recta into lookup tables indexed by rune.
+func (tr *TabulaRecta) Compile() (*Compiled, error) {
+	rt, err := tr.makereciprocaltable()
+	if err != nil {
+		return nil, err
+	}
+	return rt.Compile()
+}
+
+// Encipher a string.
+func (c *Compiled) Encipher(s string, k string, autoclave func(rune, rune) rune) (string, error) {
+	return c.transcode(s, k, autoclave, false)
+}
+
+// Decipher a string.
+func (c *Compiled) Decipher(s string, k string, autoclave func(rune, rune) rune) (string, error) {
+	return c.transcode(s, k, autoclave, true)
+}
+
+// Transcode a string, with the same treatment of keys and runes as a reciprocal table.
+func (c *Compiled) transcode(s string, k string, autoclave func(rune, rune) rune, decipher bool) (string, error) {
+	kn := c.normalizer
+	kn.Symbols = normalize.KeepSymbols
+	keyRunes := []rune(kn.Normalize(k))
+
+	s = c.normalizer.Normalize(s)
+	if len(keyRunes) == 0 && s != "" {
+		return "", errors.New("Key must not be empty")
+	}
+
+	var transcodedCharCount = 0
+	return strings.Map(func(r rune) rune {
+		k := c.normalizer.Fold(keyRunes[transcodedCharCount%len(keyRunes)], c.keys.Has)
+		row, ok := c.keys.Get(k)
+		if !ok {
+			// Rune `k` does not exist in keyAlphabet
+			return (-1)
+		}
+
+		// Look up the column of the input rune, and then the output rune in that column
+		src, dst := c.pts, c.rows[row]
+		if decipher {
+			src, dst = c.cts[row], c.ptRunes
+		}
+
+		f := c.normalizer.Fold(r, src.Has)
+		if col, ok := src.Get(f); ok {
+			// Transcoding successful
+			o := dst[col]
+			transcodedCharCount++
+			if autoclave != nil {
+				if newRune := autoclave(f, o); newRune != (-1) {
+					keyRunes = append(keyRunes, newRune)
+				}
+			}
+			return c.normalizer.Restore(r, f, o)
+		} else if !c.strict {
+			// Rune `r` does not exist in the source alphabet but we are not being strict
+			return r
+		}
+		// Rune `r` does not exist in the source alphabet
+		return (-1)
+	}, s), nil
+}
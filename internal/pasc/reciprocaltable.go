@@ -19,6 +19,9 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/merenbach/goldbug/internal/alphabet"
+	"github.com/merenbach/goldbug/internal/normalize"
+	"github.com/merenbach/goldbug/internal/trace"
 	"github.com/merenbach/goldbug/internal/translation"
 )
 
@@ -29,6 +32,21 @@ type ReciprocalTable struct {
 	KeyAlphabet string
 	PtAlphabet  string
 	CtAlphabets []string
+
+	// Normalizer prepares input text and keys before transcoding.
+	Normalizer normalize.Normalizer
+
+	// Trace, if set, receives an event for each input rune.
+	Trace *trace.Trace
+}
+
+// Indices maps each rune in a string to its position.
+func indices(s string) map[rune]int {
+	out := make(map[rune]int)
+	for i, r := range []rune(s) {
+		out[r] = i
+	}
+	return out
 }
 
 func makedicts(columnHeaders string, rowHeaders string, rows []string) (map[rune]map[rune]rune, map[rune]map[rune]rune, error) {
@@ -36,9 +54,15 @@ func makedicts(columnHeaders string, rowHeaders string, rows []string) (map[rune
 	ct2pt := make(map[rune]map[rune]rune)
 
 	keyRunes := []rune(rowHeaders)
-	if len(keyRunes) != len(rowHeaders) {
+	if len(keyRunes) != len(rows) {
 		return nil, nil, errors.New("Row headers must have same rune length as rows slice")
 	}
+	if err := alphabet.Unique(columnHeaders); err != nil {
+		return nil, nil, err
+	}
+	if err := alphabet.Unique(rowHeaders); err != nil {
+		return nil, nil, err
+	}
 
 	for i, r := range keyRunes {
 		ptTable := translation.Table{
@@ -101,6 +125,22 @@ func (tr *ReciprocalTable) Printable() (string, error) {
 	return out.String(), nil
 }
 
+// Matrix representation of this tabula recta.
+// Matrix places column headers in the first row and row headers in the first column.
+func (tr *ReciprocalTable) Matrix() ([][]string, error) {
+	keyRunes := []rune(tr.KeyAlphabet)
+	if len(keyRunes) != len(tr.CtAlphabets) {
+		return nil, errors.New("Row headers must have same rune length as rows slice")
+	}
+
+	out := make([][]string, 0, len(keyRunes)+1)
+	out = append(out, append([]string{""}, strings.Split(tr.PtAlphabet, "")...))
+	for i, r := range keyRunes {
+		out = append(out, append([]string{string(r)}, strings.Split(tr.CtAlphabets[i], "")...))
+	}
+	return out, nil
+}
+
 // // Encipher a plaintext rune with a given key alphabet rune.
 // // Encipher will return (-1, false) if the key rune is invalid.
 // // Encipher will return (-1, true) if the key rune is valid but the message rune is not.
@@ -133,76 +173,108 @@ func (tr *ReciprocalTable) Printable() (string, error) {
 // 	return (-1), true
 // }
 
-// Encipher a string.
-func (tr *ReciprocalTable) Encipher(s string, k string, autoclave func(rune, rune) rune) (string, error) {
+// A Transform transcodes successive portions of a message, keeping key state from one portion to the next.
+type Transform func(string) (string, error)
+
+// Encipherer returns a transform to encipher a message in portions.
+func (tr *ReciprocalTable) Encipherer(k string, autoclave func(rune, rune) rune) (Transform, error) {
 	pt2ct, _, err := makedicts(tr.PtAlphabet, tr.KeyAlphabet, tr.CtAlphabets)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
+	return tr.transform(pt2ct, k, autoclave, false), nil
+}
 
-	keyRunes := []rune(k)
-	var transcodedCharCount = 0
-	return strings.Map(func(r rune) rune {
-		k := keyRunes[transcodedCharCount%len(keyRunes)]
-		m, ok := pt2ct[k]
-		if !ok {
-			// Rune `k` does not exist in keyAlphabet
-			// TODO: avoid advancing on invalid key char
-			// TODO: avoid infinite loop upon _no_ valid key chars
-			return (-1)
-		}
+// Decipherer returns a transform to decipher a message in portions.
+func (tr *ReciprocalTable) Decipherer(k string, autoclave func(rune, rune) rune) (Transform, error) {
+	_, ct2pt, err := makedicts(tr.PtAlphabet, tr.KeyAlphabet, tr.CtAlphabets)
+	if err != nil {
+		return nil, err
+	}
+	return tr.transform(ct2pt, k, autoclave, true), nil
+}
 
-		if o, ok := m[r]; ok {
-			// Transcoding successful
-			transcodedCharCount++
-			if autoclave != nil {
-				if newRune := autoclave(r, o); newRune != (-1) {
-					keyRunes = append(keyRunes, newRune)
-				}
-			}
-			return o
-		} else if !tr.Strict {
-			// Rune `r` does not exist in ptAlphabet but we are not being struct
-			return r
-		}
-		// Rune `r` does not exist in ptAlphabet
-		return (-1)
-	}, s), nil
+// Encipher a string.
+func (tr *ReciprocalTable) Encipher(s string, k string, autoclave func(rune, rune) rune) (string, error) {
+	f, err := tr.Encipherer(k, autoclave)
+	if err != nil {
+		return "", err
+	}
+	return f(s)
 }
 
 // Decipher a string.
 func (tr *ReciprocalTable) Decipher(s string, k string, autoclave func(rune, rune) rune) (string, error) {
-	_, ct2pt, err := makedicts(tr.PtAlphabet, tr.KeyAlphabet, tr.CtAlphabets)
+	f, err := tr.Decipherer(k, autoclave)
 	if err != nil {
 		return "", err
 	}
+	return f(s)
+}
+
+// Transform a message with dictionaries keyed first by key rune and then by input rune.
+// Key symbols are left as-is, where they are invalid rather than silently removed or replaced.
+// The column recorded in a trace is that of the plaintext rune, whether enciphering or deciphering.
+func (tr *ReciprocalTable) transform(dicts map[rune]map[rune]rune, k string, autoclave func(rune, rune) rune, decipher bool) Transform {
+	kn := tr.Normalizer
+	kn.Symbols = normalize.KeepSymbols
+	keyRunes := []rune(kn.Normalize(k))
+
+	hasKey := func(r rune) bool {
+		_, ok := dicts[r]
+		return ok
+	}
+	rows, cols := indices(tr.KeyAlphabet), indices(tr.PtAlphabet)
+	var transcodedCharCount, runeCount = 0, 0
 
-	keyRunes := []rune(k)
-	var transcodedCharCount = 0
-	return strings.Map(func(r rune) rune {
-		k := keyRunes[transcodedCharCount%len(keyRunes)]
-		m, ok := ct2pt[k]
-		if !ok {
-			// Rune `k` does not exist in keyAlphabet
-			// TODO: avoid advancing on invalid key char
-			// TODO: avoid infinite loop upon _no_ valid key chars
-			return (-1)
+	return func(s string) (string, error) {
+		s = tr.Normalizer.Normalize(s)
+		if len(keyRunes) == 0 && s != "" {
+			return "", errors.New("Key must not be empty")
 		}
 
-		if o, ok := m[r]; ok {
-			// Transcoding successful
-			transcodedCharCount++
-			if autoclave != nil {
-				if newRune := autoclave(r, o); newRune != (-1) {
-					keyRunes = append(keyRunes, newRune)
+		return strings.Map(func(r rune) rune {
+			i := runeCount
+			runeCount++
+
+			k := tr.Normalizer.Fold(keyRunes[transcodedCharCount%len(keyRunes)], hasKey)
+			m, ok := dicts[k]
+			if !ok {
+				// Rune `k` does not exist in keyAlphabet
+				// TODO: avoid advancing on invalid key char
+				// TODO: avoid infinite loop upon _no_ valid key chars
+				tr.Trace.Skip(i, r, k)
+				return (-1)
+			}
+
+			f := tr.Normalizer.Fold(r, func(r rune) bool {
+				_, ok := m[r]
+				return ok
+			})
+			if o, ok := m[f]; ok {
+				// Transcoding successful
+				transcodedCharCount++
+				if tr.Trace != nil {
+					pt := f
+					if decipher {
+						pt = o
+					}
+					tr.Trace.Add(trace.Event{Index: i, Input: r, Key: k, Row: rows[k], Col: cols[pt], Output: tr.Normalizer.Restore(r, f, o), Action: trace.Transcoded})
+				}
+				if autoclave != nil {
+					if newRune := autoclave(f, o); newRune != (-1) {
+						keyRunes = append(keyRunes, newRune)
+					}
 				}
+				return tr.Normalizer.Restore(r, f, o)
+			} else if !tr.Strict {
+				// Rune `r` does not exist in the source alphabet but we are not being strict
+				tr.Trace.Pass(i, r, 0)
+				return r
 			}
-			return o
-		} else if !tr.Strict {
-			// Rune `r` does not exist in ctAlphabet but we are not being strict
-			return r
-		}
-		// Rune `r` does not exist in ctAlphabet
-		return (-1)
-	}, s), nil
+			// Rune `r` does not exist in the source alphabet
+			tr.Trace.Skip(i, r, 0)
+			return (-1)
+		}, s), nil
+	}
 }
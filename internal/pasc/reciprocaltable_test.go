@@ -14,6 +14,8 @@
 
 package pasc
 
+import "testing"
+
 // func TestReciprocalTable(t *testing.T) {
 // 	const alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
 
@@ -65,3 +67,21 @@ package pasc
 // 		}
 // 	}
 // }
+
+func TestReciprocalTable_emptyKey(t *testing.T) {
+	tr := ReciprocalTable{
+		PtAlphabet:  "ABC",
+		KeyAlphabet: "ABC",
+		CtAlphabets: []string{"ABC", "BCA", "CAB"},
+	}
+
+	if _, err := tr.Encipher("ABC", "", nil); err == nil {
+		t.Error("Expected error enciphering with an empty key")
+	}
+	if _, err := tr.Decipher("ABC", "", nil); err == nil {
+		t.Error("Expected error deciphering with an empty key")
+	}
+	if out, err := tr.Encipher("", "", nil); err != nil || out != "" {
+		t.Errorf("Expected an empty message to encipher to itself, but got %q and error %v", out, err)
+	}
+}
@@ -18,7 +18,9 @@ import (
 	"fmt"
 	"unicode/utf8"
 
+	"github.com/merenbach/goldbug/internal/normalize"
 	"github.com/merenbach/goldbug/internal/stringutil"
+	"github.com/merenbach/goldbug/internal/trace"
 )
 
 // TabulaRecta holds a tabula recta.
@@ -28,6 +30,12 @@ type TabulaRecta struct {
 	PtAlphabet  string
 	CtAlphabet  string
 	KeyAlphabet string
+
+	// Normalizer prepares input text and keys before transcoding.
+	Normalizer normalize.Normalizer
+
+	// Trace, if set, receives an event for each input rune.
+	Trace *trace.Trace
 }
 
 // MakeTabulaRecta creates a standard Caesar shift tabula recta.
@@ -55,6 +63,8 @@ func (tr *TabulaRecta) makereciprocaltable() (*ReciprocalTable, error) {
 		KeyAlphabet: tr.KeyAlphabet,
 		CtAlphabets: ctAlphabets,
 		Strict:      tr.Strict,
+		Normalizer:  tr.Normalizer,
+		Trace:       tr.Trace,
 	}
 
 	return &rt, nil
@@ -77,6 +87,15 @@ func (tr *TabulaRecta) Printable() (string, error) {
 	return rt.Printable()
 }
 
+// Matrix representation of this tabula recta.
+func (tr *TabulaRecta) Matrix() ([][]string, error) {
+	rt, err := tr.makereciprocaltable()
+	if err != nil {
+		return nil, err
+	}
+	return rt.Matrix()
+}
+
 // // Encipher a plaintext rune with a given key alphabet rune.
 // // Encipher will return (-1, false) if the key rune is invalid.
 // // Encipher will return (-1, true) if the key rune is valid but the message rune is not.
@@ -109,6 +128,24 @@ func (tr *TabulaRecta) Printable() (string, error) {
 // 	return (-1), true
 // }
 
+// Encipherer returns a transform to encipher a message in portions.
+func (tr *TabulaRecta) Encipherer(k string, autoclave func(rune, rune) rune) (Transform, error) {
+	rt, err := tr.makereciprocaltable()
+	if err != nil {
+		return nil, err
+	}
+	return rt.Encipherer(k, autoclave)
+}
+
+// Decipherer returns a transform to decipher a message in portions.
+func (tr *TabulaRecta) Decipherer(k string, autoclave func(rune, rune) rune) (Transform, error) {
+	rt, err := tr.makereciprocaltable()
+	if err != nil {
+		return nil, err
+	}
+	return rt.Decipherer(k, autoclave)
+}
+
 // Encipher a string.
 func (tr *TabulaRecta) Encipher(s string, k string, autoclave func(rune, rune) rune) (string, error) {
 	rt, err := tr.makereciprocaltable()
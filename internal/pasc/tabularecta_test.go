@@ -14,7 +14,13 @@
 
 package pasc
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+
+	"github.com/merenbach/goldbug/internal/normalize"
+	"github.com/merenbach/goldbug/internal/trace"
+)
 
 func TestTabulaRecta(t *testing.T) {
 	const alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
@@ -60,3 +66,70 @@ func TestTabulaRecta(t *testing.T) {
 		}
 	}
 }
+
+func TestTabulaRecta_Matrix(t *testing.T) {
+	tr := TabulaRecta{
+		PtAlphabet:  "ABC",
+		CtAlphabet:  "XYZ",
+		KeyAlphabet: "KLM",
+	}
+
+	expected := [][]string{
+		{"", "A", "B", "C"},
+		{"K", "X", "Y", "Z"},
+		{"L", "Y", "Z", "X"},
+		{"M", "Z", "X", "Y"},
+	}
+
+	if out, err := tr.Matrix(); err != nil {
+		t.Error("Error:", err)
+	} else if !reflect.DeepEqual(out, expected) {
+		t.Errorf("Expected matrix %q, but got %q", expected, out)
+	}
+}
+
+func TestTabulaRecta_Trace(t *testing.T) {
+	var tr trace.Trace
+	tableau := TabulaRecta{
+		PtAlphabet:  "ABC",
+		CtAlphabet:  "ABC",
+		KeyAlphabet: "ABC",
+		Trace:       &tr,
+	}
+
+	expected := trace.Trace{
+		{Index: 0, Input: 'A', Key: 'B', Row: 1, Col: 0, Output: 'B', Action: trace.Transcoded},
+		{Index: 1, Input: '-', Row: (-1), Col: (-1), Output: '-', Action: trace.PassedThrough},
+		{Index: 2, Input: 'B', Key: 'C', Row: 2, Col: 1, Output: 'A', Action: trace.Transcoded},
+	}
+
+	if out, err := tableau.Encipher("A-B", "BC", nil); err != nil {
+		t.Error("Error:", err)
+	} else if out != "B-A" {
+		t.Errorf("Expected %q, but got %q", "B-A", out)
+	}
+	if !reflect.DeepEqual(tr, expected) {
+		t.Errorf("Expected trace %+v, but got %+v", expected, tr)
+	}
+}
+
+func TestTabulaRecta_PreserveCase(t *testing.T) {
+	tr := TabulaRecta{
+		PtAlphabet:  Alphabet,
+		CtAlphabet:  Alphabet,
+		KeyAlphabet: Alphabet,
+		Strict:      true,
+		Normalizer:  normalize.Normalizer{Case: normalize.PreserveCase, StripDiacritics: true},
+	}
+
+	if out, err := tr.Encipher("Attack at Dawn, café", "lemon", nil); err != nil {
+		t.Error("Error:", err)
+	} else if out != "LxfopvefRnhroosp" {
+		t.Errorf("Expected %q, but got %q", "LxfopvefRnhroosp", out)
+	}
+	if out, err := tr.Decipher("LxfopvefRnhr", "LEMON", nil); err != nil {
+		t.Error("Error:", err)
+	} else if out != "AttackatDawn" {
+		t.Errorf("Expected %q, but got %q", "AttackatDawn", out)
+	}
+}
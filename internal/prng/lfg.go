@@ -40,6 +40,15 @@ type LFG struct {
 
 // Validate the settings for this LFG.
 func (g *LFG) validate() error {
+	switch {
+	case g.Modulus <= 0:
+		return errors.New("Modulus must be greater than zero")
+	case len(g.Seed) == 0:
+		return errors.New("At least one seed value is required")
+	case len(g.Taps) == 0:
+		return errors.New("At least one tap value is required")
+	}
+
 	for _, t := range g.Taps {
 		switch {
 		case t < 1:
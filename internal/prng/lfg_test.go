@@ -135,3 +135,21 @@ func TestMLFG(t *testing.T) {
 		}
 	}
 }
+
+func TestLFG_errors(t *testing.T) {
+	tables := []*LFG{
+		{Seed: []int{1}, Taps: []int{1}},
+		{Modulus: 32},
+		{Modulus: 32, Seed: []int{1}},
+		{Modulus: 32, Seed: []int{1}, Taps: []int{2}},
+	}
+
+	for _, table := range tables {
+		if _, err := table.IteratorA(); err == nil {
+			t.Errorf("Expected error creating ALFG iterator from %+v", table)
+		}
+		if _, err := table.IteratorM(); err == nil {
+			t.Errorf("Expected error creating MLFG iterator from %+v", table)
+		}
+	}
+}
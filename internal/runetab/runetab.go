@@ -0,0 +1,74 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package runetab provides lookup tables indexed by rune.
+package runetab
+
+// Runes below this limit are looked up in a slice rather than a map.
+// The limit covers the Latin, Greek, Cyrillic, Hebrew and Arabic blocks.
+const denseLimit = 0x800
+
+// An Index maps runes to their positions in a sequence.
+// An Index is immutable and safe for concurrent use once created.
+type Index struct {
+	dense  []int32
+	sparse map[rune]int
+}
+
+// New index of a sequence of runes.
+// The last position wins for any rune that occurs more than once.
+func New(rr []rune) *Index {
+	max := rune(-1)
+	for _, r := range rr {
+		if r > max {
+			max = r
+		}
+	}
+
+	var t Index
+	if max < denseLimit {
+		t.dense = make([]int32, max+1)
+		for i := range t.dense {
+			t.dense[i] = (-1)
+		}
+		for i, r := range rr {
+			t.dense[r] = int32(i)
+		}
+		return &t
+	}
+
+	t.sparse = make(map[rune]int, len(rr))
+	for i, r := range rr {
+		t.sparse[r] = i
+	}
+	return &t
+}
+
+// Get the position of a rune.
+func (t *Index) Get(r rune) (int, bool) {
+	if t.sparse != nil {
+		i, ok := t.sparse[r]
+		return i, ok
+	}
+	if r < 0 || int(r) >= len(t.dense) || t.dense[r] < 0 {
+		return 0, false
+	}
+	return int(t.dense[r]), true
+}
+
+// Has determines whether a rune is present.
+func (t *Index) Has(r rune) bool {
+	_, ok := t.Get(r)
+	return ok
+}
@@ -0,0 +1,43 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runetab
+
+import "testing"
+
+func TestIndex(t *testing.T) {
+	tables := []struct {
+		runes    string
+		r        rune
+		expected int
+		ok       bool
+	}{
+		{"ABC", 'A', 0, true},
+		{"ABC", 'C', 2, true},
+		{"ABC", 'D', 0, false},
+		{"ABC", (-1), 0, false},
+		{"ABCA", 'A', 3, true},
+		{"", 'A', 0, false},
+		{"ΑΒΓ", 'Γ', 2, true},
+		{"あいう", 'い', 1, true},
+		{"あいう", 'A', 0, false},
+	}
+
+	for _, table := range tables {
+		i, ok := New([]rune(table.runes)).Get(table.r)
+		if i != table.expected || ok != table.ok {
+			t.Errorf("Expected position of %q in %q to be (%d, %t), but got (%d, %t)", table.r, table.runes, table.expected, table.ok, i, ok)
+		}
+	}
+}
@@ -0,0 +1,1194 @@
+// Code generated by go generate; DO NOT EDIT.
+
+package smoketest
+
+// Fixtures holds encipherment test data for each cipher route, keyed by route name.
+var fixtures = map[string]string{
+	"affine": `[
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "AFCCX, BXSCY!",
+        "Slope": 7,
+        "Intercept": 3,
+        "Strict": false
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "AFCCXBXSCY",
+        "Slope": 7,
+        "Intercept": 3,
+        "Strict": true
+    },
+    {
+        "Alphabet": "",
+        "Input": "AFFINE CIPHER",
+        "Output": "IHHWVC SWFRCP",
+        "Slope": 5,
+        "Intercept": 8,
+        "Strict": false
+    },
+    {
+        "Alphabet": "",
+        "Input": "AFFINE CIPHER",
+        "Output": "IHHWVCSWFRCP",
+        "Slope": 5,
+        "Intercept": 8,
+        "Strict": true
+    }
+]`,
+	"atbash": `[
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "SVOOL, DLIOW!",
+        "Strict": false
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "SVOOLDLIOW",
+        "Strict": true
+    },
+    {
+        "Alphabet": "",
+        "Input": "ATBASH CIPHER",
+        "Output": "ZGYZHS XRKSVI",
+        "Strict": false
+    },
+    {
+        "Alphabet": "",
+        "Input": "ATBASHCIPHER",
+        "Output": "ZGYZHSXRKSVI",
+        "Strict": true
+    },
+    {
+        "Alphabet": "0123456789",
+        "Input": "A12345A",
+        "Output": "A87654A",
+        "Strict": false
+    },
+    {
+        "Alphabet": "0123456789",
+        "Input": "A12345A",
+        "Output": "87654",
+        "Strict": true
+    },
+    {
+        "Alphabet": "12345",
+        "Input": "A12345A",
+        "Output": "A54321A",
+        "Strict": false
+    },
+    {
+        "Alphabet": "12345",
+        "Input": "A12345A",
+        "Output": "54321",
+        "Strict": true
+    }
+]`,
+	"beaufort": `[
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "HYTPZ, SSAPM!",
+        "Key": "OCEANOGRAPHYWHAT",
+        "Strict": false
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "HYTPZSSAPM",
+        "Key": "OCEANOGRAPHYWHAT",
+        "Strict": true
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "JMFFC, UCZFN!",
+        "Key": "Q",
+        "Strict": false
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "JMFFCUCZFN",
+        "Key": "Q",
+        "Strict": true
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "DWCVM, VAXZX!",
+        "Key": "KANGAROO",
+        "Strict": false
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "DWCVMVAXZX",
+        "Key": "KANGAROO",
+        "Strict": true
+    }
+]`,
+	"caesar": `[
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "HELLO, WORLD!",
+        "Shift": 0,
+        "Strict": false
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "HELLOWORLD",
+        "Shift": 0,
+        "Strict": true
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "HELLO, WORLD!",
+        "Shift": 26,
+        "Strict": false
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "HELLOWORLD",
+        "Shift": 26,
+        "Strict": true
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "KHOOR, ZRUOG!",
+        "Shift": 3,
+        "Strict": false
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "KHOORZRUOG",
+        "Shift": 3,
+        "Strict": true
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "YVCCF, NFICU!",
+        "Shift": 43,
+        "Strict": false
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "YVCCFNFICU",
+        "Shift": 43,
+        "Strict": true
+    },
+    {
+        "Alphabet": "0123456789",
+        "Input": "A12345A",
+        "Output": "A45678A",
+        "Shift": 3,
+        "Strict": false
+    },
+    {
+        "Alphabet": "0123456789",
+        "Input": "A12345A",
+        "Output": "45678",
+        "Shift": 3,
+        "Strict": true
+    },
+    {
+        "Alphabet": "12345",
+        "Input": "A12345A",
+        "Output": "A45123A",
+        "Shift": 3,
+        "Strict": false
+    },
+    {
+        "Alphabet": "12345",
+        "Input": "A12345A",
+        "Output": "45123",
+        "Shift": 3,
+        "Strict": true
+    }
+]`,
+	"chaocipher": `[
+    {
+        "Alphabet": "",
+        "Left": "HXUCZVAMDSLKPEFJRIGTWOBNYQ",
+        "Right": "PTLNBQDEOYSFAVZKGJRIHWXUMC",
+        "Input": "WELLDONEISBETTERTHANWELLSAID",
+        "Output": "OAHQHCNYNXTSZJRRHJBYHQKSOUJY"
+    },
+    {
+        "Alphabet": "",
+        "Left": "CHAOS",
+        "Right": "BYRNE",
+        "Input": "ATTACK AT DAWN!",
+        "Output": "BTDAAF ZS ZWKR!"
+    },
+    {
+        "Alphabet": "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789",
+        "Left": "KEY",
+        "Right": "",
+        "Strict": true,
+        "Input": "MEET ME AT 10",
+        "Output": "LAYPG95JVS"
+    }
+]`,
+	"checkerboard": `[
+    {
+        "Alphabet": "",
+        "Strict": true,
+        "Input": "ATTACK AT DAWN",
+        "Output": "3113212731223655"
+    },
+    {
+        "Alphabet": "",
+        "Input": "ATTACK AT DAWN",
+        "Output": "31132127 31 223655"
+    },
+    {
+        "Alphabet": "ATONESIRBCDFGHJKLMPQUVWXYZ.0123456789",
+        "Digits": "9876543210",
+        "Blanks": [8, 1, 4],
+        "Input": "MEET AT 10.",
+        "Output": "59337 97 181950"
+    }
+]`,
+	"decimation": `[
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "HELLO, WORLD!",
+        "Multiplier": 1,
+        "Strict": false
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "HELLOWORLD",
+        "Multiplier": 1,
+        "Strict": true
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "XCZZU, YUPZV!",
+        "Multiplier": 7,
+        "Strict": false
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "XCZZUYUPZV",
+        "Multiplier": 7,
+        "Strict": true
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "TWPPM, EMJPX!",
+        "Multiplier": 25,
+        "Strict": false
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "TWPPMEMJPX",
+        "Multiplier": 25,
+        "Strict": true
+    },
+    {
+        "Alphabet": "0123456789",
+        "Input": "A12345A",
+        "Output": "A36925A",
+        "Multiplier": 3,
+        "Strict": false
+    },
+    {
+        "Alphabet": "0123456789",
+        "Input": "A12345A",
+        "Output": "36925",
+        "Multiplier": 3,
+        "Strict": true
+    },
+    {
+        "Alphabet": "12345",
+        "Input": "A12345A",
+        "Output": "A14253A",
+        "Multiplier": 3,
+        "Strict": false
+    },
+    {
+        "Alphabet": "12345",
+        "Input": "A12345A",
+        "Output": "14253",
+        "Multiplier": 3,
+        "Strict": true
+    }
+]`,
+	"dellaporta": `[
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "OSNYI, CLJYX!",
+        "Key": "OCEANOGRAPHYWHAT",
+        "Strict": false
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "OSNYICLJYX",
+        "Key": "OCEANOGRAPHYWHAT",
+        "Strict": true
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "PZTTG, BGJTY!",
+        "Key": "Q",
+        "Strict": false
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "PZTTGBGJTY",
+        "Key": "Q",
+        "Strict": true
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "ZRROB, BHKQQ!",
+        "Key": "KANGAROO",
+        "Strict": false
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "ZRROBBHKQQ",
+        "Key": "KANGAROO",
+        "Strict": true
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "QLKYV, VSIYI!",
+        "Key": "OCEANOGRAPHYWHAT",
+        "Keyword": "KEYWORD",
+        "Strict": false
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "SMDDW, OWXDL!",
+        "Key": "Q",
+        "Keyword": "PORTA",
+        "Strict": false
+    }
+]`,
+	"enigma": `[
+    {
+        "Rotors": "",
+        "Input": "AAAAA",
+        "Output": "BDZGO"
+    },
+    {
+        "Rotors": "II IV V",
+        "Reflector": "B",
+        "Rings": "02 21 12",
+        "Positions": "BLA",
+        "Plugboard": "AV BS CG DL FU HZ IN KM OW RX",
+        "Input": "AUFKLXABTEILUNGXVONXKURTINOWAXKURTINOWAXNORDWESTLXSEBEZXSEBEZXUAFFLIEGERSTRASZERIQTUNGXDUBROWKIXDUBROWKIXOPOTSCHKAXOPOTSCHKAXUMXEINSAQTDREINULLXUHRANGETRETENXANGRIFFXINFXRGTX",
+        "Output": "EDPUDNRGYSZRCXNUYTPOMRMBOFKTBZREZKMLXLVEFGUEYSIOZVEQMIKUBPMMYLKLTTDEISMDICAGYKUACTCDOMOHWXMUUIAUBSTSLRNBZSZWNRFXWFYSSXJZVIJHIDISHPRKLKAYUPADTXQSPINQMATLPIFSVKDASCTACDPBOPVHJK"
+    },
+    {
+        "Rotors": "Beta I II III",
+        "Reflector": "B-thin",
+        "Rings": "ABCD",
+        "Positions": "AXYZ",
+        "Input": "THEQUICKBROWNFOXJUMPSOVERTHELAZYDOG",
+        "Output": "BOGVVBEBCTZDXYWGQJWVBPQIHNUOIDRXEHW"
+    },
+    {
+        "Rotors": "Beta II IV I",
+        "Reflector": "B-thin",
+        "Rings": "AAAV",
+        "Positions": "VJNA",
+        "Plugboard": "AT BL DF GJ HM NW OP QY RZ VX",
+        "Input": "VONVONJLOOKSJHFFTTTEINSEINSDREIZWOYYQNNSNEUNINHALTXXBEIANGRIFFUNTERWASSERGEDRUECKTYWABOSXLETZTERGEGNERSTANDNULACHTDREINULUHRMARQUANTONJOTANEUNACHTSEYHSDREIYZWOZWONULGRADYACHTSMYSTOSSENACHXEKNSVIERMBFAELLTYNNNNNNOOOVIERYSICHTEINSNULL",
+        "Output": "NCZWVUSXPNYMINHZXMQXSFWXWLKJAHSHNMCOCCAKUQPMKCSMHKSEINJUSBLKIOSXCKUBHMLLXCSJUSRRDVKOHULXWCCBGVLIYXEOAHXRHKKFVDREWEZLXOBAFGYUJQUKGRTVUKAMEURBVEKSUHHVOYHABCJWMAKLFKLMYFVNRIZRVVRTKOFDANJMOLBGFFLEOPRGTFLVRHOWOPBEKVWMUQFMPWPARMFHAGKXIIBG"
+    }
+]`,
+	"gronsfeld": `[
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "HELLO, WORLD!",
+        "Key": "0",
+        "Strict": false
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "HELLOWORLD",
+        "Key": "0",
+        "Strict": true
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "JHMOQ, YRSOF!",
+        "Key": "23132",
+        "Strict": false
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "JHMOQYRSOF",
+        "Key": "23132",
+        "Strict": true
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "KMUNX, WPRNG!",
+        "Key": "389290102394957",
+        "Strict": false
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "KMUNXWPRNG",
+        "Key": "389290102394957",
+        "Strict": true
+    }
+]`,
+	"homophonic": `[
+    {
+        "Input": "ATTACK AT DAWN",
+        "Output": "001941260210 4556 03582213"
+    },
+    {
+        "Strict": true,
+        "Input": "ATTACK AT DAWN",
+        "Output": "001941260210455603582213"
+    },
+    {
+        "Table": {
+            "A": ["#", "$"],
+            "B": ["("],
+            "C": ["@", ")"],
+            "D": ["*", "!"],
+            "E": ["%", "&", "^"]
+        },
+        "Input": "BEAD A DEED",
+        "Output": "(%#* $ !&^*"
+    },
+    {
+        "Table": {
+            "E": ["101", "733"],
+            "H": ["204"],
+            "L": ["350", "482", "519"],
+            "O": ["666", "007"]
+        },
+        "Input": "HELLO, HELLO",
+        "Output": "204101350482666, 204733519350007"
+    }
+]`,
+	"keyword": `[
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "CRHHL, WLQHG!",
+        "Keyword": "KANGAROO",
+        "Strict": false
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "CRHHLWLQHG",
+        "Keyword": "KANGAROO",
+        "Strict": true
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "HELLO, WORLD!",
+        "Keyword": "ABC",
+        "Strict": false
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "HELLOWORLD",
+        "Keyword": "ABC",
+        "Strict": true
+    }
+]`,
+	"lorenz": `[
+    {
+        "Positions": null,
+        "Input": "HELLO WORLD",
+        "Output": "MI3RSX54D5E"
+    },
+    {
+        "Positions": [1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12],
+        "Input": "ATTACK AT DAWN. 0600 HOURS",
+        "Output": "MPBHF/PD4GNNXWLRSYTIW5F9XNQS"
+    }
+]`,
+	"m209": `[
+    {
+        "Lugs": "",
+        "Positions": "",
+        "Input": "ABC",
+        "Output": "ZYX"
+    },
+    {
+        "Pins": [
+            "ABDHIKMNSTVW",
+            "ADEGJKLORSUX",
+            "ABGHJLMNRSTUX",
+            "CEFHIMNPSTU",
+            "BDEFHIMNPS",
+            "ABDHKNOQ"
+        ],
+        "Lugs": "3-6 0-6 1-6 1-5 4-5 0-4*4 2-0*10 2-5*2 0-5*6",
+        "Positions": "",
+        "Input": "AAAAAAAAAAAAAAAAAAAAAAAAAA",
+        "Output": "TNJUWAUQTKCZKNUTOTBCWARMIO"
+    },
+    {
+        "Pins": [
+            "ABDHIKMNSTVW",
+            "ADEGJKLORSUX",
+            "ABGHJLMNRSTUX",
+            "CEFHIMNPSTU",
+            "BDEFHIMNPS",
+            "ABDHKNOQ"
+        ],
+        "Lugs": "3-6 0-6 1-6 1-5 4-5 0-4*4 2-0*10 2-5*2 0-5*6",
+        "Positions": "GAQLMB",
+        "Input": "ATTACK AT DAWN",
+        "Output": "ZSOARYVCBNBYGV"
+    }
+]`,
+	"nihilist": `[
+    {
+        "Alphabet": "",
+        "Keyword": "ZEBRAS",
+        "Key": "RUSSIAN",
+        "Input": "DYNAMITE WINTER PALACE",
+        "Output": "37 106 62 36 67 47 86 26 104 53 62 77 27 55 57 66 55 36 54 27"
+    },
+    {
+        "Alphabet": "",
+        "Keyword": "ZEBRAS",
+        "Key": "RUSSIAN",
+        "Groups": true,
+        "Input": "DYNAMITE WINTER PALACE",
+        "Output": "3706623667478626045362772755576655365427"
+    },
+    {
+        "Alphabet": "",
+        "Keyword": "JUMP",
+        "Key": "KEY",
+        "Input": "HELLO, JOE",
+        "Output": "65 48 88 67 65 65 74 48"
+    },
+    {
+        "Alphabet": "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789",
+        "Keyword": "SECRET",
+        "Key": "KEY",
+        "Input": "MEET AT 10",
+        "Output": "66 24 63 47 28 66 86 65"
+    }
+]`,
+	"nihilisttransposition": `[
+    {
+        "Key": "CAB",
+        "Input": "ABCDEFGHIJKLM",
+        "Output": "EFDHIGBCAMKLJ"
+    },
+    {
+        "Key": "CAB",
+        "ByColumn": true,
+        "Input": "ABCDEFGHIJKLM",
+        "Output": "EHBFICDGAKLMJ"
+    },
+    {
+        "Key": "ZEBRA",
+        "ByColumn": true,
+        "Input": "WEAREDISCOVEREDFLEEATONCE",
+        "Output": "EDOAENRSEAOEILECECERTVDFW"
+    },
+    {
+        "Key": "ZEBRA",
+        "Input": "WEAREDISCOVEREDFLEEATONCEQ",
+        "Output": "ENOCTDREEVOSICDAELEFEAERWQ"
+    }
+]`,
+	"otp": `[
+    {
+        "Alphabet": "",
+        "Pad": "XMCKL",
+        "Input": "HELLO",
+        "Output": "EQNVZ"
+    },
+    {
+        "Alphabet": "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789",
+        "Pad": "XMCKL QWERT",
+        "Input": "MEET AT 10",
+        "Output": "9QG3 L9 N4"
+    }
+]`,
+	"pipeline": `[
+    {
+        "Pipeline": [
+            {"cipher": "keyword", "keyword": "KANGAROO"},
+            {"cipher": "caesar", "shift": 3}
+        ],
+        "Input": "HELLO, WORLD!",
+        "Output": "FUKKO, ZOTKJ!"
+    },
+    {
+        "Pipeline": [
+            {"cipher": "vigenere", "countersign": "LEMON"},
+            {"cipher": "atbash"}
+        ],
+        "Input": "ATTACKATDAWN",
+        "Output": "OCULKEVUIMSI"
+    }
+]`,
+	"polybius": `[
+    {
+        "Alphabet": "",
+        "Input": "HELLO WORLD",
+        "Output": "2315313134 5234423114"
+    },
+    {
+        "Alphabet": "",
+        "Strict": true,
+        "Input": "HELLO WORLD",
+        "Output": "23153131345234423114"
+    },
+    {
+        "Alphabet": "",
+        "Input": "JUMP",
+        "Output": "24453235"
+    },
+    {
+        "Alphabet": "",
+        "RowLabels": "ADFGX",
+        "ColumnLabels": "ADFGX",
+        "Input": "HELLO",
+        "Output": "DFAXFAFAFG"
+    },
+    {
+        "Alphabet": "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789",
+        "Input": "HELLO1",
+        "Output": "221526263354"
+    },
+    {
+        "Alphabet": "ABCDEFGHIJKLMNOPQRSTUVWXYZ",
+        "Merge": "ZS",
+        "Input": "ZIGZAG",
+        "Output": "412321411121"
+    }
+]`,
+	"portax": `[
+    {
+        "Alphabet": "",
+        "Input": "ATTACKATDAWN",
+        "Output": "TQQBIQTNGBIH",
+        "Key": "NEW"
+    },
+    {
+        "Alphabet": "",
+        "Input": "ATTACKATDAWN",
+        "Output": "UQWWLGBZXBEV",
+        "Key": "PORTAX"
+    },
+    {
+        "Alphabet": "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789",
+        "Input": "MEET ME 2NIGHT",
+        "Output": "JGCZII0DJK1R",
+        "Key": "KEY"
+    }
+]`,
+	"railfence": `[
+    {
+        "Input": "WEAREDISCOVEREDFLEEATONCE",
+        "Output": "WECRLTEERDSOEEFEAOCAIVDEN",
+        "Rows": 3
+    },
+    {
+        "Input": "I_REALLY_LIKE_PUZZLES",
+        "Output": "IA_EZS_ELYLK_UZERLIPL",
+        "Rows": 3
+    },
+    {
+        "Input": "MEETMETONIGHTQXZ",
+        "Output": "MEMTNGTXETEOIHQZ",
+        "Rows": 2
+    },
+    {
+        "Input": "MEETMETONIGHTQXZ",
+        "Output": "MMNTETEOIHQZETGX",
+        "Rows": 3
+    },
+    {
+        "Input": "AMANLAUGHINGHISHEADOFFYZ",
+        "Output": "AALUHNHSEDFYMNAGIGIHAOFZ",
+        "Rows": 2
+    },
+    {
+        "Input": "HELLO, WORLD!",
+        "Output": "HELLO, WORLD!",
+        "Rows": 1
+    },
+    {
+        "Input": "WEAREDISCOVEREDFLEEATONCE",
+        "Output": "ERDSOEEFEAOCAIVDENWECRLTE",
+        "Rows": 3,
+        "Key": "312"
+    },
+    {
+        "Input": "WEAREDISCOVEREDFLEEATONCE",
+        "Output": "RSEFACWAEICVRDLETNEEDOEEO",
+        "Rows": 3,
+        "Offset": 1
+    }
+]`,
+	"rot13": `[
+    {
+        "Input": "HELLO, WORLD!",
+        "Output": "URYYB, JBEYQ!",
+        "Strict": false
+    },
+    {
+        "Input": "HELLO, WORLD!",
+        "Output": "URYYBJBEYQ",
+        "Strict": true
+    },
+    {
+        "Input": "URYYB, JBEYQ!",
+        "Output": "HELLO, WORLD!",
+        "Strict": false
+    },
+    {
+        "Input": "URYYB, JBEYQ!",
+        "Output": "HELLOWORLD",
+        "Strict": true
+    }
+]`,
+	"scytale": `[
+    {
+        "Input": "Iamhurtverybadlyhelp",
+        "Output": "Iryyatbhmvaehedlurlp",
+        "Turns": 5
+    },
+    {
+        "Input": "Thequickbrownfoxjumpsoverthelazydog",
+        "Output": "Tolhxaejzquyumdipocsgkobvreorwtnhfe",
+        "Turns": 14
+    },
+    {
+        "Input": "HELPMEIAMUNDERATTACK",
+        "Output": "HENTEIDTLAEAPMRCMUAK",
+        "Turns": 5
+    },
+    {
+        "Input": "HELLO, WORLD!",
+        "Output": "HELLO, WORLD!",
+        "Turns": 1
+    }
+]`,
+	"solitaire": `[
+    {
+        "Key": "",
+        "Input": "AAAAAAAAAAAAAAA",
+        "Output": "EXKYIZSGEHUNTIQ"
+    },
+    {
+        "Key": "f",
+        "Input": "AAAAAAAAAAAAAAA",
+        "Output": "XYIUQBMHKKJBEGY"
+    },
+    {
+        "Key": "CRYPTONOMICON",
+        "Input": "AAAAAAAAAAAAAAAAAAAAAAAAA",
+        "Output": "SUGSRSXSWQRMXOHIPBFPXARYQ"
+    },
+    {
+        "Key": "CRYPTONOMICON",
+        "Input": "SOLITAIREX",
+        "Output": "KIRAKSFJAN"
+    }
+]`,
+	"trithemius": `[
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "HFNOS, BUYTM!",
+        "Strict": false
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "HFNOSBUYTM",
+        "Strict": true
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "KIQRV, EXBWP!",
+        "Offset": 3,
+        "Strict": false
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "HDJIK, RIKDU!",
+        "Offset": 0,
+        "Step": -1,
+        "Strict": false
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "GBGEF, LBCUK!",
+        "Offset": 25,
+        "Step": -2,
+        "Strict": false
+    }
+]`,
+	"variantbeaufort": `[
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "TCHLB, IIALO!",
+        "Key": "OCEANOGRAPHYWHAT",
+        "Strict": false
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "TCHLBIIALO",
+        "Key": "OCEANOGRAPHYWHAT",
+        "Strict": true
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "HELLO, WORLD!",
+        "Key": "A",
+        "Strict": false
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "HELLOWORLD",
+        "Key": "A",
+        "Strict": true
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "ROVVY, GYBVN!",
+        "Key": "Q",
+        "Strict": false
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "ROVVYGYBVN",
+        "Key": "Q",
+        "Strict": true
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "XEYFO, FADBD!",
+        "Key": "KANGAROO",
+        "Strict": false
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "XEYFOFADBD",
+        "Key": "KANGAROO",
+        "Strict": true
+    }
+]`,
+	"vernam": `[
+    {
+        "Key": "VERNAM",
+        "Pad": "",
+        "Input": "ATTACK AT DAWN",
+        "Output": "XZGKFWG4G3R8FF"
+    },
+    {
+        "Key": "",
+        "Pad": "QWERTYUIOPASDFGHJKLZXCVBNM/98543",
+        "Input": "MEET AT 10",
+        "Output": "JL/GHPQ4AEY"
+    }
+]`,
+	"vic": `[
+    {
+        "Phrase": "I DREAM OF JEANNIE WITH THE LIGHT BROWN HAIR",
+        "Date": "741776",
+        "PersonalNumber": 6,
+        "Indicator": "77651",
+        "Input": "WE ARE PLEASED TO HEAR OF YOUR SUCCESS IN ESTABLISHING YOUR FALSE IDENTITY.",
+        "Output": "501122111103428108075404616597639519340108508565077874916940776512300597806825987609357082"
+    },
+    {
+        "Phrase": "ТОЛЬКО СЛЫШНО НА УЛИЦЕ ГДЕ-ТО ОДИНОКАЯ БРОДИТ ГАРМОНЬ",
+        "Date": "391945",
+        "PersonalNumber": 13,
+        "Indicator": "20818",
+        "Alphabet": "ОЕАИНТСРВЛКМДПУЯЫЬГЗБЧЙХЖШЮЦЩЭФЪЁ.",
+        "Blanks": [2, 5, 8],
+        "Input": "ВСТРЕЧА НА МОСТУ В ПОЛНОЧЬ.",
+        "Output": "706074389565772081827887406752515944793"
+    },
+    {
+        "Phrase": "I DREAM OF JEANNIE WITH THE LIGHT BROWN HAIR",
+        "Date": "741776",
+        "PersonalNumber": 6,
+        "Indicator": "77651",
+        "Alphabet": "ATONESIRBCDFGHJKLMPQUVWXYZ./",
+        "Blanks": [8, 9],
+        "Input": "HELLO WORLD",
+        "Output": "776517974337007077767"
+    }
+]`,
+	"vigenere": `[
+    {
+        "Alphabet": "",
+        "Input": "",
+        "Output": "",
+        "Key": "",
+        "Autokey": 0,
+        "Strict": false
+    },
+    {
+        "Alphabet": "",
+        "Input": "",
+        "Output": "",
+        "Key": "OCEANOGRAPHYWHAT",
+        "Autokey": 0,
+        "Strict": false
+    },
+    {
+        "Alphabet": "",
+        "Input": "",
+        "Output": "",
+        "Key": "OCEANOGRAPHYWHAT",
+        "Autokey": 0,
+        "Strict": true
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "HELLO, WORLD!",
+        "Key": "A",
+        "Autokey": 0,
+        "Strict": false
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "HELLOWORLD",
+        "Key": "A",
+        "Autokey": 0,
+        "Strict": true
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "VGPLB, KUILS!",
+        "Key": "OCEANOGRAPHYWHAT",
+        "Autokey": 0,
+        "Strict": false
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "VGPLBKUILS",
+        "Key": "OCEANOGRAPHYWHAT",
+        "Autokey": 0,
+        "Strict": true
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "XUBBE, MEHBT!",
+        "Key": "Q",
+        "Autokey": 0,
+        "Strict": false
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "XUBBEMEHBT",
+        "Key": "Q",
+        "Autokey": 0,
+        "Strict": true
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "REYRO, NCFVD!",
+        "Key": "KANGAROO",
+        "Autokey": 0,
+        "Strict": false
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "REYRONCFVD",
+        "Key": "KANGAROO",
+        "Autokey": 0,
+        "Strict": true
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "VGPLB, KUILS!",
+        "Key": "OCEANOGRAPHYWHAT",
+        "Autokey": 1,
+        "Strict": false
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "VGPLBKUILS",
+        "Key": "OCEANOGRAPHYWHAT",
+        "Autokey": 1,
+        "Strict": true
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "HLPWZ, KKFCO!",
+        "Key": "A",
+        "Autokey": 1,
+        "Strict": false
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "HLPWZKKFCO",
+        "Key": "A",
+        "Autokey": 1,
+        "Strict": true
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "XLPWZ, KKFCO!",
+        "Key": "Q",
+        "Autokey": 1,
+        "Strict": false
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "XLPWZKKFCO",
+        "Key": "Q",
+        "Autokey": 1,
+        "Strict": true
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "REYRO, NCFSH!",
+        "Key": "KANGAROO",
+        "Autokey": 1,
+        "Strict": false
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "REYRONCFSH",
+        "Key": "KANGAROO",
+        "Autokey": 1,
+        "Strict": true
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "VGPLB, KUILS!",
+        "Key": "OCEANOGRAPHYWHAT",
+        "Autokey": 2,
+        "Strict": false
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "VGPLBKUILS",
+        "Key": "OCEANOGRAPHYWHAT",
+        "Autokey": 2,
+        "Strict": true
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "HLWHV, RFWHK!",
+        "Key": "A",
+        "Autokey": 2,
+        "Strict": false
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "HLWHVRFWHK",
+        "Key": "A",
+        "Autokey": 2,
+        "Strict": true
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "XBMXL, HVMXA!",
+        "Key": "Q",
+        "Autokey": 2,
+        "Strict": false
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "XBMXLHVMXA",
+        "Key": "Q",
+        "Autokey": 2,
+        "Strict": true
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "REYRO, NCFCH!",
+        "Key": "KANGAROO",
+        "Autokey": 2,
+        "Strict": false
+    },
+    {
+        "Alphabet": "",
+        "Input": "HELLO, WORLD!",
+        "Output": "REYRONCFCH",
+        "Key": "KANGAROO",
+        "Autokey": 2,
+        "Strict": true
+    }
+]`,
+}
@@ -0,0 +1,90 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build ignore
+// +build ignore
+
+// This program generates fixtures.go from the encipherment test data of each cipher package,
+// along with test data in this package for routes that are not backed by a single cipher package.
+// It can be invoked by running `go generate`.
+package main
+
+import (
+	"bytes"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+var tmpl = template.Must(template.New("fixtures").Parse(`// Code generated by go generate; DO NOT EDIT.
+
+package smoketest
+
+// Fixtures holds encipherment test data for each cipher route, keyed by route name.
+var fixtures = map[string]string{
+{{- range $name, $data := .}}
+	{{printf "%q" $name}}: ` + "`{{$data}}`" + `,
+{{- end}}
+}
+`))
+
+func main() {
+	paths, err := filepath.Glob(filepath.Join("..", "..", "pkg", "*", "testdata", "cipher_encipher.json"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	sort.Strings(paths)
+
+	routes, err := filepath.Glob(filepath.Join("testdata", "*.json"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	sort.Strings(routes)
+
+	data := make(map[string]string)
+	for _, p := range paths {
+		data[filepath.Base(filepath.Dir(filepath.Dir(p)))] = load(p)
+	}
+	for _, p := range routes {
+		data[strings.TrimSuffix(filepath.Base(p), ".json")] = load(p)
+	}
+
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, data); err != nil {
+		log.Fatal(err)
+	}
+	out, err := format.Source(b.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile("fixtures.go", out, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// Load a fixture file for embedding in a raw string literal.
+func load(p string) string {
+	bb, err := ioutil.ReadFile(p)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if bytes.ContainsRune(bb, '`') {
+		log.Fatalf("Fixture %s contains a backquote", p)
+	}
+	return strings.TrimSpace(string(bb))
+}
@@ -0,0 +1,269 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package smoketest validates a deployed gold-bug function against golden test vectors.
+package smoketest
+
+//go:generate go run gen.go
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// Statuses understood by CodeDeploy lifecycle hooks.
+const (
+	Succeeded = "Succeeded"
+	Failed    = "Failed"
+)
+
+// Default number of vectors to check at once.
+const defaultWorkers = 8
+
+// Fixture field names mapped to API payload field names.
+var fieldNames = map[string]string{
+	"Alphabet":       "alphabet",
+	"Blanks":         "blanks",
+	"ByColumn":       "byColumn",
+	"ColumnLabels":   "columnLabels",
+	"Date":           "date",
+	"Digits":         "digits",
+	"Groups":         "groups",
+	"Indicator":      "indicator",
+	"Input":          "message",
+	"Intercept":      "shift",
+	"Key":            "countersign",
+	"Keyword":        "keyword",
+	"Left":           "left",
+	"Lugs":           "lugs",
+	"Merge":          "merge",
+	"Multiplier":     "multiplier",
+	"Offset":         "offset",
+	"Pad":            "pad",
+	"PersonalNumber": "personalNumber",
+	"Phrase":         "phrase",
+	"Pins":           "pins",
+	"Pipeline":       "pipeline",
+	"Plugboard":      "plugboard",
+	"Positions":      "positions",
+	"Reflector":      "reflector",
+	"Right":          "right",
+	"Rings":          "rings",
+	"Rotors":         "rotors",
+	"RowLabels":      "rowLabels",
+	"Rows":           "rows",
+	"Shift":          "shift",
+	"Slope":          "multiplier",
+	"Step":           "step",
+	"Strict":         "strict",
+	"Table":          "table",
+	"Turns":          "turns",
+}
+
+// Autokey fixture values mapped to API payload field names.
+var autokeyNames = map[float64]string{
+	1: "textAutoclave",
+	2: "keyAutoclave",
+}
+
+// An Invoker invokes the function under test with a raw JSON payload.
+type Invoker interface {
+	Invoke(payload []byte) ([]byte, error)
+}
+
+// A Reporter sends a lifecycle hook execution status to CodeDeploy.
+type Reporter interface {
+	Report(status string) error
+}
+
+// A Vector is a golden test vector for a cipher route.
+type Vector struct {
+	Cipher  string
+	Payload map[string]interface{}
+	Output  string
+}
+
+// Payload converts a fixture entry into an API payload.
+func payload(entry map[string]interface{}) (map[string]interface{}, error) {
+	out := make(map[string]interface{})
+	for k, v := range entry {
+		switch k {
+		case "Output":
+			continue
+		case "Autokey":
+			n, ok := v.(float64)
+			if !ok {
+				return nil, fmt.Errorf("Invalid autokey setting %v", v)
+			}
+			if name, ok := autokeyNames[n]; ok {
+				out[name] = true
+			}
+		default:
+			name, ok := fieldNames[k]
+			if !ok {
+				return nil, fmt.Errorf("Unknown fixture field %q", k)
+			}
+			out[name] = v
+		}
+	}
+	return out, nil
+}
+
+// Vectors for the given cipher routes, drawn from encipherment test data.
+// Vectors skips any route without test data.
+func Vectors(routes []string) ([]Vector, error) {
+	var out []Vector
+	for _, name := range routes {
+		data, ok := fixtures[name]
+		if !ok {
+			log.Printf("No test vectors for cipher %q", name)
+			continue
+		}
+
+		var entries []map[string]interface{}
+		if err := json.Unmarshal([]byte(data), &entries); err != nil {
+			return nil, fmt.Errorf("Could not parse test data for cipher %q: %v", name, err)
+		}
+
+		for _, e := range entries {
+			p, err := payload(e)
+			if err != nil {
+				return nil, fmt.Errorf("Could not convert test data for cipher %q: %v", name, err)
+			}
+			output, _ := e["Output"].(string)
+			out = append(out, Vector{Cipher: name, Payload: p, Output: output})
+		}
+	}
+	return out, nil
+}
+
+// Check a vector against the function under test.
+func (v *Vector) Check(inv Invoker) error {
+	body, err := json.Marshal(v.Payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := json.Marshal(events.APIGatewayProxyRequest{
+		Resource:       "/{cipher}",
+		Path:           "/" + v.Cipher,
+		HTTPMethod:     http.MethodPost,
+		PathParameters: map[string]string{"cipher": v.Cipher},
+		Body:           string(body),
+	})
+	if err != nil {
+		return err
+	}
+
+	out, err := inv.Invoke(req)
+	if err != nil {
+		return err
+	}
+
+	var resp events.APIGatewayProxyResponse
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Unexpected status code %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Message string          `json:"message"`
+		Error   json.RawMessage `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(resp.Body), &result); err != nil {
+		return err
+	}
+	if len(result.Error) > 0 && string(result.Error) != "null" {
+		return fmt.Errorf("Unexpected error %s", result.Error)
+	}
+	if result.Message != v.Output {
+		return fmt.Errorf("Expected %q but got %q", v.Output, result.Message)
+	}
+	return nil
+}
+
+// A Suite checks test vectors against the function under test.
+type Suite struct {
+	Vectors []Vector
+
+	// Workers is the maximum number of vectors to check at once.
+	Workers int
+}
+
+// Run all vectors, returning an error if any fail.
+func (s *Suite) Run(inv Invoker) error {
+	if len(s.Vectors) == 0 {
+		return errors.New("No test vectors to run")
+	}
+
+	workers := s.Workers
+	if workers <= 0 {
+		workers = defaultWorkers
+	}
+
+	var (
+		mu       sync.Mutex
+		failures []int
+		wg       sync.WaitGroup
+	)
+	indices := make(chan int)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				v := s.Vectors[i]
+				if err := v.Check(inv); err != nil {
+					log.Printf("Vector %d for cipher %q with payload %v failed: %v", i, v.Cipher, v.Payload, err)
+					mu.Lock()
+					failures = append(failures, i)
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	for i := range s.Vectors {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+
+	if len(failures) > 0 {
+		sort.Ints(failures)
+		return fmt.Errorf("%d of %d test vectors failed, first at index %d", len(failures), len(s.Vectors), failures[0])
+	}
+	return nil
+}
+
+// Hook runs a suite and reports the outcome, returning the status reported.
+func Hook(s *Suite, inv Invoker, rep Reporter) (string, error) {
+	status := Succeeded
+	if err := s.Run(inv); err != nil {
+		log.Println("Validation testing failed:", err)
+		status = Failed
+	} else {
+		log.Println("Validation testing succeeded!")
+	}
+	return status, rep.Report(status)
+}
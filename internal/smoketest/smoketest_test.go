@@ -0,0 +1,124 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package smoketest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/merenbach/goldbug/internal/api"
+)
+
+// A fakeLambda emulates the gold-bug function in-process.
+type fakeLambda struct {
+	// Tamper, if set, alters every message returned.
+	tamper func(string) string
+}
+
+func (f *fakeLambda) Invoke(payload []byte) ([]byte, error) {
+	var req events.APIGatewayProxyRequest
+	if err := json.Unmarshal(payload, &req); err != nil {
+		return nil, err
+	}
+
+	p, ok := api.Lookup(req.PathParameters["cipher"])
+	if !ok {
+		return json.Marshal(events.APIGatewayProxyResponse{StatusCode: http.StatusNotFound})
+	}
+
+	var out string
+	o, err := p(req.Body)
+	if err == nil {
+		out = o.Message
+	}
+	if f.tamper != nil {
+		out = f.tamper(out)
+	}
+	body, err := json.Marshal(struct {
+		Message string `json:"message"`
+		Error   error  `json:"error"`
+	}{out, err})
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(events.APIGatewayProxyResponse{StatusCode: http.StatusOK, Body: string(body)})
+}
+
+// A fakeCodeDeploy records reported statuses.
+type fakeCodeDeploy struct {
+	statuses []string
+}
+
+func (f *fakeCodeDeploy) Report(status string) error {
+	f.statuses = append(f.statuses, status)
+	return nil
+}
+
+func TestVectors(t *testing.T) {
+	vv, err := Vectors(api.Ciphers())
+	if err != nil {
+		t.Fatal("Could not load vectors:", err)
+	}
+
+	seen := make(map[string]bool)
+	for _, v := range vv {
+		seen[v.Cipher] = true
+	}
+	for _, name := range api.Ciphers() {
+		if !seen[name] {
+			t.Errorf("Expected test vectors for cipher %q", name)
+		}
+	}
+}
+
+func TestHook(t *testing.T) {
+	vv, err := Vectors(api.Ciphers())
+	if err != nil {
+		t.Fatal("Could not load vectors:", err)
+	}
+
+	tables := []struct {
+		lambda *fakeLambda
+		status string
+	}{
+		{&fakeLambda{}, Succeeded},
+		{&fakeLambda{tamper: strings.ToLower}, Failed},
+		{&fakeLambda{tamper: func(s string) string { return s + "X" }}, Failed},
+	}
+
+	for _, table := range tables {
+		cd := &fakeCodeDeploy{}
+		status, err := Hook(&Suite{Vectors: vv}, table.lambda, cd)
+		if err != nil {
+			t.Error("Could not report status:", err)
+		}
+		if status != table.status {
+			t.Errorf("Expected status %q, but got %q", table.status, status)
+		}
+		if len(cd.statuses) != 1 || cd.statuses[0] != table.status {
+			t.Errorf("Expected exactly one report of %q, but got %q", table.status, cd.statuses)
+		}
+	}
+}
+
+func TestHookWithoutVectors(t *testing.T) {
+	cd := &fakeCodeDeploy{}
+	if status, _ := Hook(&Suite{}, &fakeLambda{}, cd); status != Failed {
+		t.Errorf("Expected an empty suite to fail, but got %q", status)
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package stream applies cipher transforms to readers and writers.
+package stream
+
+import (
+	"io"
+	"unicode/utf8"
+)
+
+// Size of the buffer for reads from an underlying reader.
+const bufferSize = 4096
+
+// A Transform transcodes successive portions of a message, keeping any state from one portion to the next.
+// Portions are always divided on rune boundaries.
+type Transform func(string) (string, error)
+
+// Complete returns the length of the longest prefix of a byte slice that does not end with an incomplete rune.
+func complete(p []byte) int {
+	for i := len(p) - 1; i >= 0 && i >= len(p)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(p[i]) {
+			if utf8.FullRune(p[i:]) {
+				return len(p)
+			}
+			return i
+		}
+	}
+	return len(p)
+}
+
+// A writer transforms text before writing it to an underlying writer.
+type writer struct {
+	w       io.Writer
+	f       Transform
+	pending []byte
+}
+
+// NewWriter returns a writer that transforms text before writing it to w.
+// The writer holds back any incomplete rune at the end of a write until the next write.
+// Closing the writer flushes any such rune, but does not close w.
+func NewWriter(w io.Writer, f Transform) io.WriteCloser {
+	return &writer{w: w, f: f}
+}
+
+// Write transforms text and writes the result to the underlying writer.
+func (w *writer) Write(p []byte) (int, error) {
+	w.pending = append(w.pending, p...)
+	n := complete(w.pending)
+	if err := w.flush(n); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close flushes any pending bytes.
+func (w *writer) Close() error {
+	return w.flush(len(w.pending))
+}
+
+// Flush the first n pending bytes through the transform.
+func (w *writer) flush(n int) error {
+	if n == 0 {
+		return nil
+	}
+
+	out, err := w.f(string(w.pending[:n]))
+	if err != nil {
+		return err
+	}
+	w.pending = w.pending[:copy(w.pending, w.pending[n:])]
+
+	_, err = io.WriteString(w.w, out)
+	return err
+}
+
+// A reader transforms text read from an underlying reader.
+type reader struct {
+	r       io.Reader
+	f       Transform
+	buf     []byte
+	pending []byte
+	out     []byte
+	err     error
+}
+
+// NewReader returns a reader that transforms text read from r.
+func NewReader(r io.Reader, f Transform) io.Reader {
+	return &reader{r: r, f: f}
+}
+
+// Read transformed text.
+func (r *reader) Read(p []byte) (int, error) {
+	if r.buf == nil {
+		r.buf = make([]byte, bufferSize)
+	}
+	for len(r.out) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+
+		n, err := r.r.Read(r.buf)
+		r.pending = append(r.pending, r.buf[:n]...)
+
+		// Hold back an incomplete rune unless no more input is coming
+		k := len(r.pending)
+		if err == nil {
+			k = complete(r.pending)
+		} else {
+			r.err = err
+		}
+
+		if k > 0 {
+			out, err := r.f(string(r.pending[:k]))
+			if err != nil {
+				r.err = err
+				return 0, err
+			}
+			r.pending = r.pending[:copy(r.pending, r.pending[k:])]
+			r.out = []byte(out)
+		}
+	}
+
+	n := copy(p, r.out)
+	r.out = r.out[n:]
+	return n, nil
+}
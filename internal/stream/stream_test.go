@@ -0,0 +1,91 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stream
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// Numbered returns a transform that appends to each rune its position in the whole message.
+func numbered() Transform {
+	var n int
+	return func(s string) (string, error) {
+		var b strings.Builder
+		for _, r := range s {
+			fmt.Fprintf(&b, "%c%d", r, n)
+			n++
+		}
+		return b.String(), nil
+	}
+}
+
+func TestComplete(t *testing.T) {
+	tables := []struct {
+		input    []byte
+		expected int
+	}{
+		{[]byte(""), 0},
+		{[]byte("ABC"), 3},
+		{[]byte("Aé"), 3},
+		{[]byte("Aé")[:2], 1},
+		{[]byte("A€")[:3], 1},
+		{[]byte("A𝄞")[:4], 1},
+		{[]byte("A𝄞"), 5},
+		{[]byte{'A', 0xff}, 2},
+	}
+
+	for _, table := range tables {
+		if out := complete(table.input); out != table.expected {
+			t.Errorf("Expected complete prefix of %q to have length %d, but got %d", table.input, table.expected, out)
+		}
+	}
+}
+
+func TestNewWriter(t *testing.T) {
+	const input = "Aé€𝄞Z"
+
+	var b strings.Builder
+	w := NewWriter(&b, numbered())
+	for _, c := range []byte(input) {
+		if _, err := w.Write([]byte{c}); err != nil {
+			t.Fatal("Error:", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	if expected := "A0é1€2𝄞3Z4"; b.String() != expected {
+		t.Errorf("Expected %q, but got %q", expected, b.String())
+	}
+}
+
+func TestNewReader(t *testing.T) {
+	const input = "Aé€𝄞Z"
+
+	r := NewReader(iotest.OneByteReader(strings.NewReader(input)), numbered())
+	out, err := ioutil.ReadAll(iotest.OneByteReader(r))
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+
+	if expected := "A0é1€2𝄞3Z4"; string(out) != expected {
+		t.Errorf("Expected %q, but got %q", expected, out)
+	}
+}
@@ -73,9 +73,10 @@ func Reverse(s string) string {
 	return string(r)
 }
 
-// Chunk divides a string into groups.
-func chunk(s string, size int, delimiter rune) string {
-	return strings.Join(groupString(s, size, 'X'), string(delimiter))
+// Chunk divides a string into groups joined by a delimiter.
+// Chunk pads the final group as GroupString does.
+func Chunk(s string, size int, delimiter rune, padding rune) string {
+	return strings.Join(GroupString(s, size, padding), string(delimiter))
 }
 
 // DiffToMod returns the difference between a and the nearest multiple of m.
@@ -87,14 +88,21 @@ func diffToMod(a, m int) int {
 }
 
 // GroupString divides a string into groups.
-func groupString(s string, size int, padding rune) []string {
+// GroupString pads the final group to full size with the padding rune, unless the padding rune is zero.
+func GroupString(s string, size int, padding rune) []string {
 	out := make([]string, 0)
-	nullCount := diffToMod(utf8.RuneCountInString(s), size)
-	nulls := strings.Repeat(string(padding), nullCount)
-	padded := []rune(s + nulls)
+	if padding != 0 {
+		nullCount := diffToMod(utf8.RuneCountInString(s), size)
+		s += strings.Repeat(string(padding), nullCount)
+	}
+	padded := []rune(s)
 	// Iterate fewer times than the length of padded because we're stepping
 	for i := 0; i < len(padded); i += size {
-		out = append(out, string(padded[i:i+size]))
+		end := i + size
+		if end > len(padded) {
+			end = len(padded)
+		}
+		out = append(out, string(padded[i:end]))
 	}
 	return out
 }
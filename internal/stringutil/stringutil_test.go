@@ -143,7 +143,7 @@ func TestChunk(t *testing.T) {
 	}
 
 	for _, table := range tables {
-		if o := chunk(table.s, table.size, table.delimiter); o != table.expected {
+		if o := Chunk(table.s, table.size, table.delimiter, 'X'); o != table.expected {
 			t.Errorf("Chunking of string %q with size %d and delimiter %q was %q; expected %q", table.s, table.size, table.delimiter, o, table.expected)
 		}
 	}
@@ -159,10 +159,11 @@ func TestGroupString(t *testing.T) {
 		{"HELLOWORLD", 2, 'X', []string{"HE", "LL", "OW", "OR", "LD"}},
 		{"HELLOWORLD", 3, 'X', []string{"HEL", "LOW", "ORL", "DXX"}},
 		{"HELLOWORLD", 4, 'X', []string{"HELL", "OWOR", "LDXX"}},
+		{"HELLOWORLD", 4, 0, []string{"HELL", "OWOR", "LD"}},
 	}
 
 	for _, table := range tables {
-		if o := groupString(table.s, table.size, table.padding); !reflect.DeepEqual(o, table.expected) {
+		if o := GroupString(table.s, table.size, table.padding); !reflect.DeepEqual(o, table.expected) {
 			t.Errorf("Grouping of string %q with size %d and padding %q was %q; expected %q", table.s, table.size, table.padding, o, table.expected)
 		}
 	}
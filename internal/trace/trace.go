@@ -0,0 +1,100 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package trace records how each rune of a message was transformed.
+package trace
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// An Action describes what happened to an input rune.
+type Action uint8
+
+const (
+	// Transcoded denotes a rune that was substituted or transposed.
+	Transcoded Action = iota
+
+	// PassedThrough denotes a rune that was left as-is.
+	PassedThrough
+
+	// Skipped denotes a rune that was removed.
+	Skipped
+)
+
+func (a Action) String() string {
+	switch a {
+	case Transcoded:
+		return "transcoded"
+	case PassedThrough:
+		return "passed"
+	case Skipped:
+		return "skipped"
+	}
+	return fmt.Sprintf("Action(%d)", a)
+}
+
+// An Event records the treatment of a single input rune.
+// Row and Col locate the tableau or grid cell used, or are (-1) if none was.
+// Key and Output are zero if no key rune or output rune applies.
+type Event struct {
+	Index  int
+	Input  rune
+	Key    rune
+	Row    int
+	Col    int
+	Output rune
+	Action Action
+}
+
+// MarshalJSON renders runes as strings.
+func (e Event) MarshalJSON() ([]byte, error) {
+	var key, output string
+	if e.Key != 0 {
+		key = string(e.Key)
+	}
+	if e.Output != 0 {
+		output = string(e.Output)
+	}
+	return json.Marshal(struct {
+		Index  int    `json:"index"`
+		Input  string `json:"input"`
+		Key    string `json:"key,omitempty"`
+		Row    int    `json:"row"`
+		Col    int    `json:"col"`
+		Output string `json:"output,omitempty"`
+		Action string `json:"action"`
+	}{e.Index, string(e.Input), key, e.Row, e.Col, output, e.Action.String()})
+}
+
+// A Trace records per-rune events in the order of input.
+type Trace []Event
+
+// Add an event to this trace, if the trace is not nil.
+func (t *Trace) Add(e Event) {
+	if t != nil {
+		*t = append(*t, e)
+	}
+}
+
+// Pass records a rune left as-is.
+func (t *Trace) Pass(i int, r rune, k rune) {
+	t.Add(Event{Index: i, Input: r, Key: k, Row: (-1), Col: (-1), Output: r, Action: PassedThrough})
+}
+
+// Skip records a rune that was removed.
+func (t *Trace) Skip(i int, r rune, k rune) {
+	t.Add(Event{Index: i, Input: r, Key: k, Row: (-1), Col: (-1), Action: Skipped})
+}
@@ -16,6 +16,7 @@ package translation
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 )
 
@@ -34,6 +35,9 @@ func makeMap(a string, b string, c string) (map[rune]rune, error) {
 
 	// Translate from A to B
 	for i, r := range src {
+		if _, ok := t[r]; ok {
+			return nil, fmt.Errorf("The first argument contains duplicate character %q", r)
+		}
 		t[r] = dst[i]
 	}
 
@@ -49,6 +49,12 @@ func TestMakeMap(t *testing.T) {
 	}
 }
 
+func TestMakeMap_duplicates(t *testing.T) {
+	if _, err := makeMap("ABCA", "WXYZ", ""); err == nil {
+		t.Error("Expected error for duplicate source characters")
+	}
+}
+
 func TestTranslate(t *testing.T) {
 	testdata, err := ioutil.ReadFile(filepath.Join("testdata", "translate.json"))
 	if err != nil {
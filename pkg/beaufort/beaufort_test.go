@@ -72,9 +72,9 @@ func TestCipher_Decipher(t *testing.T) {
 	}
 }
 
-func ExampleCipher_tableau() {
+func ExampleCipher_Tableau() {
 	c := Cipher{}
-	out, err := c.tableau()
+	out, err := c.Tableau()
 	if err != nil {
 		fmt.Println("Error:", err)
 	}
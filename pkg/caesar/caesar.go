@@ -14,25 +14,33 @@
 
 package caesar
 
-import "github.com/merenbach/goldbug/pkg/affine"
+import (
+	"io"
+
+	"github.com/merenbach/goldbug/internal/normalize"
+	"github.com/merenbach/goldbug/internal/trace"
+	"github.com/merenbach/goldbug/pkg/affine"
+)
 
 // Slope for the affine cipher.
 const slope = 1
 
 // Cipher implements a Caesar cipher.
 type Cipher struct {
-	Alphabet string
-	Shift    int
-	Strict   bool
+	Alphabet   string
+	Shift      int
+	Strict     bool
+	Normalizer normalize.Normalizer
 }
 
 // Encipher a message.
 func (c *Cipher) Encipher(s string) (string, error) {
 	c2 := affine.Cipher{
-		Alphabet:  c.Alphabet,
-		Intercept: c.Shift,
-		Slope:     slope,
-		Strict:    c.Strict,
+		Alphabet:   c.Alphabet,
+		Intercept:  c.Shift,
+		Slope:      slope,
+		Strict:     c.Strict,
+		Normalizer: c.Normalizer,
 	}
 	return c2.Encipher(s)
 }
@@ -40,21 +48,96 @@ func (c *Cipher) Encipher(s string) (string, error) {
 // Decipher a message.
 func (c *Cipher) Decipher(s string) (string, error) {
 	c2 := affine.Cipher{
-		Alphabet:  c.Alphabet,
-		Intercept: c.Shift,
-		Slope:     slope,
-		Strict:    c.Strict,
+		Alphabet:   c.Alphabet,
+		Intercept:  c.Shift,
+		Slope:      slope,
+		Strict:     c.Strict,
+		Normalizer: c.Normalizer,
 	}
 	return c2.Decipher(s)
 }
 
+// EncipherTrace enciphers a message and records how each rune was produced.
+func (c *Cipher) EncipherTrace(s string) (string, trace.Trace, error) {
+	c2 := affine.Cipher{
+		Alphabet:   c.Alphabet,
+		Intercept:  c.Shift,
+		Slope:      slope,
+		Strict:     c.Strict,
+		Normalizer: c.Normalizer,
+	}
+	return c2.EncipherTrace(s)
+}
+
+// DecipherTrace deciphers a message and records how each rune was produced.
+func (c *Cipher) DecipherTrace(s string) (string, trace.Trace, error) {
+	c2 := affine.Cipher{
+		Alphabet:   c.Alphabet,
+		Intercept:  c.Shift,
+		Slope:      slope,
+		Strict:     c.Strict,
+		Normalizer: c.Normalizer,
+	}
+	return c2.DecipherTrace(s)
+}
+
+// NewEncipherWriter returns a writer that enciphers text before writing it to w.
+// Close the writer to flush any incomplete rune at the end of the text.
+func (c *Cipher) NewEncipherWriter(w io.Writer) (io.WriteCloser, error) {
+	c2 := affine.Cipher{
+		Alphabet:   c.Alphabet,
+		Intercept:  c.Shift,
+		Slope:      slope,
+		Strict:     c.Strict,
+		Normalizer: c.Normalizer,
+	}
+	return c2.NewEncipherWriter(w)
+}
+
+// NewDecipherReader returns a reader that deciphers text read from r.
+func (c *Cipher) NewDecipherReader(r io.Reader) (io.Reader, error) {
+	c2 := affine.Cipher{
+		Alphabet:   c.Alphabet,
+		Intercept:  c.Shift,
+		Slope:      slope,
+		Strict:     c.Strict,
+		Normalizer: c.Normalizer,
+	}
+	return c2.NewDecipherReader(r)
+}
+
+// Compile this cipher.
+func (c *Cipher) Compile() (*affine.Compiled, error) {
+	c2 := affine.Cipher{
+		Alphabet:   c.Alphabet,
+		Intercept:  c.Shift,
+		Slope:      slope,
+		Strict:     c.Strict,
+		Normalizer: c.Normalizer,
+	}
+	return c2.Compile()
+}
+
 // Tableau for encipherment and decipherment.
 func (c *Cipher) Tableau() (string, error) {
 	c2 := affine.Cipher{
-		Alphabet:  c.Alphabet,
-		Intercept: c.Shift,
-		Slope:     slope,
-		Strict:    c.Strict,
+		Alphabet:   c.Alphabet,
+		Intercept:  c.Shift,
+		Slope:      slope,
+		Strict:     c.Strict,
+		Normalizer: c.Normalizer,
 	}
 	return c2.Tableau()
 }
+
+// TableauMatrix for encipherment and decipherment.
+func (c *Cipher) TableauMatrix() ([][]string, error) {
+	c2 := affine.Cipher{
+		Alphabet:   c.Alphabet,
+		Intercept:  c.Shift,
+		Slope:      slope,
+		Strict:     c.Strict,
+		Normalizer: c.Normalizer,
+	}
+	return c2.TableauMatrix()
+}
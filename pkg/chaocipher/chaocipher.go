@@ -0,0 +1,221 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package chaocipher implements John F. Byrne's Chaocipher, whose two alphabets permute after every letter.
+package chaocipher
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/merenbach/goldbug/internal/alphabet"
+	"github.com/merenbach/goldbug/internal/masc"
+	"github.com/merenbach/goldbug/internal/normalize"
+	"github.com/merenbach/goldbug/internal/stringutil"
+	"github.com/merenbach/goldbug/internal/trace"
+)
+
+// Cipher implements a Chaocipher.
+// Left is the ciphertext alphabet and Right the plaintext alphabet.
+// Each is keyed like a keyword cipher, so a keyword yields a mixed alphabet and a full permutation of the alphabet is used as-is.
+type Cipher struct {
+	Alphabet   string
+	Left       string
+	Right      string
+	Strict     bool
+	Normalizer normalize.Normalizer
+}
+
+// A State holds the alphabets with which a rune was transcoded.
+type State struct {
+	Left   string
+	Right  string
+	Input  rune
+	Output rune
+}
+
+// A Machine holds the left and right alphabets as they permute.
+type machine struct {
+	left  []rune
+	right []rune
+}
+
+// Key an alphabet with a keyword.
+func key(keyword string, a string) (string, error) {
+	out := stringutil.Deduplicate(keyword + a)
+	if len([]rune(out)) != len([]rune(a)) {
+		return "", fmt.Errorf("Keyword %q has characters outside the alphabet", keyword)
+	}
+	return out, nil
+}
+
+func (c *Cipher) makemachine() (*machine, error) {
+	a := c.Alphabet
+	if a == "" {
+		a = masc.Alphabet
+	}
+	// Permutation moves the third rune of the right alphabet to the nadir, which must not precede it
+	if err := alphabet.ValidateLength(a, 4, 0); err != nil {
+		return nil, err
+	}
+
+	left, err := key(c.Left, a)
+	if err != nil {
+		return nil, err
+	}
+	right, err := key(c.Right, a)
+	if err != nil {
+		return nil, err
+	}
+	return &machine{left: []rune(left), right: []rune(right)}, nil
+}
+
+// Index of a rune in an alphabet, or (-1) if absent.
+func index(rr []rune, r rune) int {
+	for i, o := range rr {
+		if o == r {
+			return i
+		}
+	}
+	return (-1)
+}
+
+// Rotate an alphabet left by i positions.
+func rotate(rr []rune, i int) {
+	out := append(append([]rune{}, rr[i:]...), rr[:i]...)
+	copy(rr, out)
+}
+
+// Extract the rune at index i and insert it again at the nadir, shifting the runes between them left by one.
+func extract(rr []rune, i int) {
+	nadir := len(rr) / 2
+	r := rr[i]
+	copy(rr[i:nadir], rr[i+1:nadir+1])
+	rr[nadir] = r
+}
+
+// Permute both alphabets after transcoding at index i.
+// The left alphabet is brought to the zenith at index i and its second rune moved to the nadir.
+// The right alphabet is brought to the zenith one further, and its third rune moved to the nadir.
+func (m *machine) permute(i int) {
+	rotate(m.left, i)
+	extract(m.left, 1)
+
+	rotate(m.right, (i+1)%len(m.right))
+	extract(m.right, 2)
+}
+
+// Transcode a message, reading from one alphabet and writing from the other.
+func (c *Cipher) transcode(s string, decipher bool, tr *trace.Trace, states *[]State) (string, error) {
+	m, err := c.makemachine()
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	for i, r := range []rune(c.Normalizer.Normalize(s)) {
+		src, dst := m.right, m.left
+		if decipher {
+			src, dst = m.left, m.right
+		}
+		f := c.Normalizer.Fold(r, func(r rune) bool {
+			return index(src, r) != (-1)
+		})
+
+		j := index(src, f)
+		if j == (-1) {
+			if !c.Strict {
+				tr.Pass(i, r, 0)
+				b.WriteRune(r)
+			} else {
+				tr.Skip(i, r, 0)
+			}
+			continue
+		}
+
+		o := c.Normalizer.Restore(r, f, dst[j])
+		if states != nil {
+			*states = append(*states, State{Left: string(m.left), Right: string(m.right), Input: r, Output: o})
+		}
+		tr.Add(trace.Event{Index: i, Input: r, Row: (-1), Col: j, Output: o, Action: trace.Transcoded})
+		b.WriteRune(o)
+		m.permute(j)
+	}
+	return b.String(), nil
+}
+
+// Encipher a message.
+func (c *Cipher) Encipher(s string) (string, error) {
+	return c.transcode(s, false, nil, nil)
+}
+
+// Decipher a message.
+func (c *Cipher) Decipher(s string) (string, error) {
+	return c.transcode(s, true, nil, nil)
+}
+
+// EncipherTrace enciphers a message and records how each rune was produced.
+// The column of each event is the position of the rune at the moment it was transcoded.
+func (c *Cipher) EncipherTrace(s string) (string, trace.Trace, error) {
+	var tr trace.Trace
+	out, err := c.transcode(s, false, &tr, nil)
+	return out, tr, err
+}
+
+// DecipherTrace deciphers a message and records how each rune was produced.
+func (c *Cipher) DecipherTrace(s string) (string, trace.Trace, error) {
+	var tr trace.Trace
+	out, err := c.transcode(s, true, &tr, nil)
+	return out, tr, err
+}
+
+// EncipherStates enciphers a message and records the alphabets with which each rune was transcoded.
+func (c *Cipher) EncipherStates(s string) (string, []State, error) {
+	var states []State
+	out, err := c.transcode(s, false, nil, &states)
+	return out, states, err
+}
+
+// DecipherStates deciphers a message and records the alphabets with which each rune was transcoded.
+func (c *Cipher) DecipherStates(s string) (string, []State, error) {
+	var states []State
+	out, err := c.transcode(s, true, nil, &states)
+	return out, states, err
+}
+
+// TableauMatrix shows the initial left and right alphabets, with row labels in the first column.
+func (c *Cipher) TableauMatrix() ([][]string, error) {
+	m, err := c.makemachine()
+	if err != nil {
+		return nil, err
+	}
+	return [][]string{
+		append([]string{"Left"}, strings.Split(string(m.left), "")...),
+		append([]string{"Right"}, strings.Split(string(m.right), "")...),
+	}, nil
+}
+
+// Tableau shows the initial left and right alphabets.
+func (c *Cipher) Tableau() (string, error) {
+	mm, err := c.TableauMatrix()
+	if err != nil {
+		return "", err
+	}
+
+	lines := make([]string, len(mm))
+	for i, row := range mm {
+		lines[i] = fmt.Sprintf("%-5s | %s", row[0], strings.Join(row[1:], " "))
+	}
+	return strings.Join(lines, "\n"), nil
+}
@@ -0,0 +1,145 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chaocipher
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/merenbach/goldbug/internal/normalize"
+)
+
+func TestCipher_Encipher(t *testing.T) {
+	testdata, err := ioutil.ReadFile(filepath.Join("testdata", "cipher_encipher.json"))
+	if err != nil {
+		t.Fatal("Could not read testdata fixture:", err)
+	}
+
+	var tables []struct {
+		Cipher
+
+		Input  string
+		Output string
+	}
+	if err := json.Unmarshal(testdata, &tables); err != nil {
+		t.Fatal("Could not unmarshal testdata:", err)
+	}
+
+	for _, table := range tables {
+		if out, err := table.Encipher(table.Input); err != nil {
+			t.Error("Could not encipher:", err)
+		} else if out != table.Output {
+			t.Errorf("Expected %q to encipher to %q, but instead got %q", table.Input, table.Output, out)
+		}
+	}
+}
+
+func TestCipher_Decipher(t *testing.T) {
+	testdata, err := ioutil.ReadFile(filepath.Join("testdata", "cipher_decipher.json"))
+	if err != nil {
+		t.Fatal("Could not read testdata fixture:", err)
+	}
+
+	var tables []struct {
+		Cipher
+
+		Input  string
+		Output string
+	}
+	if err := json.Unmarshal(testdata, &tables); err != nil {
+		t.Fatal("Could not unmarshal testdata:", err)
+	}
+
+	for _, table := range tables {
+		if out, err := table.Decipher(table.Input); err != nil {
+			t.Error("Could not decipher:", err)
+		} else if out != table.Output {
+			t.Errorf("Expected %q to decipher to %q, but instead got %q", table.Input, table.Output, out)
+		}
+	}
+}
+
+func TestCipher_PreserveCase(t *testing.T) {
+	c := Cipher{Left: "CHAOS", Right: "BYRNE", Normalizer: normalize.Normalizer{Case: normalize.PreserveCase}}
+	if out, err := c.Encipher("Attack at dawn!"); err != nil {
+		t.Error("Could not encipher:", err)
+	} else if out != "Btdaaf zs zwkr!" {
+		t.Errorf("Expected %q, but got %q", "Btdaaf zs zwkr!", out)
+	}
+}
+
+// Alphabet states from Byrne's exhibit, as described by Moshe Rubin.
+func TestCipher_EncipherStates(t *testing.T) {
+	c := Cipher{Left: "HXUCZVAMDSLKPEFJRIGTWOBNYQ", Right: "PTLNBQDEOYSFAVZKGJRIHWXUMC"}
+	out, states, err := c.EncipherStates("WELL")
+	if err != nil {
+		t.Fatal("Could not encipher:", err)
+	}
+	if out != "OAHQ" {
+		t.Errorf("Expected %q, but got %q", "OAHQ", out)
+	}
+
+	expected := []State{
+		{"HXUCZVAMDSLKPEFJRIGTWOBNYQ", "PTLNBQDEOYSFAVZKGJRIHWXUMC", 'W', 'O'},
+		{"ONYQHXUCZVAMDBSLKPEFJRIGTW", "XUCPTLNBQDEOYMSFAVZKGJRIHW", 'E', 'A'},
+		{"ADBSLKPEFJRIGMTWONYQHXUCZV", "OYSFAVZKGJRIHMWXUCPTLNBQDE", 'L', 'H'},
+		{"HUCZVADBSLKPEXFJRIGMTWONYQ", "NBDEOYSFAVZKGQJRIHMWXUCPTL", 'L', 'Q'},
+	}
+	if len(states) != len(expected) {
+		t.Fatalf("Expected %d states, but got %d", len(expected), len(states))
+	}
+	for i, s := range states {
+		if s != expected[i] {
+			t.Errorf("Expected state %d to be %+v, but got %+v", i, expected[i], s)
+		}
+	}
+
+	if _, states, err := c.DecipherStates("OAHQ"); err != nil {
+		t.Error("Could not decipher:", err)
+	} else if states[3].Left != expected[3].Left || states[3].Right != expected[3].Right {
+		t.Errorf("Expected decipherment to follow the same states, but got %+v", states[3])
+	}
+}
+
+func TestCipher_errors(t *testing.T) {
+	tables := []Cipher{
+		{Alphabet: "ABCA"},
+		{Alphabet: "ABC"},
+		{Left: "K3Y"},
+		{Right: "abc"},
+	}
+
+	for _, table := range tables {
+		if _, err := table.Encipher("HELLO"); err == nil {
+			t.Errorf("Expected error enciphering with %+v", table)
+		}
+	}
+}
+
+func ExampleCipher_Tableau() {
+	c := Cipher{Left: "CHAOS", Right: "BYRNE"}
+	out, err := c.Tableau()
+	if err != nil {
+		fmt.Println("Error:", err)
+	}
+	fmt.Println(out)
+
+	// Output:
+	// Left  | C H A O S B D E F G I J K L M N P Q R T U V W X Y Z
+	// Right | B Y R N E A C D F G H I J K L M O P Q S T U V W X Z
+}
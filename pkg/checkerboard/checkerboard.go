@@ -0,0 +1,300 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package checkerboard implements the straddling checkerboard, which replaces the commonest runes with single digits and the rest with pairs.
+package checkerboard
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/merenbach/goldbug/internal/alphabet"
+	"github.com/merenbach/goldbug/internal/normalize"
+	"github.com/merenbach/goldbug/internal/trace"
+)
+
+// Alphabet to use by default, with the eight commonest English letters first, then the rest of the alphabet, a figure shift and a full stop.
+const Alphabet = "ETAONRISBCDFGHJKLMPQ/UVWXYZ."
+
+// Digits to use by default as column labels.
+const Digits = "0123456789"
+
+// Blanks to use by default, leaving the third and seventh columns of the top row empty.
+var Blanks = []int{2, 6}
+
+// Cipher implements a straddling checkerboard.
+// The alphabet fills the top row, skipping blank columns, and then one full row for each blank, labeled with the digit above that blank.
+// Runes in the top row encipher to their column digit, and the rest to their row digit followed by their column digit.
+type Cipher struct {
+	Alphabet   string
+	Digits     string
+	Blanks     []int
+	Strict     bool
+	Normalizer normalize.Normalizer
+}
+
+// A board holds the lookup tables for a straddling checkerboard.
+// The top row has no label, so its entry in rows is zero.
+type board struct {
+	cells  [][]rune
+	rows   []rune
+	cols   []rune
+	coords map[rune][2]int
+}
+
+func (c *Cipher) makeboard() (*board, error) {
+	a, digits, blanks := c.Alphabet, c.Digits, c.Blanks
+	if a == "" {
+		a = Alphabet
+	}
+	if digits == "" {
+		digits = Digits
+	}
+	if len(blanks) == 0 {
+		blanks = Blanks
+	}
+	if err := alphabet.Unique(digits); err != nil {
+		return nil, fmt.Errorf("Invalid digits: %w", err)
+	}
+	cols := []rune(digits)
+	if len(cols) != 10 {
+		return nil, fmt.Errorf("Checkerboard needs 10 digits, but got %d", len(cols))
+	}
+
+	bb := make([]int, len(blanks))
+	copy(bb, blanks)
+	sort.Ints(bb)
+	for i, n := range bb {
+		switch {
+		case n < 0 || n >= len(cols):
+			return nil, fmt.Errorf("Blank position %d is out of range", n)
+		case i > 0 && bb[i-1] == n:
+			return nil, fmt.Errorf("Blank position %d is repeated", n)
+		}
+	}
+	if len(bb) == len(cols) {
+		return nil, errors.New("Top row cannot be entirely blank")
+	}
+
+	// The top row holds a rune for each non-blank digit, and each blank labels a full row below
+	if err := alphabet.ValidateLength(a, 1, len(cols)-len(bb)+len(cols)*len(bb)); err != nil {
+		return nil, err
+	}
+	aa := []rune(a)
+
+	bd := &board{
+		cells:  make([][]rune, 1+len(bb)),
+		rows:   make([]rune, 1+len(bb)),
+		cols:   cols,
+		coords: make(map[rune][2]int, len(aa)),
+	}
+	for i, n := range bb {
+		bd.rows[i+1] = cols[n]
+	}
+
+	blank := make(map[int]bool, len(bb))
+	for _, n := range bb {
+		blank[n] = true
+	}
+	for i := range bd.cells {
+		bd.cells[i] = make([]rune, len(cols))
+		for j := range cols {
+			if (i == 0 && blank[j]) || len(aa) == 0 {
+				continue
+			}
+			bd.cells[i][j] = aa[0]
+			bd.coords[aa[0]] = [2]int{i, j}
+			aa = aa[1:]
+		}
+	}
+	return bd, nil
+}
+
+// Find the rune on a board, after case folding, and report whether it was found.
+func (c *Cipher) find(bd *board, r rune) (rune, bool) {
+	present := func(r rune) bool {
+		_, ok := bd.coords[r]
+		return ok
+	}
+	f := c.Normalizer.Fold(r, present)
+	return f, present(f)
+}
+
+// Encipher a message.
+func (c *Cipher) Encipher(s string) (string, error) {
+	return c.encipher(s, nil)
+}
+
+// EncipherTrace enciphers a message and records how each rune was produced.
+// The output of each event is the first digit written, which for rows below the top is the row label.
+func (c *Cipher) EncipherTrace(s string) (string, trace.Trace, error) {
+	var tr trace.Trace
+	out, err := c.encipher(s, &tr)
+	return out, tr, err
+}
+
+// Encipher a message, recording an event for each rune if a trace is given.
+func (c *Cipher) encipher(s string, tr *trace.Trace) (string, error) {
+	bd, err := c.makeboard()
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	for i, r := range []rune(c.Normalizer.Normalize(s)) {
+		if f, ok := c.find(bd, r); ok {
+			rc := bd.coords[f]
+			first := bd.cols[rc[1]]
+			if rc[0] > 0 {
+				first = bd.rows[rc[0]]
+				b.WriteRune(first)
+			}
+			tr.Add(trace.Event{Index: i, Input: r, Row: rc[0], Col: rc[1], Output: first, Action: trace.Transcoded})
+			b.WriteRune(bd.cols[rc[1]])
+		} else if !c.Strict {
+			tr.Pass(i, r, 0)
+			b.WriteRune(r)
+		} else {
+			tr.Skip(i, r, 0)
+		}
+	}
+	return b.String(), nil
+}
+
+// Decipher a message.
+// A row label followed by a column digit, or a lone column digit of the top row, is deciphered, and other runes are passed through unless strict.
+func (c *Cipher) Decipher(s string) (string, error) {
+	return c.decipher(s, nil)
+}
+
+// DecipherTrace deciphers a message and records how each rune was produced.
+// Each row label and column digit together yield a single event, with the row label as its input.
+func (c *Cipher) DecipherTrace(s string) (string, trace.Trace, error) {
+	var tr trace.Trace
+	out, err := c.decipher(s, &tr)
+	return out, tr, err
+}
+
+// Decipher a message, recording an event for each cell or other rune if a trace is given.
+func (c *Cipher) decipher(s string, tr *trace.Trace) (string, error) {
+	bd, err := c.makeboard()
+	if err != nil {
+		return "", err
+	}
+
+	rows, cols := indices(bd.rows[1:]), indices(bd.cols)
+
+	var b strings.Builder
+	rr := []rune(s)
+	for i := 0; i < len(rr); i++ {
+		if y, ok := rows[rr[i]]; ok {
+			if i+1 < len(rr) {
+				if x, ok := cols[rr[i+1]]; ok && bd.cells[y+1][x] != 0 {
+					tr.Add(trace.Event{Index: i, Input: rr[i], Row: y + 1, Col: x, Output: bd.cells[y+1][x], Action: trace.Transcoded})
+					b.WriteRune(bd.cells[y+1][x])
+					i++
+					continue
+				}
+			}
+		} else if x, ok := cols[rr[i]]; ok && bd.cells[0][x] != 0 {
+			tr.Add(trace.Event{Index: i, Input: rr[i], Col: x, Output: bd.cells[0][x], Action: trace.Transcoded})
+			b.WriteRune(bd.cells[0][x])
+			continue
+		}
+		if !c.Strict {
+			tr.Pass(i, rr[i], 0)
+			b.WriteRune(rr[i])
+		} else {
+			tr.Skip(i, rr[i], 0)
+		}
+	}
+	return b.String(), nil
+}
+
+// Labels that may appear in ciphertext, which are the column digits.
+func (c *Cipher) Labels() (string, error) {
+	bd, err := c.makeboard()
+	if err != nil {
+		return "", err
+	}
+	return string(bd.cols), nil
+}
+
+// Indices of runes by position.
+func indices(rr []rune) map[rune]int {
+	out := make(map[rune]int, len(rr))
+	for i, r := range rr {
+		out[r] = i
+	}
+	return out
+}
+
+// Tableau for encipherment and decipherment.
+func (c *Cipher) Tableau() (string, error) {
+	bd, err := c.makeboard()
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString("   ")
+	for _, r := range bd.cols {
+		b.WriteString(" " + string(r))
+	}
+	b.WriteString("\n  +")
+	b.WriteString(strings.Repeat("-", 2*len(bd.cols)))
+	for i, row := range bd.cells {
+		label := " "
+		if i > 0 {
+			label = string(bd.rows[i])
+		}
+		line := label + " |"
+		for _, r := range row {
+			if r == 0 {
+				r = ' '
+			}
+			line += " " + string(r)
+		}
+		b.WriteString("\n" + strings.TrimRight(line, " "))
+	}
+	return b.String(), nil
+}
+
+// TableauMatrix for encipherment and decipherment, with row labels in the first column and column digits in the first row.
+// Blank cells, and the label of the top row, are empty strings.
+func (c *Cipher) TableauMatrix() ([][]string, error) {
+	bd, err := c.makeboard()
+	if err != nil {
+		return nil, err
+	}
+
+	out := [][]string{append([]string{""}, strings.Split(string(bd.cols), "")...)}
+	for i, cells := range bd.cells {
+		row := []string{""}
+		if i > 0 {
+			row[0] = string(bd.rows[i])
+		}
+		for _, r := range cells {
+			var s string
+			if r != 0 {
+				s = string(r)
+			}
+			row = append(row, s)
+		}
+		out = append(out, row)
+	}
+	return out, nil
+}
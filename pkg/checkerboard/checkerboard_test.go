@@ -0,0 +1,124 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checkerboard
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestCipher_Encipher(t *testing.T) {
+	testdata, err := ioutil.ReadFile(filepath.Join("testdata", "cipher_encipher.json"))
+	if err != nil {
+		t.Fatal("Could not read testdata fixture:", err)
+	}
+
+	var tables []struct {
+		Cipher
+
+		Input  string
+		Output string
+	}
+	if err := json.Unmarshal(testdata, &tables); err != nil {
+		t.Fatal("Could not unmarshal testdata:", err)
+	}
+
+	for _, table := range tables {
+		if out, err := table.Encipher(table.Input); err != nil {
+			t.Error("Could not encipher:", err)
+		} else if out != table.Output {
+			t.Errorf("Expected %q to encipher to %q, but instead got %q", table.Input, table.Output, out)
+		}
+	}
+}
+
+func TestCipher_Decipher(t *testing.T) {
+	testdata, err := ioutil.ReadFile(filepath.Join("testdata", "cipher_decipher.json"))
+	if err != nil {
+		t.Fatal("Could not read testdata fixture:", err)
+	}
+
+	var tables []struct {
+		Cipher
+
+		Input  string
+		Output string
+	}
+	if err := json.Unmarshal(testdata, &tables); err != nil {
+		t.Fatal("Could not unmarshal testdata:", err)
+	}
+
+	for _, table := range tables {
+		if out, err := table.Decipher(table.Input); err != nil {
+			t.Error("Could not decipher:", err)
+		} else if out != table.Output {
+			t.Errorf("Expected %q to decipher to %q, but instead got %q", table.Input, table.Output, out)
+		}
+	}
+}
+
+func TestCipher_Decipher_dangling(t *testing.T) {
+	// A trailing row label has no column digit to complete its cell
+	c := Cipher{}
+	if out, err := c.Decipher("312"); err != nil {
+		t.Error("Could not decipher:", err)
+	} else if out != "AT2" {
+		t.Errorf("Expected %q, but got %q", "AT2", out)
+	}
+
+	c.Strict = true
+	if out, err := c.Decipher("312"); err != nil {
+		t.Error("Could not decipher:", err)
+	} else if out != "AT" {
+		t.Errorf("Expected %q, but got %q", "AT", out)
+	}
+}
+
+func TestCipher_errors(t *testing.T) {
+	tables := []Cipher{
+		{Alphabet: "ABCA"},
+		{Digits: "012345678"},
+		{Digits: "0123456788"},
+		{Blanks: []int{2, 10}},
+		{Blanks: []int{2, 2}},
+		{Blanks: []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}},
+		{Alphabet: "ABCDEFGHIJKLMNOPQRSTUVWXYZ.", Blanks: []int{5}},
+	}
+
+	for _, c := range tables {
+		if _, err := c.Encipher("HELLO"); err == nil {
+			t.Errorf("Expected error enciphering with %+v", c)
+		}
+	}
+}
+
+func ExampleCipher_Tableau() {
+	c := Cipher{}
+	out, err := c.Tableau()
+	if err != nil {
+		fmt.Println("Error:", err)
+	}
+	fmt.Println(out)
+
+	// Output:
+	//     0 1 2 3 4 5 6 7 8 9
+	//   +--------------------
+	//   | E T   A O N   R I S
+	// 2 | B C D F G H J K L M
+	// 6 | P Q / U V W X Y Z .
+}
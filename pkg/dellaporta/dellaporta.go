@@ -16,49 +16,61 @@ package dellaporta
 
 import (
 	"errors"
+	"io"
+	"strings"
 	"unicode/utf8"
 
+	"github.com/merenbach/goldbug/internal/normalize"
 	"github.com/merenbach/goldbug/internal/pasc"
+	"github.com/merenbach/goldbug/internal/stream"
 	"github.com/merenbach/goldbug/internal/stringutil"
+	"github.com/merenbach/goldbug/internal/trace"
 )
 
 // Cipher implements a Della Porta cipher.
+// The alphabet may have any even length, and each pair of key runes selects one of half as many rows.
+// A keyword, if given, mixes the alphabet before it is split into upper and lower halves.
 type Cipher struct {
-	Alphabet string
-	Key      string
-	Strict   bool
+	Alphabet   string
+	Key        string
+	Keyword    string
+	Strict     bool
+	Normalizer normalize.Normalizer
 }
 
 func (c *Cipher) maketableau() (*pasc.ReciprocalTable, error) {
-	alphabet := c.Alphabet
-	if alphabet == "" {
-		alphabet = pasc.Alphabet
+	keyAlphabet := c.Alphabet
+	if keyAlphabet == "" {
+		keyAlphabet = pasc.Alphabet
 	}
 
-	ptAlphabet, ctAlphabet, keyAlphabet := alphabet, alphabet, alphabet
-
-	keyRunes := []rune(keyAlphabet)
-	ctAlphabets := make([]string, len(keyRunes))
+	// Mix the alphabet with any keyword runes that belong to it
+	ptAlphabet := stringutil.Deduplicate(strings.Map(func(r rune) rune {
+		if !strings.ContainsRune(keyAlphabet, r) {
+			return (-1)
+		}
+		return r
+	}, c.Keyword) + keyAlphabet)
+	ctAlphabet := ptAlphabet
 
-	if utf8.RuneCountInString(ctAlphabet)%2 != 0 {
+	n := utf8.RuneCountInString(ctAlphabet)
+	if n == 0 || n%2 != 0 {
 		return nil, errors.New("Della Porta cipher alphabets must have even length")
 	}
+	h := n / 2
+
+	keyRunes := []rune(keyAlphabet)
+	ctAlphabets := make([]string, len(keyRunes))
 
+	// Each row pairs the upper half with the lower half, sliding the lower half by one for each pair of key runes
 	for y := range keyRunes {
-		ii := make([]int, utf8.RuneCountInString(ctAlphabet))
+		ii := make([]int, n)
 		for x := range ii {
-			if x < 13 {
-				// 	// v = 13 - x + x%13 + (x+y/2)%13
-				// v = 13 + (x+y/2)%13 // <--- THIS IS THE MOST BASIC VERSION
-				ii[x] = 13 + (x+y/2)%13
+			if x < h {
+				ii[x] = h + (x+y/2)%h
 			} else {
-				// v = (x - y/2) % 13 // <--- THIS IS THE MOST BASIC VERSION
-				ii[x] = (x - y/2) % 13
-				// v2 := mod(x, 13) - y/2 + 13
-				// log.Printf("v = %d and v2 = %d", v, v2)
-				// 	// v = 13 - x + x%13 + (13+x-y/2)%13
+				ii[x] = (x - y/2) % h
 			}
-			// ii[x] = (13-x)%13 + x%13 + (x-y*sign(x-13)/2)%13
 		}
 
 		out, err := stringutil.Backpermute(ctAlphabet, ii)
@@ -73,6 +85,7 @@ func (c *Cipher) maketableau() (*pasc.ReciprocalTable, error) {
 		KeyAlphabet: keyAlphabet,
 		CtAlphabets: ctAlphabets,
 		Strict:      c.Strict,
+		Normalizer:  c.Normalizer,
 	}
 
 	return &tr, nil
@@ -96,11 +109,101 @@ func (c *Cipher) Decipher(s string) (string, error) {
 	return t.Decipher(s, c.Key, nil)
 }
 
+// EncipherTrace enciphers a message and records how each rune was produced.
+func (c *Cipher) EncipherTrace(s string) (string, trace.Trace, error) {
+	t, err := c.maketableau()
+	if err != nil {
+		return "", nil, err
+	}
+	var tr trace.Trace
+	t.Trace = &tr
+	out, err := t.Encipher(s, c.Key, nil)
+	return out, tr, err
+}
+
+// DecipherTrace deciphers a message and records how each rune was produced.
+func (c *Cipher) DecipherTrace(s string) (string, trace.Trace, error) {
+	t, err := c.maketableau()
+	if err != nil {
+		return "", nil, err
+	}
+	var tr trace.Trace
+	t.Trace = &tr
+	out, err := t.Decipher(s, c.Key, nil)
+	return out, tr, err
+}
+
+// NewEncipherWriter returns a writer that enciphers text before writing it to w.
+// Close the writer to flush any incomplete rune at the end of the text.
+func (c *Cipher) NewEncipherWriter(w io.Writer) (io.WriteCloser, error) {
+	t, err := c.maketableau()
+	if err != nil {
+		return nil, err
+	}
+	f, err := t.Encipherer(c.Key, nil)
+	if err != nil {
+		return nil, err
+	}
+	return stream.NewWriter(w, stream.Transform(f)), nil
+}
+
+// NewDecipherReader returns a reader that deciphers text read from r.
+func (c *Cipher) NewDecipherReader(r io.Reader) (io.Reader, error) {
+	t, err := c.maketableau()
+	if err != nil {
+		return nil, err
+	}
+	f, err := t.Decipherer(c.Key, nil)
+	if err != nil {
+		return nil, err
+	}
+	return stream.NewReader(r, stream.Transform(f)), nil
+}
+
+// A Compiled cipher holds precomputed lookup tables.
+// A Compiled cipher is immutable and safe for concurrent use.
+type Compiled struct {
+	t   *pasc.Compiled
+	key string
+}
+
+// Compile this cipher.
+func (c *Cipher) Compile() (*Compiled, error) {
+	t, err := c.maketableau()
+	if err != nil {
+		return nil, err
+	}
+	ct, err := t.Compile()
+	if err != nil {
+		return nil, err
+	}
+	return &Compiled{t: ct, key: c.Key}, nil
+}
+
+// Encipher a message.
+func (c *Compiled) Encipher(s string) (string, error) {
+	return c.t.Encipher(s, c.key, nil)
+}
+
+// Decipher a message.
+func (c *Compiled) Decipher(s string) (string, error) {
+	return c.t.Decipher(s, c.key, nil)
+}
+
 // Tableau for encipherment and decipherment.
-func (c *Cipher) tableau() (string, error) {
+func (c *Cipher) Tableau() (string, error) {
 	t, err := c.maketableau()
 	if err != nil {
 		return "", err
 	}
 	return t.Printable()
 }
+
+// TableauMatrix for encipherment and decipherment.
+func (c *Cipher) TableauMatrix() ([][]string, error) {
+	t, err := c.maketableau()
+	if err != nil {
+		return nil, err
+	}
+	return t.Matrix()
+}
@@ -19,7 +19,10 @@ import (
 	"fmt"
 	"io/ioutil"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/merenbach/goldbug/internal/stringutil"
 )
 
 func TestCipher_Encipher(t *testing.T) {
@@ -72,9 +75,153 @@ func TestCipher_Decipher(t *testing.T) {
 	}
 }
 
-func ExampleCipher_tableau() {
+func TestCompiled_Encipher(t *testing.T) {
+	testdata, err := ioutil.ReadFile(filepath.Join("testdata", "cipher_encipher.json"))
+	if err != nil {
+		t.Fatal("Could not read testdata fixture:", err)
+	}
+
+	var tables []struct {
+		Cipher
+
+		Input  string
+		Output string
+	}
+	if err := json.Unmarshal(testdata, &tables); err != nil {
+		t.Fatal("Could not unmarshal testdata:", err)
+	}
+
+	for i, table := range tables {
+		c, err := table.Compile()
+		if err != nil {
+			t.Error("Could not compile:", err)
+			continue
+		}
+		if out, err := c.Encipher(table.Input); err != nil {
+			t.Error("Could not encipher:", err)
+		} else if out != table.Output {
+			t.Errorf("Test %d: compiled encipherment of %q was %q; expected %q", i+1, table.Input, out, table.Output)
+		}
+	}
+}
+
+func TestCompiled_Decipher(t *testing.T) {
+	testdata, err := ioutil.ReadFile(filepath.Join("testdata", "cipher_decipher.json"))
+	if err != nil {
+		t.Fatal("Could not read testdata fixture:", err)
+	}
+
+	var tables []struct {
+		Cipher
+
+		Input  string
+		Output string
+	}
+	if err := json.Unmarshal(testdata, &tables); err != nil {
+		t.Fatal("Could not unmarshal testdata:", err)
+	}
+
+	for i, table := range tables {
+		c, err := table.Compile()
+		if err != nil {
+			t.Error("Could not compile:", err)
+			continue
+		}
+		if out, err := c.Decipher(table.Input); err != nil {
+			t.Error("Could not decipher:", err)
+		} else if out != table.Output {
+			t.Errorf("Test %d: compiled decipherment of %q was %q; expected %q", i+1, table.Input, out, table.Output)
+		}
+	}
+}
+
+// Message for benchmarks.
+var benchmarkMessage = strings.Repeat("THE QUICK BROWN FOX JUMPS OVER THE LAZY DOG. ", 100)
+
+func TestCipher_alphabets(t *testing.T) {
+	tables := []struct {
+		alphabet string
+		keyword  string
+	}{
+		{"ABCDEFGHIJKLMNOPQRST", ""},
+		{"ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789", ""},
+		{"ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789.,?-", ""},
+		{"ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789.,?-", "SECRET42"},
+	}
+
+	for _, table := range tables {
+		c := Cipher{Alphabet: table.alphabet, Keyword: table.keyword, Key: table.alphabet}
+		out, err := c.Encipher(table.alphabet)
+		if err != nil {
+			t.Fatal("Could not encipher:", err)
+		}
+
+		// Each rune in one half of the keyed alphabet must map into the other half
+		n := len(table.alphabet)
+		mixed := stringutil.Deduplicate(table.keyword + table.alphabet)
+		for i, r := range out {
+			if (strings.IndexRune(mixed, r) < n/2) == (strings.IndexByte(mixed, table.alphabet[i]) < n/2) {
+				t.Errorf("Expected %q to map across halves of %q, but got %q", table.alphabet[i], mixed, r)
+			}
+		}
+
+		if back, err := c.Decipher(out); err != nil {
+			t.Error("Could not decipher:", err)
+		} else if back != table.alphabet {
+			t.Errorf("Expected %q to decipher to %q, but got %q", out, table.alphabet, back)
+		}
+		if again, err := c.Encipher(out); err != nil {
+			t.Error("Could not encipher:", err)
+		} else if again != table.alphabet {
+			t.Errorf("Expected reciprocal encipherment of %q, but got %q", out, again)
+		}
+	}
+
+	if _, err := (&Cipher{Alphabet: "ABC"}).Encipher("A"); err == nil {
+		t.Error("Expected error for odd-length alphabet")
+	}
+}
+
+func BenchmarkCipher_Encipher(b *testing.B) {
+	c := Cipher{Key: "LEMON"}
+	for i := 0; i < b.N; i++ {
+		if _, err := c.Encipher(benchmarkMessage); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCompiled_Encipher(b *testing.B) {
+	c, err := (&Cipher{Key: "LEMON"}).Compile()
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := c.Encipher(benchmarkMessage); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCompiled_EncipherParallel(b *testing.B) {
+	c, err := (&Cipher{Key: "LEMON"}).Compile()
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := c.Encipher(benchmarkMessage); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func ExampleCipher_Tableau() {
 	c := Cipher{}
-	out, err := c.tableau()
+	out, err := c.Tableau()
 	if err != nil {
 		fmt.Println("Error:", err)
 	}
@@ -0,0 +1,373 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package enigma simulates the three-rotor Enigma M3 and the four-rotor Enigma M4.
+package enigma
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/merenbach/goldbug/internal/normalize"
+	"github.com/merenbach/goldbug/internal/trace"
+)
+
+// Alphabet of an Enigma machine.
+const Alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// Cipher implements an Enigma machine.
+// Rotors holds space-separated rotor names from left to right, with three for the M3 and four for the M4, whose leftmost rotor is Beta or Gamma and which takes a thin reflector.
+// Rings and Positions hold a letter for each rotor, although Rings may instead hold space-separated numbers from 1 to 26.
+// Plugboard holds space-separated pairs of letters to swap, such as "AV BS CG".
+// The zero value is an M3 with rotors I, II and III, reflector B, and all rings and positions at A.
+type Cipher struct {
+	Rotors     string
+	Reflector  string
+	Rings      string
+	Positions  string
+	Plugboard  string
+	Strict     bool
+	Normalizer normalize.Normalizer
+}
+
+// A rotor is a wired wheel at a particular ring setting and position.
+type rotor struct {
+	name    string
+	fwd     [26]int
+	bwd     [26]int
+	notches [26]bool
+	ring    int
+	pos     int
+}
+
+// Forward passes a signal from right to left through this rotor.
+func (r *rotor) forward(x int) int {
+	return mod(r.fwd[mod(x+r.pos-r.ring, 26)]-r.pos+r.ring, 26)
+}
+
+// Backward passes a signal from left to right through this rotor.
+func (r *rotor) backward(x int) int {
+	return mod(r.bwd[mod(x+r.pos-r.ring, 26)]-r.pos+r.ring, 26)
+}
+
+// AtNotch tests whether this rotor will carry the rotor to its left on the next step.
+func (r *rotor) atNotch() bool {
+	return r.notches[r.pos]
+}
+
+// A Machine is an Enigma machine in a particular state.
+type Machine struct {
+	rotors    []*rotor
+	reflector [26]int
+	plugboard [26]int
+	name      string
+}
+
+// Mod returns a nonnegative remainder.
+func mod(a int, m int) int {
+	return (a%m + m) % m
+}
+
+// Wire a permutation from a string of letters.
+func wire(s string) [26]int {
+	var out [26]int
+	for i, r := range s {
+		out[i] = int(r - 'A')
+	}
+	return out
+}
+
+// Letters parses a string of one letter for each rotor.
+func letters(s string, n int, what string) ([]int, error) {
+	if s == "" {
+		return make([]int, n), nil
+	}
+	s = strings.ToUpper(strings.Join(strings.Fields(s), ""))
+	if len(s) != n {
+		return nil, fmt.Errorf("%s must have one letter for each of %d rotors", what, n)
+	}
+	out := make([]int, n)
+	for i, r := range s {
+		if r < 'A' || r > 'Z' {
+			return nil, fmt.Errorf("%s must consist of letters A to Z", what)
+		}
+		out[i] = int(r - 'A')
+	}
+	return out, nil
+}
+
+// Rings parses ring settings given either as letters or as numbers from 1 to 26.
+func rings(s string, n int) ([]int, error) {
+	if strings.IndexFunc(s, unicode.IsDigit) == (-1) {
+		return letters(s, n, "Rings")
+	}
+	ff := strings.Fields(s)
+	if len(ff) != n {
+		return nil, fmt.Errorf("Rings must have one number for each of %d rotors", n)
+	}
+	out := make([]int, n)
+	for i, f := range ff {
+		v, err := strconv.Atoi(f)
+		if err != nil || v < 1 || v > 26 {
+			return nil, fmt.Errorf("Ring setting %q must be a number from 1 to 26", f)
+		}
+		out[i] = v - 1
+	}
+	return out, nil
+}
+
+// Plugboard parses space-separated pairs of letters into a permutation.
+func plugboard(s string) ([26]int, error) {
+	var out [26]int
+	for i := range out {
+		out[i] = i
+	}
+	for _, f := range strings.Fields(strings.ToUpper(s)) {
+		if len(f) != 2 || f[0] < 'A' || f[0] > 'Z' || f[1] < 'A' || f[1] > 'Z' || f[0] == f[1] {
+			return out, fmt.Errorf("Plugboard pair %q must consist of two different letters", f)
+		}
+		a, b := int(f[0]-'A'), int(f[1]-'A')
+		if out[a] != a || out[b] != b {
+			return out, fmt.Errorf("Plugboard pair %q reuses a letter", f)
+		}
+		out[a], out[b] = b, a
+	}
+	return out, nil
+}
+
+// Machine creates an Enigma machine with the settings of this cipher.
+func (c *Cipher) Machine() (*Machine, error) {
+	names := strings.Fields(c.Rotors)
+	if len(names) == 0 {
+		names = []string{"I", "II", "III"}
+	}
+	reflector := c.Reflector
+	if reflector == "" {
+		reflector = "B"
+		if len(names) == 4 {
+			reflector = "B-thin"
+		}
+	}
+
+	var model string
+	switch len(names) {
+	case 3:
+		model = "M3"
+	case 4:
+		model = "M4"
+	default:
+		return nil, errors.New("Enigma machines must have three or four rotors")
+	}
+
+	ref, ok := reflectorSpecs[reflector]
+	if !ok {
+		return nil, fmt.Errorf("Unknown reflector %q", reflector)
+	}
+	if ref.thin != (model == "M4") {
+		return nil, fmt.Errorf("Reflector %q does not fit the %s", reflector, model)
+	}
+
+	rr, err := rings(c.Rings, len(names))
+	if err != nil {
+		return nil, err
+	}
+	pp, err := letters(c.Positions, len(names), "Positions")
+	if err != nil {
+		return nil, err
+	}
+	pb, err := plugboard(c.Plugboard)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Machine{
+		reflector: wire(ref.wiring),
+		plugboard: pb,
+		name:      model,
+	}
+	seen := make(map[string]bool)
+	for i, name := range names {
+		spec, ok := rotorSpecs[name]
+		if !ok {
+			return nil, fmt.Errorf("Unknown rotor %q", name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("Rotor %q may only be used once", name)
+		}
+		seen[name] = true
+		if fourth := name == "Beta" || name == "Gamma"; fourth != (model == "M4" && i == 0) {
+			return nil, fmt.Errorf("Rotor %q does not fit position %d of the %s", name, i+1, model)
+		}
+
+		r := &rotor{name: name, fwd: wire(spec.wiring), ring: rr[i], pos: pp[i]}
+		for x, y := range r.fwd {
+			r.bwd[y] = x
+		}
+		for _, n := range spec.notches {
+			r.notches[n-'A'] = true
+		}
+		m.rotors = append(m.rotors, r)
+	}
+	return m, nil
+}
+
+// Step the rotors, as the machine does before enciphering each letter.
+// The middle rotor steps both when the right rotor is at its notch and, in the double step, when it is at its own notch.
+func (m *Machine) step() {
+	n := len(m.rotors)
+	left, middle, right := m.rotors[n-3], m.rotors[n-2], m.rotors[n-1]
+
+	if middle.atNotch() {
+		middle.pos = mod(middle.pos+1, 26)
+		left.pos = mod(left.pos+1, 26)
+	} else if right.atNotch() {
+		middle.pos = mod(middle.pos+1, 26)
+	}
+	right.pos = mod(right.pos+1, 26)
+}
+
+// Path of a signal through the machine in its current state, without stepping.
+func (m *Machine) path(x int) int {
+	x = m.plugboard[x]
+	for i := len(m.rotors) - 1; i >= 0; i-- {
+		x = m.rotors[i].forward(x)
+	}
+	x = m.reflector[x]
+	for _, r := range m.rotors {
+		x = r.backward(x)
+	}
+	return m.plugboard[x]
+}
+
+// Press a key, stepping the rotors and returning the lamp that lights.
+// Press returns false, without stepping, for any rune other than the letters A to Z.
+func (m *Machine) Press(r rune) (rune, bool) {
+	if r < 'A' || r > 'Z' {
+		return r, false
+	}
+	m.step()
+	return rune('A' + m.path(int(r-'A'))), true
+}
+
+// Windows shows the letter of each rotor in its window, from left to right.
+func (m *Machine) Windows() string {
+	var b strings.Builder
+	for _, r := range m.rotors {
+		b.WriteRune(rune('A' + r.pos))
+	}
+	return b.String()
+}
+
+// Transcode a message, which is the same operation in both directions, recording an event for each rune if a trace is given.
+// The key of each event is the window letter of the rightmost rotor after stepping.
+func (c *Cipher) transcode(s string, tr *trace.Trace) (string, error) {
+	m, err := c.Machine()
+	if err != nil {
+		return "", err
+	}
+
+	present := func(r rune) bool {
+		return r >= 'A' && r <= 'Z'
+	}
+
+	var b strings.Builder
+	for i, r := range []rune(c.Normalizer.Normalize(s)) {
+		f := c.Normalizer.Fold(r, present)
+		if o, ok := m.Press(f); ok {
+			o = c.Normalizer.Restore(r, f, o)
+			k := m.Windows()[len(m.rotors)-1]
+			tr.Add(trace.Event{Index: i, Input: r, Key: rune(k), Row: (-1), Col: (-1), Output: o, Action: trace.Transcoded})
+			b.WriteRune(o)
+		} else if !c.Strict {
+			tr.Pass(i, r, 0)
+			b.WriteRune(r)
+		} else {
+			tr.Skip(i, r, 0)
+		}
+	}
+	return b.String(), nil
+}
+
+// Encipher a message.
+func (c *Cipher) Encipher(s string) (string, error) {
+	return c.transcode(s, nil)
+}
+
+// Decipher a message.
+func (c *Cipher) Decipher(s string) (string, error) {
+	return c.transcode(s, nil)
+}
+
+// EncipherTrace enciphers a message and records how each rune was produced.
+func (c *Cipher) EncipherTrace(s string) (string, trace.Trace, error) {
+	var tr trace.Trace
+	out, err := c.transcode(s, &tr)
+	return out, tr, err
+}
+
+// DecipherTrace deciphers a message and records how each rune was produced.
+func (c *Cipher) DecipherTrace(s string) (string, trace.Trace, error) {
+	return c.EncipherTrace(s)
+}
+
+// TableauMatrix shows the state of the machine as it enciphers the first letter, with row labels in the first column.
+// Rows follow the signal from the plugboard through each rotor, from right to left, to the reflector, and end with the substitution made by the machine as a whole.
+func (c *Cipher) TableauMatrix() ([][]string, error) {
+	m, err := c.Machine()
+	if err != nil {
+		return nil, err
+	}
+	m.step()
+
+	row := func(label string, f func(int) int) []string {
+		out := []string{label}
+		for x := 0; x < 26; x++ {
+			out = append(out, string(rune('A'+f(x))))
+		}
+		return out
+	}
+
+	out := [][]string{append([]string{""}, strings.Split(Alphabet, "")...)}
+	out = append(out, row("Plugboard", func(x int) int { return m.plugboard[x] }))
+	for i := len(m.rotors) - 1; i >= 0; i-- {
+		r := m.rotors[i]
+		out = append(out, row(fmt.Sprintf("%s at %c", r.name, 'A'+r.pos), r.forward))
+	}
+	out = append(out, row("Reflector", func(x int) int { return m.reflector[x] }))
+	out = append(out, row(m.name, m.path))
+	return out, nil
+}
+
+// Tableau shows the state of the machine as it enciphers the first letter.
+func (c *Cipher) Tableau() (string, error) {
+	mm, err := c.TableauMatrix()
+	if err != nil {
+		return "", err
+	}
+
+	w := 0
+	for _, row := range mm {
+		if len(row[0]) > w {
+			w = len(row[0])
+		}
+	}
+	lines := make([]string, len(mm))
+	for i, row := range mm {
+		lines[i] = fmt.Sprintf("%-*s | %s", w, row[0], strings.Join(row[1:], " "))
+	}
+	return strings.Join(lines, "\n"), nil
+}
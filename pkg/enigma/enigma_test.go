@@ -0,0 +1,193 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package enigma
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/merenbach/goldbug/internal/trace"
+)
+
+func TestCipher_Encipher(t *testing.T) {
+	testdata, err := ioutil.ReadFile(filepath.Join("testdata", "cipher_encipher.json"))
+	if err != nil {
+		t.Fatal("Could not read testdata fixture:", err)
+	}
+
+	var tables []struct {
+		Cipher
+
+		Input  string
+		Output string
+	}
+	if err := json.Unmarshal(testdata, &tables); err != nil {
+		t.Fatal("Could not unmarshal testdata:", err)
+	}
+
+	for _, table := range tables {
+		if out, err := table.Encipher(table.Input); err != nil {
+			t.Error("Could not encipher:", err)
+		} else if out != table.Output {
+			t.Errorf("Expected %q to encipher to %q, but instead got %q", table.Input, table.Output, out)
+		}
+	}
+}
+
+func TestCipher_Decipher(t *testing.T) {
+	testdata, err := ioutil.ReadFile(filepath.Join("testdata", "cipher_decipher.json"))
+	if err != nil {
+		t.Fatal("Could not read testdata fixture:", err)
+	}
+
+	var tables []struct {
+		Cipher
+
+		Input  string
+		Output string
+	}
+	if err := json.Unmarshal(testdata, &tables); err != nil {
+		t.Fatal("Could not unmarshal testdata:", err)
+	}
+
+	for _, table := range tables {
+		if out, err := table.Decipher(table.Input); err != nil {
+			t.Error("Could not decipher:", err)
+		} else if out != table.Output {
+			t.Errorf("Expected %q to decipher to %q, but instead got %q", table.Input, table.Output, out)
+		}
+	}
+}
+
+func TestMachine_doubleStep(t *testing.T) {
+	c := Cipher{Positions: "ADU"}
+	m, err := c.Machine()
+	if err != nil {
+		t.Fatal("Could not create machine:", err)
+	}
+
+	for _, expected := range []string{"ADV", "AEW", "BFX", "BFY"} {
+		m.Press('A')
+		if out := m.Windows(); out != expected {
+			t.Errorf("Expected windows %q, but got %q", expected, out)
+		}
+	}
+}
+
+func TestCipher_thin(t *testing.T) {
+	const msg = "THEQUICKBROWNFOXJUMPSOVERTHELAZYDOG"
+	tables := []struct {
+		m3 Cipher
+		m4 Cipher
+	}{
+		{
+			Cipher{Rotors: "IV II V", Reflector: "B", Rings: "QWE", Positions: "RTY", Plugboard: "AZ QT"},
+			Cipher{Rotors: "Beta IV II V", Reflector: "B-thin", Rings: "AQWE", Positions: "ARTY", Plugboard: "AZ QT"},
+		},
+		{
+			Cipher{Rotors: "VI VII VIII", Reflector: "C", Rings: "01 13 26", Positions: "ZZZ"},
+			Cipher{Rotors: "Gamma VI VII VIII", Reflector: "C-thin", Rings: "01 01 13 26", Positions: "AZZZ"},
+		},
+	}
+
+	for _, table := range tables {
+		expected, err := table.m3.Encipher(msg)
+		if err != nil {
+			t.Fatal("Could not encipher:", err)
+		}
+		if out, err := table.m4.Encipher(msg); err != nil {
+			t.Error("Could not encipher:", err)
+		} else if out != expected {
+			t.Errorf("Expected M4 %+v to match M3 with %q, but got %q", table.m4, expected, out)
+		}
+	}
+}
+
+func TestCipher_EncipherTrace(t *testing.T) {
+	c := Cipher{Strict: true}
+	out, tr, err := c.EncipherTrace("AA-AAA")
+	if err != nil {
+		t.Fatal("Could not encipher:", err)
+	}
+	if out != "BDZGO" {
+		t.Errorf("Expected %q, but got %q", "BDZGO", out)
+	}
+	if len(tr) != 6 {
+		t.Fatalf("Expected 6 events, but got %d", len(tr))
+	}
+
+	tables := []struct {
+		index  int
+		key    rune
+		output rune
+		action trace.Action
+	}{
+		{0, 'B', 'B', trace.Transcoded},
+		{2, 0, 0, trace.Skipped},
+		{3, 'D', 'Z', trace.Transcoded},
+	}
+	for _, table := range tables {
+		e := tr[table.index]
+		if e.Key != table.key || e.Output != table.output || e.Action != table.action {
+			t.Errorf("Unexpected event at index %d: %+v", table.index, e)
+		}
+	}
+}
+
+func TestCipher_errors(t *testing.T) {
+	tables := []Cipher{
+		{Rotors: "I II"},
+		{Rotors: "I II IX"},
+		{Rotors: "I I II"},
+		{Rotors: "Beta I II"},
+		{Rotors: "I II III IV"},
+		{Rotors: "Beta I II III", Reflector: "B"},
+		{Reflector: "B-thin"},
+		{Reflector: "A"},
+		{Rings: "AB"},
+		{Rings: "01 02 27"},
+		{Positions: "A1C"},
+		{Plugboard: "AB BC"},
+		{Plugboard: "AA"},
+		{Plugboard: "ABC"},
+	}
+
+	for _, table := range tables {
+		if _, err := table.Encipher("HELLO"); err == nil {
+			t.Errorf("Expected error enciphering with %+v", table)
+		}
+	}
+}
+
+func ExampleCipher_Tableau() {
+	c := Cipher{Plugboard: "AB"}
+	out, err := c.Tableau()
+	if err != nil {
+		fmt.Println("Error:", err)
+	}
+	fmt.Println(out)
+
+	// Output:
+	//           | A B C D E F G H I J K L M N O P Q R S T U V W X Y Z
+	// Plugboard | B A C D E F G H I J K L M N O P Q R S T U V W X Y Z
+	// III at B  | C E G I K B O Q S W U Y M X D H V F Z J L T R P N A
+	// II at A   | A J D K S I R U X B L H W T M C Q G Z N P Y F V O E
+	// I at A    | E K M F L G D Q V Z N T O W Y H X U S P A I B R C J
+	// Reflector | Y R U H Q S L D P X N G O K M I E B F Z C W V J A T
+	// M3        | B A Q M F E X I H S W P D Y T L C V J O Z R K G N U
+}
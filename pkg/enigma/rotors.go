@@ -0,0 +1,49 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package enigma
+
+// A rotorSpec describes the wiring of a rotor and the window letters at which it carries the rotor to its left.
+type rotorSpec struct {
+	wiring  string
+	notches string
+}
+
+// Rotors by name, with Beta and Gamma as the fixed fourth rotors of the M4.
+var rotorSpecs = map[string]rotorSpec{
+	"I":     {"EKMFLGDQVZNTOWYHXUSPAIBRCJ", "Q"},
+	"II":    {"AJDKSIRUXBLHWTMCQGZNPYFVOE", "E"},
+	"III":   {"BDFHJLCPRTXVZNYEIWGAKMUSQO", "V"},
+	"IV":    {"ESOVPZJAYQUIRHXLNFTGKDCMWB", "J"},
+	"V":     {"VZBRGITYUPSDNHLXAWMJQOFECK", "Z"},
+	"VI":    {"JPGVOUMFYQBENHZRDKASXLICTW", "ZM"},
+	"VII":   {"NZJHGRCXMYSWBOUFAIVLPEKQDT", "ZM"},
+	"VIII":  {"FKQHTLXOCBJSPDZRAMEWNIUYGV", "ZM"},
+	"Beta":  {"LEYJVCNIXWPBQMDRTAKZGFUHOS", ""},
+	"Gamma": {"FSOKANUERHMBTIYCWLQPZXVGJD", ""},
+}
+
+// A reflectorSpec describes the wiring of a reflector and whether it is thin, for use alongside a fourth rotor.
+type reflectorSpec struct {
+	wiring string
+	thin   bool
+}
+
+// Reflectors by name.
+var reflectorSpecs = map[string]reflectorSpec{
+	"B":      {"YRUHQSLDPXNGOKMIEBFZCWVJAT", false},
+	"C":      {"FVPJIAOYEDRZXWGCTKUQSBNMHL", false},
+	"B-thin": {"ENKQAUYWJICOPBLMDXZVFTHRGS", true},
+	"C-thin": {"RDOBJNTKVEHMLFCWZAXGYIPSUQ", true},
+}
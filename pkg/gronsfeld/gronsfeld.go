@@ -15,14 +15,20 @@
 package gronsfeld
 
 import (
+	"io"
+
+	"github.com/merenbach/goldbug/internal/normalize"
 	"github.com/merenbach/goldbug/internal/pasc"
+	"github.com/merenbach/goldbug/internal/stream"
+	"github.com/merenbach/goldbug/internal/trace"
 )
 
 // Cipher implements a Gronsfeld cipher.
 type Cipher struct {
-	Alphabet string
-	Key      string
-	Strict   bool
+	Alphabet   string
+	Key        string
+	Strict     bool
+	Normalizer normalize.Normalizer
 }
 
 func (c *Cipher) maketableau() (*pasc.TabulaRecta, error) {
@@ -38,6 +44,7 @@ func (c *Cipher) maketableau() (*pasc.TabulaRecta, error) {
 		CtAlphabet:  alphabet,
 		KeyAlphabet: digits,
 		Strict:      c.Strict,
+		Normalizer:  c.Normalizer,
 	}, nil
 }
 
@@ -59,11 +66,101 @@ func (c *Cipher) Decipher(s string) (string, error) {
 	return t.Decipher(s, c.Key, nil)
 }
 
+// EncipherTrace enciphers a message and records how each rune was produced.
+func (c *Cipher) EncipherTrace(s string) (string, trace.Trace, error) {
+	t, err := c.maketableau()
+	if err != nil {
+		return "", nil, err
+	}
+	var tr trace.Trace
+	t.Trace = &tr
+	out, err := t.Encipher(s, c.Key, nil)
+	return out, tr, err
+}
+
+// DecipherTrace deciphers a message and records how each rune was produced.
+func (c *Cipher) DecipherTrace(s string) (string, trace.Trace, error) {
+	t, err := c.maketableau()
+	if err != nil {
+		return "", nil, err
+	}
+	var tr trace.Trace
+	t.Trace = &tr
+	out, err := t.Decipher(s, c.Key, nil)
+	return out, tr, err
+}
+
+// NewEncipherWriter returns a writer that enciphers text before writing it to w.
+// Close the writer to flush any incomplete rune at the end of the text.
+func (c *Cipher) NewEncipherWriter(w io.Writer) (io.WriteCloser, error) {
+	t, err := c.maketableau()
+	if err != nil {
+		return nil, err
+	}
+	f, err := t.Encipherer(c.Key, nil)
+	if err != nil {
+		return nil, err
+	}
+	return stream.NewWriter(w, stream.Transform(f)), nil
+}
+
+// NewDecipherReader returns a reader that deciphers text read from r.
+func (c *Cipher) NewDecipherReader(r io.Reader) (io.Reader, error) {
+	t, err := c.maketableau()
+	if err != nil {
+		return nil, err
+	}
+	f, err := t.Decipherer(c.Key, nil)
+	if err != nil {
+		return nil, err
+	}
+	return stream.NewReader(r, stream.Transform(f)), nil
+}
+
+// A Compiled cipher holds precomputed lookup tables.
+// A Compiled cipher is immutable and safe for concurrent use.
+type Compiled struct {
+	t   *pasc.Compiled
+	key string
+}
+
+// Compile this cipher.
+func (c *Cipher) Compile() (*Compiled, error) {
+	t, err := c.maketableau()
+	if err != nil {
+		return nil, err
+	}
+	ct, err := t.Compile()
+	if err != nil {
+		return nil, err
+	}
+	return &Compiled{t: ct, key: c.Key}, nil
+}
+
+// Encipher a message.
+func (c *Compiled) Encipher(s string) (string, error) {
+	return c.t.Encipher(s, c.key, nil)
+}
+
+// Decipher a message.
+func (c *Compiled) Decipher(s string) (string, error) {
+	return c.t.Decipher(s, c.key, nil)
+}
+
 // Tableau for encipherment and decipherment.
-func (c *Cipher) tableau() (string, error) {
+func (c *Cipher) Tableau() (string, error) {
 	t, err := c.maketableau()
 	if err != nil {
 		return "", err
 	}
 	return t.Printable()
 }
+
+// TableauMatrix for encipherment and decipherment.
+func (c *Cipher) TableauMatrix() ([][]string, error) {
+	t, err := c.maketableau()
+	if err != nil {
+		return nil, err
+	}
+	return t.Matrix()
+}
@@ -0,0 +1,252 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package homophonic implements a homophonic substitution cipher, which gives common runes several substitutes to flatten their frequencies.
+package homophonic
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"errors"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/merenbach/goldbug/internal/normalize"
+	"github.com/merenbach/goldbug/internal/trace"
+)
+
+// A Selection determines which homophone stands for each occurrence of a rune.
+type Selection uint8
+
+const (
+	// Cyclic takes the homophones of each rune in turn.
+	Cyclic Selection = iota
+
+	// Random takes any homophone of each rune, drawing from a source seeded with Seed, so that the same seed always makes the same choices.
+	// Without a Seed, the source is seeded unpredictably for each message.
+	Random
+)
+
+// Cipher implements a homophonic substitution cipher.
+// Table defaults to one from a Generator with default settings.
+type Cipher struct {
+	Table      Table
+	Selection  Selection
+	Seed       *int64
+	Strict     bool
+	Normalizer normalize.Normalizer
+}
+
+// A codebook holds the lookup tables for a homophonic table.
+type codebook struct {
+	table  Table
+	runes  []string
+	width  int
+	lookup map[string]rune
+	labels map[rune]bool
+}
+
+func (c *Cipher) makecodebook() (*codebook, error) {
+	t := c.Table
+	if t == nil {
+		var err error
+		if t, err = (&Generator{}).Table(); err != nil {
+			return nil, err
+		}
+	}
+	width, err := t.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	cb := &codebook{
+		table:  t,
+		runes:  t.runes(),
+		width:  width,
+		lookup: make(map[string]rune),
+		labels: make(map[rune]bool),
+	}
+	for k, hh := range t {
+		for _, h := range hh {
+			cb.lookup[h] = []rune(k)[0]
+			for _, r := range h {
+				cb.labels[r] = true
+			}
+		}
+	}
+	return cb, nil
+}
+
+// Encipher a message.
+func (c *Cipher) Encipher(s string) (string, error) {
+	out, _, err := c.EncipherTrace(s)
+	return out, err
+}
+
+// EncipherTrace enciphers a message and records how each rune was produced.
+// The row of each event is the place of its rune among the runes of the table, the column is the place of the homophone chosen, and the output is the first rune of that homophone.
+func (c *Cipher) EncipherTrace(s string) (string, trace.Trace, error) {
+	cb, err := c.makecodebook()
+	if err != nil {
+		return "", nil, err
+	}
+
+	var pick func(n int, uses int) int
+	switch c.Selection {
+	case Cyclic:
+		pick = func(n int, uses int) int {
+			return uses % n
+		}
+	case Random:
+		rng := rand.New(rand.NewSource(seed(c.Seed)))
+		pick = func(n int, uses int) int {
+			return rng.Intn(n)
+		}
+	default:
+		return "", nil, errors.New("Unknown selection")
+	}
+
+	present := func(r rune) bool {
+		_, ok := cb.table[string(r)]
+		return ok
+	}
+	uses := make(map[rune]int)
+
+	var tr trace.Trace
+	var b strings.Builder
+	for i, r := range []rune(c.Normalizer.Normalize(s)) {
+		if f := c.Normalizer.Fold(r, present); present(f) {
+			hh := cb.table[string(f)]
+			j := pick(len(hh), uses[f])
+			uses[f]++
+			row := sort.SearchStrings(cb.runes, string(f))
+			tr.Add(trace.Event{Index: i, Input: r, Row: row, Col: j, Output: []rune(hh[j])[0], Action: trace.Transcoded})
+			b.WriteString(hh[j])
+		} else if !c.Strict {
+			tr.Pass(i, r, 0)
+			b.WriteRune(r)
+		} else {
+			tr.Skip(i, r, 0)
+		}
+	}
+	return b.String(), tr, nil
+}
+
+// Decipher a message.
+// Each run of runes that spells a homophone is deciphered, and other runes are passed through unless strict.
+func (c *Cipher) Decipher(s string) (string, error) {
+	out, _, err := c.DecipherTrace(s)
+	return out, err
+}
+
+// DecipherTrace deciphers a message and records how each rune was produced.
+// Each homophone yields a single event, with its first rune as the input.
+func (c *Cipher) DecipherTrace(s string) (string, trace.Trace, error) {
+	cb, err := c.makecodebook()
+	if err != nil {
+		return "", nil, err
+	}
+
+	var tr trace.Trace
+	var b strings.Builder
+	rr := []rune(s)
+	for i := 0; i < len(rr); i++ {
+		if i+cb.width <= len(rr) {
+			if r, ok := cb.lookup[string(rr[i:i+cb.width])]; ok {
+				row := sort.SearchStrings(cb.runes, string(r))
+				col := indexOf(cb.table[string(r)], string(rr[i:i+cb.width]))
+				tr.Add(trace.Event{Index: i, Input: rr[i], Row: row, Col: col, Output: r, Action: trace.Transcoded})
+				b.WriteRune(r)
+				i += cb.width - 1
+				continue
+			}
+		}
+		if !c.Strict {
+			tr.Pass(i, rr[i], 0)
+			b.WriteRune(rr[i])
+		} else {
+			tr.Skip(i, rr[i], 0)
+		}
+	}
+	return b.String(), tr, nil
+}
+
+// Index of a string in a slice, or (-1) if it is absent.
+func indexOf(ss []string, s string) int {
+	for i, v := range ss {
+		if v == s {
+			return i
+		}
+	}
+	return (-1)
+}
+
+// Labels that may appear in homophones.
+func (c *Cipher) Labels() (string, error) {
+	cb, err := c.makecodebook()
+	if err != nil {
+		return "", err
+	}
+	out := make([]rune, 0, len(cb.labels))
+	for r := range cb.labels {
+		out = append(out, r)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i] < out[j]
+	})
+	return string(out), nil
+}
+
+// Tableau listing the homophones of each rune.
+func (c *Cipher) Tableau() (string, error) {
+	cb, err := c.makecodebook()
+	if err != nil {
+		return "", err
+	}
+
+	lines := make([]string, len(cb.runes))
+	for i, k := range cb.runes {
+		lines[i] = k + " | " + strings.Join(cb.table[k], " ")
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// TableauMatrix listing the homophones of each rune, with the rune in the first column.
+func (c *Cipher) TableauMatrix() ([][]string, error) {
+	cb, err := c.makecodebook()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([][]string, len(cb.runes))
+	for i, k := range cb.runes {
+		out[i] = append([]string{k}, cb.table[k]...)
+	}
+	return out, nil
+}
+
+// Seed for a random source, which is the given seed if set, or else drawn from crypto/rand.
+// Seed falls back to the clock should crypto/rand fail.
+func seed(s *int64) int64 {
+	if s != nil {
+		return *s
+	}
+	var n int64
+	if err := binary.Read(crand.Reader, binary.LittleEndian, &n); err != nil {
+		return time.Now().UnixNano()
+	}
+	return n
+}
@@ -0,0 +1,263 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package homophonic
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCipher_Encipher(t *testing.T) {
+	testdata, err := ioutil.ReadFile(filepath.Join("testdata", "cipher_encipher.json"))
+	if err != nil {
+		t.Fatal("Could not read testdata fixture:", err)
+	}
+
+	var tables []struct {
+		Cipher
+
+		Input  string
+		Output string
+	}
+	if err := json.Unmarshal(testdata, &tables); err != nil {
+		t.Fatal("Could not unmarshal testdata:", err)
+	}
+
+	for _, table := range tables {
+		if out, err := table.Encipher(table.Input); err != nil {
+			t.Error("Could not encipher:", err)
+		} else if out != table.Output {
+			t.Errorf("Expected %q to encipher to %q, but instead got %q", table.Input, table.Output, out)
+		}
+	}
+}
+
+func TestCipher_Decipher(t *testing.T) {
+	testdata, err := ioutil.ReadFile(filepath.Join("testdata", "cipher_decipher.json"))
+	if err != nil {
+		t.Fatal("Could not read testdata fixture:", err)
+	}
+
+	var tables []struct {
+		Cipher
+
+		Input  string
+		Output string
+	}
+	if err := json.Unmarshal(testdata, &tables); err != nil {
+		t.Fatal("Could not unmarshal testdata:", err)
+	}
+
+	for _, table := range tables {
+		if out, err := table.Decipher(table.Input); err != nil {
+			t.Error("Could not decipher:", err)
+		} else if out != table.Output {
+			t.Errorf("Expected %q to decipher to %q, but instead got %q", table.Input, table.Output, out)
+		}
+	}
+}
+
+func TestCipher_Random(t *testing.T) {
+	n := int64(1)
+	c := Cipher{Selection: Random, Seed: &n}
+	out, err := c.Encipher("ATTACK AT DAWN")
+	if err != nil {
+		t.Fatal("Could not encipher:", err)
+	}
+	if out != "269156582710 2696 03692236" {
+		t.Errorf("Expected %q, but got %q", "269156582710 2696 03692236", out)
+	}
+	if again, _ := c.Encipher("ATTACK AT DAWN"); again != out {
+		t.Errorf("Expected the same seed to give %q again, but got %q", out, again)
+	}
+	if in, err := c.Decipher(out); err != nil {
+		t.Error("Could not decipher:", err)
+	} else if in != "ATTACK AT DAWN" {
+		t.Errorf("Expected %q, but got %q", "ATTACK AT DAWN", in)
+	}
+
+	n = 2
+	if other, _ := c.Encipher("ATTACK AT DAWN"); other == out {
+		t.Errorf("Expected another seed to give other choices than %q", out)
+	}
+
+	c.Seed = nil
+	msg := strings.Repeat("ATTACK AT DAWN ", 10)
+	first, _ := c.Encipher(msg)
+	if second, _ := c.Encipher(msg); second == first {
+		t.Errorf("Expected no seed to give other choices each time than %q", first)
+	}
+	if in, err := c.Decipher(first); err != nil {
+		t.Error("Could not decipher:", err)
+	} else if in != msg {
+		t.Errorf("Expected %q, but got %q", msg, in)
+	}
+
+	c.Selection = 2
+	if _, err := c.Encipher("ATTACK AT DAWN"); err == nil {
+		t.Error("Expected error for unknown selection")
+	}
+}
+
+func TestCipher_EncipherTrace(t *testing.T) {
+	c := Cipher{Table: Table{"A": {"1", "2"}, "B": {"3"}}}
+	_, tr, err := c.EncipherTrace("ABA")
+	if err != nil {
+		t.Fatal("Could not encipher:", err)
+	}
+
+	expected := []struct {
+		row, col int
+		output   rune
+	}{
+		{0, 0, '1'},
+		{1, 0, '3'},
+		{0, 1, '2'},
+	}
+	for i, e := range expected {
+		if tr[i].Row != e.row || tr[i].Col != e.col || tr[i].Output != e.output {
+			t.Errorf("Event %d: expected row %d, column %d and output %q, but got %+v", i, e.row, e.col, e.output, tr[i])
+		}
+	}
+}
+
+func TestTable_validate(t *testing.T) {
+	tables := []Table{
+		{},
+		{"AB": {"1"}},
+		{"A": {}},
+		{"A": {"1"}, "B": {"1"}},
+		{"A": {"1"}, "B": {"22"}},
+		{"A": {""}},
+	}
+	for _, table := range tables {
+		if _, err := table.validate(); err == nil {
+			t.Errorf("Expected error validating %v", table)
+		}
+	}
+}
+
+func TestReadTable(t *testing.T) {
+	tab, err := (&Generator{Keyword: "KANGAROO"}).Table()
+	if err != nil {
+		t.Fatal("Could not generate table:", err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteTable(&buf, tab); err != nil {
+		t.Fatal("Could not write table:", err)
+	}
+	if other, err := ReadTable(&buf); err != nil {
+		t.Error("Could not read table:", err)
+	} else if !reflect.DeepEqual(tab, other) {
+		t.Errorf("Expected table %v to survive JSON, but got %v", tab, other)
+	}
+
+	if _, err := ReadTable(strings.NewReader(`{"A": ["1"], "B": ["1"]}`)); err == nil {
+		t.Error("Expected error reading a table with a repeated homophone")
+	}
+}
+
+func TestGenerator_Table(t *testing.T) {
+	tab, err := (&Generator{}).Table()
+	if err != nil {
+		t.Fatal("Could not generate table:", err)
+	}
+
+	var n int
+	for _, hh := range tab {
+		n += len(hh)
+	}
+	if n != Codes {
+		t.Errorf("Expected %d homophones, but got %d", Codes, n)
+	}
+
+	// Shares follow English frequencies, with one apiece for the rarest letters
+	for k, expected := range map[string]int{"E": 12, "T": 9, "A": 8, "J": 1, "Q": 1, "Z": 1} {
+		if len(tab[k]) != expected {
+			t.Errorf("Expected %d homophones for %q, but got %v", expected, k, tab[k])
+		}
+	}
+
+	// A keyword reorders the homophones but keeps each share
+	keyed, err := (&Generator{Keyword: "KANGAROO"}).Table()
+	if err != nil {
+		t.Fatal("Could not generate table:", err)
+	}
+	if reflect.DeepEqual(tab, keyed) {
+		t.Error("Expected a keyword to change the table")
+	}
+	for k := range tab {
+		if len(tab[k]) != len(keyed[k]) {
+			t.Errorf("Expected %d homophones for %q, but got %d", len(tab[k]), k, len(keyed[k]))
+		}
+	}
+
+	g := Generator{Alphabet: "AB", Frequencies: map[rune]float64{'A': 3, 'B': 1}, Symbols: "!@#$%"}
+	if tab, err := g.Table(); err != nil {
+		t.Error("Could not generate table:", err)
+	} else if expected := (Table{"A": {"!", "#", "$", "%"}, "B": {"@"}}); !reflect.DeepEqual(tab, expected) {
+		t.Errorf("Expected %v, but got %v", expected, tab)
+	}
+
+	// Frequencies apply to either case
+	lower, err := (&Generator{Alphabet: "abcdefghijklmnopqrstuvwxyz"}).Table()
+	if err != nil {
+		t.Fatal("Could not generate table:", err)
+	}
+	for k, expected := range map[string]int{"e": 12, "t": 9, "a": 8, "z": 1} {
+		if len(lower[k]) != expected {
+			t.Errorf("Expected %d homophones for %q, but got %v", expected, k, lower[k])
+		}
+	}
+
+	for _, g := range []Generator{
+		{Codes: 25},
+		{Codes: -1},
+		{Symbols: "!!"},
+		{Alphabet: "AA"},
+		{Alphabet: "АБВГД"},
+	} {
+		if _, err := g.Table(); err == nil {
+			t.Errorf("Expected error generating a table with %+v", g)
+		}
+	}
+}
+
+func ExampleCipher_Tableau() {
+	tab, err := (&Generator{Alphabet: "ABCDE", Symbols: "!@#$%^&*()", Keyword: "SECRET"}).Table()
+	if err != nil {
+		fmt.Println("Error:", err)
+	}
+	c := Cipher{Table: tab}
+	out, err := c.Tableau()
+	if err != nil {
+		fmt.Println("Error:", err)
+	}
+	fmt.Println(out)
+
+	// Output:
+	// A | # $ !
+	// B | (
+	// C | @
+	// D | *
+	// E | % ) & ^
+}
@@ -0,0 +1,260 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package homophonic
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/merenbach/goldbug/internal/alphabet"
+	"github.com/merenbach/goldbug/internal/masc"
+)
+
+// English letter frequencies, in percent.
+var English = map[rune]float64{
+	'A': 8.167, 'B': 1.492, 'C': 2.782, 'D': 4.253, 'E': 12.702, 'F': 2.228, 'G': 2.015,
+	'H': 6.094, 'I': 6.966, 'J': 0.153, 'K': 0.772, 'L': 4.025, 'M': 2.406, 'N': 6.749,
+	'O': 7.507, 'P': 1.929, 'Q': 0.095, 'R': 5.987, 'S': 6.327, 'T': 9.056, 'U': 2.758,
+	'V': 0.978, 'W': 2.360, 'X': 0.150, 'Y': 1.974, 'Z': 0.074,
+}
+
+// Codes to generate by default, which makes two-digit codes from 00 to 99.
+const Codes = 100
+
+// A Table maps each rune, written as a string, to its homophones.
+// Every homophone in a table must be unique and have the same number of runes, so that ciphertext can be read without separators.
+type Table map[string][]string
+
+// Runes in the table, in order.
+func (t Table) runes() []string {
+	out := make([]string, 0, len(t))
+	for k := range t {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Validate the table, returning the length of its homophones.
+func (t Table) validate() (int, error) {
+	if len(t) == 0 {
+		return 0, errors.New("Table must not be empty")
+	}
+
+	width := (-1)
+	seen := make(map[string]string)
+	for _, k := range t.runes() {
+		if utf8.RuneCountInString(k) != 1 {
+			return 0, fmt.Errorf("Table key %q must be a single character", k)
+		}
+		if len(t[k]) == 0 {
+			return 0, fmt.Errorf("Character %q has no homophones", k)
+		}
+		for _, h := range t[k] {
+			n := utf8.RuneCountInString(h)
+			if width == (-1) {
+				width = n
+			}
+			if n == 0 || n != width {
+				return 0, fmt.Errorf("Homophone %q must have %d characters like the rest", h, width)
+			}
+			if other, ok := seen[h]; ok {
+				return 0, fmt.Errorf("Homophone %q stands for both %q and %q", h, other, k)
+			}
+			seen[h] = k
+		}
+	}
+	return width, nil
+}
+
+// ReadTable decodes a table from JSON and validates it.
+func ReadTable(r io.Reader) (Table, error) {
+	var t Table
+	if err := json.NewDecoder(r).Decode(&t); err != nil {
+		return nil, err
+	}
+	if _, err := t.validate(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// WriteTable encodes a table as indented JSON.
+func WriteTable(w io.Writer, t Table) error {
+	e := json.NewEncoder(w)
+	e.SetIndent("", "    ")
+	return e.Encode(t)
+}
+
+// A Generator makes a table that allots homophones to each rune of an alphabet in proportion to its frequency, with at least one apiece.
+// Frequencies are looked up in either case, and at least one rune of the alphabet must have one.
+// Homophones are numbers of equal width counting up from zero, as many as Codes asks, or else the runes of Symbols.
+// They are dealt out to the runes of the alphabet in turn, so that the commonest runes do not get runs of neighboring numbers, after being written in rows under the keyword, if any, and read off by columns in its alphabetical order.
+type Generator struct {
+	Alphabet    string
+	Frequencies map[rune]float64
+	Codes       int
+	Symbols     string
+	Keyword     string
+}
+
+// Frequency of a rune, looked up in either case if the rune itself has none.
+func frequency(freqs map[rune]float64, r rune) float64 {
+	if f, ok := freqs[r]; ok {
+		return f
+	}
+	if f, ok := freqs[unicode.ToUpper(r)]; ok {
+		return f
+	}
+	return freqs[unicode.ToLower(r)]
+}
+
+// Shares of n homophones for each rune of an alphabet, by the largest remainder method, with at least one apiece.
+func shares(aa []rune, freqs map[rune]float64, n int) ([]int, error) {
+	if n < len(aa) {
+		return nil, fmt.Errorf("%d homophones are too few for %d characters", n, len(aa))
+	}
+
+	var total float64
+	for _, r := range aa {
+		if f := frequency(freqs, r); f > 0 {
+			total += f
+		}
+	}
+	if total == 0 {
+		return nil, errors.New("No character of the alphabet has a frequency")
+	}
+
+	out := make([]int, len(aa))
+	quotas := make([]float64, len(aa))
+	left := n
+	for i, r := range aa {
+		if f := frequency(freqs, r); f > 0 {
+			quotas[i] = float64(n) * f / total
+		}
+		out[i] = int(quotas[i])
+		if out[i] < 1 {
+			out[i] = 1
+		}
+		left -= out[i]
+	}
+
+	// Runes furthest below their quotas gain homophones, and, if runes raised to one apiece overdraw the total, those furthest above lose them
+	for ; left > 0; left-- {
+		best := 0
+		for i := range out {
+			if quotas[i]-float64(out[i]) > quotas[best]-float64(out[best]) {
+				best = i
+			}
+		}
+		out[best]++
+	}
+	for ; left < 0; left++ {
+		best := (-1)
+		for i := range out {
+			if out[i] > 1 && (best == (-1) || quotas[i]-float64(out[i]) < quotas[best]-float64(out[best])) {
+				best = i
+			}
+		}
+		out[best]--
+	}
+	return out, nil
+}
+
+// Homophones to deal out, in keyed order.
+func (g *Generator) homophones() ([]string, error) {
+	var hh []string
+	if g.Symbols != "" {
+		if err := alphabet.Validate(g.Symbols); err != nil {
+			return nil, err
+		}
+		for _, r := range g.Symbols {
+			hh = append(hh, string(r))
+		}
+	} else {
+		n := g.Codes
+		if n == 0 {
+			n = Codes
+		}
+		if n < 0 {
+			return nil, errors.New("Codes must be positive")
+		}
+		width := len(strconv.Itoa(n - 1))
+		for i := 0; i < n; i++ {
+			hh = append(hh, fmt.Sprintf("%0*d", width, i))
+		}
+	}
+
+	kk := []rune(g.Keyword)
+	if len(kk) == 0 {
+		return hh, nil
+	}
+	cols := make([]int, len(kk))
+	for i := range cols {
+		cols[i] = i
+	}
+	sort.SliceStable(cols, func(i, j int) bool {
+		return kk[cols[i]] < kk[cols[j]]
+	})
+	out := make([]string, 0, len(hh))
+	for _, c := range cols {
+		for i := c; i < len(hh); i += len(kk) {
+			out = append(out, hh[i])
+		}
+	}
+	return out, nil
+}
+
+// Table generated by this generator.
+func (g *Generator) Table() (Table, error) {
+	a, freqs := g.Alphabet, g.Frequencies
+	if a == "" {
+		a = masc.Alphabet
+	}
+	if freqs == nil {
+		freqs = English
+	}
+	if err := alphabet.Validate(a); err != nil {
+		return nil, err
+	}
+
+	hh, err := g.homophones()
+	if err != nil {
+		return nil, err
+	}
+	aa := []rune(a)
+	ss, err := shares(aa, freqs, len(hh))
+	if err != nil {
+		return nil, err
+	}
+
+	// Deal one homophone at a time to each rune that still has some of its share to take
+	t := make(Table, len(aa))
+	for len(hh) > 0 {
+		for i, r := range aa {
+			if k := string(r); len(t[k]) < ss[i] {
+				t[k] = append(t[k], hh[0])
+				hh = hh[1:]
+			}
+		}
+	}
+	return t, nil
+}
@@ -0,0 +1,138 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ita2 encodes and decodes text in the five-bit International Telegraph Alphabet No. 2, or Baudot-Murray code.
+package ita2
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// A Code is a five-bit ITA2 character, with the first impulse in the most significant bit.
+type Code byte
+
+// Codes with the same meaning in both shifts.
+const (
+	Null           Code = 0x00
+	CarriageReturn Code = 0x02
+	Space          Code = 0x04
+	LineFeed       Code = 0x08
+	Figures        Code = 0x1b
+	Letters        Code = 0x1f
+)
+
+// Size of the code, which is the number of distinct five-bit codes.
+const Size = 32
+
+// Tape notation for codes in order of value, as used at Bletchley Park, with / for null, 9 for space, 3 for carriage return, 4 for line feed, 5 for figure shift and 8 for letter shift.
+const Tape = "/T3O9HNM4LRGIPCVEZDBSYFXAWJ5UQK8"
+
+// Letters for each code in letter shift, with zero where none is printed.
+var letters = [Size]rune{
+	0, 'T', '\r', 'O', ' ', 'H', 'N', 'M', '\n', 'L', 'R', 'G', 'I', 'P', 'C', 'V',
+	'E', 'Z', 'D', 'B', 'S', 'Y', 'F', 'X', 'A', 'W', 'J', 0, 'U', 'Q', 'K', 0,
+}
+
+// Figures for each code in figure shift, with zero where none is printed or the code is reserved for national use.
+var figures = [Size]rune{
+	0, '5', '\r', '9', ' ', 0, ',', '.', '\n', ')', '4', 0, '8', '0', ':', '=',
+	'3', '+', '\x05', '?', '\'', '6', 0, '/', '-', '2', '\a', 0, '7', '1', '(', 0,
+}
+
+// Index a table by printed rune.
+func index(table [Size]rune) map[rune]Code {
+	out := make(map[rune]Code)
+	for i, r := range table {
+		if r != 0 {
+			out[r] = Code(i)
+		}
+	}
+	return out
+}
+
+var letterCodes, figureCodes = index(letters), index(figures)
+
+// Encode text in ITA2, starting in letter shift and shifting as needed.
+// Encode folds lowercase letters to uppercase and returns an error for any rune without a code.
+func Encode(s string) ([]Code, error) {
+	var out []Code
+	figs := false
+	for _, r := range s {
+		r = unicode.ToUpper(r)
+		lc, inLetters := letterCodes[r]
+		fc, inFigures := figureCodes[r]
+		switch {
+		case inLetters && inFigures:
+			// Spaces, carriage returns and line feeds need no shift.
+			out = append(out, lc)
+		case inLetters:
+			if figs {
+				out, figs = append(out, Letters), false
+			}
+			out = append(out, lc)
+		case inFigures:
+			if !figs {
+				out, figs = append(out, Figures), true
+			}
+			out = append(out, fc)
+		default:
+			return nil, fmt.Errorf("Character %q has no ITA2 code", r)
+		}
+	}
+	return out, nil
+}
+
+// Decode ITA2 codes into text, starting in letter shift.
+// Decode drops nulls, shifts and codes without a printed character in the current shift.
+func Decode(cc []Code) string {
+	var b strings.Builder
+	table := &letters
+	for _, c := range cc {
+		switch c &= Size - 1; c {
+		case Letters:
+			table = &letters
+		case Figures:
+			table = &figures
+		default:
+			if r := table[c]; r != 0 {
+				b.WriteRune(r)
+			}
+		}
+	}
+	return b.String()
+}
+
+// Format codes in tape notation.
+func Format(cc []Code) string {
+	out := make([]byte, len(cc))
+	for i, c := range cc {
+		out[i] = Tape[c&(Size-1)]
+	}
+	return string(out)
+}
+
+// Parse codes from tape notation, folding lowercase letters to uppercase.
+func Parse(s string) ([]Code, error) {
+	out := make([]Code, 0, len(s))
+	for _, r := range strings.ToUpper(s) {
+		i := strings.IndexRune(Tape, r)
+		if i == (-1) {
+			return nil, fmt.Errorf("Character %q is not in tape notation", r)
+		}
+		out = append(out, Code(i))
+	}
+	return out, nil
+}
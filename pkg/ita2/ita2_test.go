@@ -0,0 +1,96 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ita2
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	tables := []struct {
+		input    string
+		expected string
+	}{
+		{"HELLO WORLD", "HELLO9WORLD"},
+		{"hello", "HELLO"},
+		{"RV 10", "RV95QP"},
+		{"1 A", "5Q98A"},
+		{"A.\r\nB", "A5M348B"},
+		{"", ""},
+	}
+
+	for _, table := range tables {
+		cc, err := Encode(table.input)
+		if err != nil {
+			t.Error("Could not encode:", err)
+		} else if out := Format(cc); out != table.expected {
+			t.Errorf("Expected %q to encode to %q, but got %q", table.input, table.expected, out)
+		}
+	}
+
+	if _, err := Encode("100%"); err == nil {
+		t.Error("Expected error for a character without a code")
+	}
+}
+
+func TestDecode(t *testing.T) {
+	tables := []struct {
+		input    string
+		expected string
+	}{
+		{"HELLO9WORLD", "HELLO WORLD"},
+		{"RV95QP", "RV 10"},
+		{"55M88", "."},
+		{"/A/5F/8B", "AB"},
+	}
+
+	for _, table := range tables {
+		cc, err := Parse(table.input)
+		if err != nil {
+			t.Error("Could not parse:", err)
+		} else if out := Decode(cc); out != table.expected {
+			t.Errorf("Expected %q to decode to %q, but got %q", table.input, table.expected, out)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	cc, err := Parse("/t38")
+	if err != nil {
+		t.Fatal("Could not parse:", err)
+	}
+	if expected := []Code{Null, 1, CarriageReturn, Letters}; !reflect.DeepEqual(cc, expected) {
+		t.Errorf("Expected %v, but got %v", expected, cc)
+	}
+
+	if _, err := Parse("HI!"); err == nil {
+		t.Error("Expected error for a character outside tape notation")
+	}
+}
+
+func ExampleEncode() {
+	cc, err := Encode("ATTACK AT 0600")
+	if err != nil {
+		fmt.Println("Error:", err)
+	}
+	fmt.Println(Format(cc))
+	fmt.Println(Decode(cc))
+
+	// Output:
+	// ATTACK9AT95PYPP
+	// ATTACK AT 0600
+}
@@ -15,17 +15,22 @@
 package keyword
 
 import (
+	"io"
 	"log"
 
 	"github.com/merenbach/goldbug/internal/masc"
+	"github.com/merenbach/goldbug/internal/normalize"
+	"github.com/merenbach/goldbug/internal/stream"
 	"github.com/merenbach/goldbug/internal/stringutil"
+	"github.com/merenbach/goldbug/internal/trace"
 )
 
 // Cipher implements a keyword cipher.
 type Cipher struct {
-	Alphabet string
-	Keyword  string
-	Strict   bool
+	Alphabet   string
+	Keyword    string
+	Strict     bool
+	Normalizer normalize.Normalizer
 }
 
 func (c *Cipher) maketableau() (*masc.Tableau, error) {
@@ -39,6 +44,7 @@ func (c *Cipher) maketableau() (*masc.Tableau, error) {
 		PtAlphabet: ptAlphabet,
 		CtAlphabet: ctAlphabet,
 		Strict:     c.Strict,
+		Normalizer: c.Normalizer,
 	}, nil
 }
 
@@ -62,6 +68,83 @@ func (c *Cipher) Decipher(s string) (string, error) {
 	return t.Decipher(s)
 }
 
+// EncipherTrace enciphers a message and records how each rune was produced.
+func (c *Cipher) EncipherTrace(s string) (string, trace.Trace, error) {
+	t, err := c.maketableau()
+	if err != nil {
+		log.Println("Could not calculate alphabets")
+		return "", nil, err
+	}
+	var tr trace.Trace
+	t.Trace = &tr
+	out, err := t.Encipher(s)
+	return out, tr, err
+}
+
+// DecipherTrace deciphers a message and records how each rune was produced.
+func (c *Cipher) DecipherTrace(s string) (string, trace.Trace, error) {
+	t, err := c.maketableau()
+	if err != nil {
+		log.Println("Could not calculate alphabets")
+		return "", nil, err
+	}
+	var tr trace.Trace
+	t.Trace = &tr
+	out, err := t.Decipher(s)
+	return out, tr, err
+}
+
+// NewEncipherWriter returns a writer that enciphers text before writing it to w.
+// Close the writer to flush any incomplete rune at the end of the text.
+func (c *Cipher) NewEncipherWriter(w io.Writer) (io.WriteCloser, error) {
+	t, err := c.maketableau()
+	if err != nil {
+		log.Println("Could not calculate alphabets")
+		return nil, err
+	}
+	return stream.NewWriter(w, t.Encipher), nil
+}
+
+// NewDecipherReader returns a reader that deciphers text read from r.
+func (c *Cipher) NewDecipherReader(r io.Reader) (io.Reader, error) {
+	t, err := c.maketableau()
+	if err != nil {
+		log.Println("Could not calculate alphabets")
+		return nil, err
+	}
+	return stream.NewReader(r, t.Decipher), nil
+}
+
+// A Compiled cipher holds precomputed lookup tables.
+// A Compiled cipher is immutable and safe for concurrent use.
+type Compiled struct {
+	t *masc.Compiled
+}
+
+// Compile this cipher.
+func (c *Cipher) Compile() (*Compiled, error) {
+	t, err := c.maketableau()
+	if err != nil {
+		log.Println("Could not calculate alphabets")
+		return nil, err
+	}
+	ct, err := t.Compile()
+	if err != nil {
+		return nil, err
+	}
+	return &Compiled{t: ct}, nil
+}
+
+// Encipher a message.
+func (c *Compiled) Encipher(s string) (string, error) {
+	return c.t.Encipher(s)
+}
+
+// Decipher a message.
+func (c *Compiled) Decipher(s string) (string, error) {
+	return c.t.Decipher(s)
+}
+
 // Tableau for this cipher.
 func (c *Cipher) Tableau() (string, error) {
 	t, err := c.maketableau()
@@ -71,3 +154,13 @@ func (c *Cipher) Tableau() (string, error) {
 	}
 	return t.Printable()
 }
+
+// TableauMatrix for this cipher.
+func (c *Cipher) TableauMatrix() ([][]string, error) {
+	t, err := c.maketableau()
+	if err != nil {
+		log.Println("Could not calculate alphabets")
+		return nil, err
+	}
+	return t.Matrix()
+}
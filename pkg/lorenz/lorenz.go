@@ -0,0 +1,285 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package lorenz simulates the Lorenz SZ40 and SZ42 teleprinter cipher attachments, which added a key of chi and psi wheel impulses to a message in ITA2 code.
+package lorenz
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/merenbach/goldbug/internal/normalize"
+	"github.com/merenbach/goldbug/internal/trace"
+	"github.com/merenbach/goldbug/pkg/ita2"
+)
+
+// A Limitation further controls the movement of the psi wheels on the SZ42.
+type Limitation uint8
+
+const (
+	// NoLimitation lets the 37-cam motor wheel alone decide whether the psi wheels move, as on the SZ40.
+	NoLimitation Limitation = iota
+
+	// Chi2 limits the motor by the second chi wheel one character back, as on the SZ42A.
+	Chi2
+
+	// Chi2Psi1 limits the motor by the sum of the second chi wheel and the first psi wheel one character back, as on the SZ42B.
+	Chi2Psi1
+)
+
+// Indices of the wheels, in order of a setting.
+const (
+	chi1  = 0
+	chi2  = 1
+	psi1  = 5
+	mu61  = 10
+	mu37  = 11
+	count = 12
+)
+
+// Names of the wheels, in order of a setting.
+var names = [count]string{"chi1", "chi2", "chi3", "chi4", "chi5", "psi1", "psi2", "psi3", "psi4", "psi5", "mu61", "mu37"}
+
+// Cipher implements a Lorenz cipher attachment.
+// Patterns default to SamplePatterns, and Positions hold the starting cam, counting from 1, of each wheel in the order chi 1 to 5, psi 1 to 5, then the 61-cam and 37-cam motor wheels, defaulting to 1 for all.
+// With a limitation in place, P5 adds the fifth impulse of the plaintext two characters back to the limitation.
+// Encipherment encodes the message in ITA2 and returns the enciphered codes in tape notation, while decipherment reverses the process.
+type Cipher struct {
+	Patterns   *Patterns
+	Positions  []int
+	Limitation Limitation
+	P5         bool
+	Normalizer normalize.Normalizer
+}
+
+// A machine holds the cams and current position of each wheel.
+type machine struct {
+	wheels     [][]bool
+	pos        [count]int
+	limitation Limitation
+	p5         bool
+
+	// Fifth plaintext impulse of the previous character, which is two back from the character after the next step.
+	back bool
+}
+
+func (c *Cipher) makemachine() (*machine, error) {
+	p := c.Patterns
+	if p == nil {
+		p = &SamplePatterns
+	}
+	ww, err := p.wheels()
+	if err != nil {
+		return nil, err
+	}
+	if c.Limitation > Chi2Psi1 {
+		return nil, fmt.Errorf("Unknown limitation %d", c.Limitation)
+	}
+
+	m := &machine{wheels: ww, limitation: c.Limitation, p5: c.P5}
+	if len(c.Positions) == 0 {
+		return m, nil
+	}
+	if len(c.Positions) != count {
+		return nil, fmt.Errorf("Positions must be given for all %d wheels", count)
+	}
+	for i, v := range c.Positions {
+		if v < 1 || v > len(ww[i]) {
+			return nil, fmt.Errorf("Position of %s must be from 1 to %d", names[i], len(ww[i]))
+		}
+		m.pos[i] = v - 1
+	}
+	return m, nil
+}
+
+// Cam under the reading head of a wheel.
+func (m *machine) cam(i int) bool {
+	return m.wheels[i][m.pos[i]]
+}
+
+// Advance a wheel by one cam.
+func (m *machine) advance(i int) {
+	m.pos[i] = (m.pos[i] + 1) % len(m.wheels[i])
+}
+
+// Key code from the sum of the chi and psi wheels, with the first wheel of each as the first impulse.
+func (m *machine) key() ita2.Code {
+	var k ita2.Code
+	for i := 0; i < 5; i++ {
+		k <<= 1
+		if m.cam(chi1+i) != m.cam(psi1+i) {
+			k |= 1
+		}
+	}
+	return k
+}
+
+// Step the wheels after a character with the given plaintext.
+// The chi wheels and the 61-cam motor always move, and the 61-cam motor moves the 37-cam motor when its cam is a cross.
+// The psi wheels move when the total motor is a cross, which it is when the 37-cam motor is a cross or the limitation is a dot.
+func (m *machine) step(plain ita2.Code) {
+	tm := m.cam(mu37)
+	if m.limitation != NoLimitation {
+		lim := m.cam(chi2)
+		if m.limitation == Chi2Psi1 {
+			lim = lim != m.cam(psi1)
+		}
+		if m.p5 {
+			lim = lim != m.back
+		}
+		tm = tm || !lim
+	}
+	m.back = plain&1 == 1
+
+	if tm {
+		for i := psi1; i < psi1+5; i++ {
+			m.advance(i)
+		}
+	}
+	if m.cam(mu61) {
+		m.advance(mu37)
+	}
+	m.advance(mu61)
+	for i := chi1; i < chi1+5; i++ {
+		m.advance(i)
+	}
+}
+
+// Add the key to a tape, recording an event for each code if a trace is given.
+// The plaintext is the input when enciphering and the output when deciphering.
+func (c *Cipher) add(cc []ita2.Code, decipher bool, tr *trace.Trace) ([]ita2.Code, error) {
+	m, err := c.makemachine()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]ita2.Code, len(cc))
+	for i, x := range cc {
+		k := m.key()
+		out[i] = x ^ k
+		tr.Add(trace.Event{Index: i, Input: rune(ita2.Tape[x]), Key: rune(ita2.Tape[k]), Row: (-1), Col: (-1), Output: rune(ita2.Tape[out[i]]), Action: trace.Transcoded})
+
+		plain := x
+		if decipher {
+			plain = out[i]
+		}
+		m.step(plain)
+	}
+	return out, nil
+}
+
+// Encipher a message, recording an event for each code if a trace is given.
+func (c *Cipher) encipher(s string, tr *trace.Trace) (string, error) {
+	cc, err := ita2.Encode(c.Normalizer.Normalize(s))
+	if err != nil {
+		return "", err
+	}
+	out, err := c.add(cc, false, tr)
+	if err != nil {
+		return "", err
+	}
+	return ita2.Format(out), nil
+}
+
+// Decipher a message, recording an event for each code if a trace is given.
+// Whitespace in the enciphered tape is ignored.
+func (c *Cipher) decipher(s string, tr *trace.Trace) (string, error) {
+	cc, err := ita2.Parse(strings.Join(strings.Fields(s), ""))
+	if err != nil {
+		return "", err
+	}
+	out, err := c.add(cc, true, tr)
+	if err != nil {
+		return "", err
+	}
+	return ita2.Decode(out), nil
+}
+
+// Encipher a message.
+func (c *Cipher) Encipher(s string) (string, error) {
+	return c.encipher(s, nil)
+}
+
+// Decipher a message.
+func (c *Cipher) Decipher(s string) (string, error) {
+	return c.decipher(s, nil)
+}
+
+// EncipherTrace enciphers a message and records how each code was produced.
+// Events describe codes of the encoded message in tape notation, with the key of the chi and psi wheels.
+func (c *Cipher) EncipherTrace(s string) (string, trace.Trace, error) {
+	var tr trace.Trace
+	out, err := c.encipher(s, &tr)
+	return out, tr, err
+}
+
+// DecipherTrace deciphers a message and records how each code was produced.
+func (c *Cipher) DecipherTrace(s string) (string, trace.Trace, error) {
+	var tr trace.Trace
+	out, err := c.decipher(s, &tr)
+	return out, tr, err
+}
+
+// Keystream returns the first n key codes in tape notation, as produced while enciphering a message that is all null codes.
+func (c *Cipher) Keystream(n int) (string, error) {
+	if n < 0 {
+		return "", errors.New("Length must not be negative")
+	}
+	out, err := c.add(make([]ita2.Code, n), false, nil)
+	if err != nil {
+		return "", err
+	}
+	return ita2.Format(out), nil
+}
+
+// Labels that may appear in ciphertext, which are the characters of tape notation.
+func (c *Cipher) Labels() (string, error) {
+	return ita2.Tape, nil
+}
+
+// TableauMatrix shows the cams of each wheel from its starting position, with the wheel name in the first column.
+func (c *Cipher) TableauMatrix() ([][]string, error) {
+	m, err := c.makemachine()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([][]string, count)
+	for i, w := range m.wheels {
+		out[i] = []string{names[i]}
+		for j := range w {
+			if w[(m.pos[i]+j)%len(w)] {
+				out[i] = append(out[i], "x")
+			} else {
+				out[i] = append(out[i], ".")
+			}
+		}
+	}
+	return out, nil
+}
+
+// Tableau shows the cams of each wheel from its starting position.
+func (c *Cipher) Tableau() (string, error) {
+	mm, err := c.TableauMatrix()
+	if err != nil {
+		return "", err
+	}
+
+	lines := make([]string, len(mm))
+	for i, row := range mm {
+		lines[i] = row[0] + " | " + strings.Join(row[1:], "")
+	}
+	return strings.Join(lines, "\n"), nil
+}
@@ -0,0 +1,227 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lorenz
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCipher_Encipher(t *testing.T) {
+	testdata, err := ioutil.ReadFile(filepath.Join("testdata", "cipher_encipher.json"))
+	if err != nil {
+		t.Fatal("Could not read testdata fixture:", err)
+	}
+
+	var tables []struct {
+		Cipher
+
+		Input  string
+		Output string
+	}
+	if err := json.Unmarshal(testdata, &tables); err != nil {
+		t.Fatal("Could not unmarshal testdata:", err)
+	}
+
+	for _, table := range tables {
+		if out, err := table.Encipher(table.Input); err != nil {
+			t.Error("Could not encipher:", err)
+		} else if out != table.Output {
+			t.Errorf("Expected %q to encipher to %q, but instead got %q", table.Input, table.Output, out)
+		}
+	}
+}
+
+func TestCipher_Decipher(t *testing.T) {
+	testdata, err := ioutil.ReadFile(filepath.Join("testdata", "cipher_decipher.json"))
+	if err != nil {
+		t.Fatal("Could not read testdata fixture:", err)
+	}
+
+	var tables []struct {
+		Cipher
+
+		Input  string
+		Output string
+	}
+	if err := json.Unmarshal(testdata, &tables); err != nil {
+		t.Fatal("Could not unmarshal testdata:", err)
+	}
+
+	for _, table := range tables {
+		if out, err := table.Decipher(table.Input); err != nil {
+			t.Error("Could not decipher:", err)
+		} else if out != table.Output {
+			t.Errorf("Expected %q to decipher to %q, but instead got %q", table.Input, table.Output, out)
+		}
+	}
+}
+
+// Patterns with every chi cam a dot and only the first cam of each psi wheel a cross, so that the key is 8 (all crosses) until the psi wheels move.
+func motorPatterns(mu37 string) *Patterns {
+	p := &Patterns{Mu: [2]string{strings.Repeat("x", 61), mu37}}
+	for i, n := range chiSizes {
+		p.Chi[i] = strings.Repeat(".", n)
+	}
+	for i, n := range psiSizes {
+		p.Psi[i] = "x" + strings.Repeat(".", n-1)
+	}
+	return p
+}
+
+func TestCipher_motor(t *testing.T) {
+	tables := []struct {
+		Cipher
+		expected string
+	}{
+		// The psi wheels stand still while the 37-cam motor shows dots.
+		{Cipher{Patterns: motorPatterns(strings.Repeat(".", 37))}, "88888"},
+		// The psi wheels move whenever the 37-cam motor shows a cross.
+		{Cipher{Patterns: motorPatterns("x" + strings.Repeat(".", 36))}, "8////"},
+		// The 37-cam motor moves only when the 61-cam motor shows a cross.
+		{Cipher{Patterns: motorPatterns(".x" + strings.Repeat(".", 35))}, "88///"},
+		// A limitation of dots overrides a 37-cam motor of dots.
+		{Cipher{Patterns: motorPatterns(strings.Repeat(".", 37)), Limitation: Chi2}, "8////"},
+	}
+
+	for _, table := range tables {
+		if out, err := table.Keystream(5); err != nil {
+			t.Error("Could not generate keystream:", err)
+		} else if out != table.expected {
+			t.Errorf("Expected keystream %q, but got %q", table.expected, out)
+		}
+	}
+}
+
+func TestCipher_Limitation(t *testing.T) {
+	tables := []struct {
+		Cipher
+		expected string
+	}{
+		{Cipher{}, "3UGOXB3GAD"},
+		{Cipher{Limitation: Chi2}, "3MEASUKAMM"},
+		{Cipher{Limitation: Chi2Psi1}, "3UEASUKAMM"},
+	}
+
+	for _, table := range tables {
+		if out, err := table.Keystream(10); err != nil {
+			t.Error("Could not generate keystream:", err)
+		} else if out != table.expected {
+			t.Errorf("Expected keystream %q, but got %q", table.expected, out)
+		}
+	}
+}
+
+func TestCipher_P5(t *testing.T) {
+	const msg = "ATTACK AT DAWN. 0600 HOURS"
+	c := Cipher{Positions: []int{41, 31, 29, 26, 23, 43, 47, 51, 53, 59, 61, 37}, Limitation: Chi2Psi1, P5: true}
+	out, err := c.Encipher(msg)
+	if err != nil {
+		t.Fatal("Could not encipher:", err)
+	}
+	if out != "8OQBPLXJRUBLGY43OY/QCMML/YRJ" {
+		t.Errorf("Expected %q, but got %q", "8OQBPLXJRUBLGY43OY/QCMML/YRJ", out)
+	}
+	if out, err := c.Decipher(out); err != nil {
+		t.Error("Could not decipher:", err)
+	} else if out != msg {
+		t.Errorf("Expected %q, but got %q", msg, out)
+	}
+}
+
+func TestReadPatterns(t *testing.T) {
+	f, err := os.Open(filepath.Join("testdata", "patterns.json"))
+	if err != nil {
+		t.Fatal("Could not open testdata fixture:", err)
+	}
+	defer f.Close()
+
+	p, err := ReadPatterns(f)
+	if err != nil {
+		t.Fatal("Could not read patterns:", err)
+	}
+	if *p != SamplePatterns {
+		t.Errorf("Expected sample patterns, but got %+v", p)
+	}
+
+	var b bytes.Buffer
+	if err := WritePatterns(&b, p); err != nil {
+		t.Fatal("Could not write patterns:", err)
+	}
+	if q, err := ReadPatterns(&b); err != nil {
+		t.Error("Could not read patterns:", err)
+	} else if *q != *p {
+		t.Errorf("Expected patterns to survive JSON, but got %+v", q)
+	}
+
+	for _, bad := range []string{
+		`{"chi": ["x"]}`,
+		`{"chi": ["` + strings.Repeat("y", 41) + `"]}`,
+		`[]`,
+	} {
+		if _, err := ReadPatterns(strings.NewReader(bad)); err == nil {
+			t.Errorf("Expected error reading patterns %s", bad)
+		}
+	}
+}
+
+func TestCipher_errors(t *testing.T) {
+	tables := []struct {
+		Cipher
+		Input string
+	}{
+		{Cipher{Positions: []int{1, 2, 3}}, "HELLO"},
+		{Cipher{Positions: []int{42, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1}}, "HELLO"},
+		{Cipher{Positions: []int{0, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1}}, "HELLO"},
+		{Cipher{Patterns: &Patterns{}}, "HELLO"},
+		{Cipher{Limitation: 3}, "HELLO"},
+		{Cipher{}, "100%"},
+	}
+
+	for _, table := range tables {
+		if _, err := table.Encipher(table.Input); err == nil {
+			t.Errorf("Expected error enciphering %q with %+v", table.Input, table.Cipher)
+		}
+	}
+}
+
+func ExampleCipher_Tableau() {
+	c := Cipher{Positions: []int{2, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 37}}
+	out, err := c.Tableau()
+	if err != nil {
+		fmt.Println("Error:", err)
+	}
+	fmt.Println(out)
+
+	// Output:
+	// chi1 | .xxxx.x..xxx..x...x..xxx..x...x.x.xx..xxx
+	// chi2 | .xx.xx...xx.xxx.x.x..x.x...xx..
+	// chi3 | x.xx.x.xxxx.....x.xx.x...x..x
+	// chi4 | x.xx.x..x..x.x.xxx....xx..
+	// chi5 | xx..x..x...x..xxx.x.xxx
+	// psi1 | x...x...x.xxxxx.xxx..xx.x.x..xxxxx.......xx
+	// psi2 | .xx.xx.xxx...x...xx.x.x.xx..x...x.xxxx..xxx....
+	// psi3 | xxx.xx...xxxxxx.xx.xxxx....x...x....x..x....xx.x.x.
+	// psi4 | .x.xx..x...xx.xx.x...xxx....xxx..xx...x..xxxxx..x.x.x
+	// psi5 | x.x..xxxx..xxxx.xx....xx.xxx.xxx..x..x.xx.x.....x....xxx...
+	// mu61 | xxx...xx.x..xxx..x..xx..xx.x.xxx..xxxx...xxx....x.....x...xxx
+	// mu37 | x...x..xxxxx..xx.x...x.xx.x..x..x.xxx
+}
@@ -0,0 +1,129 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lorenz
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Sizes of the wheels, in cams: five chi wheels, five psi wheels, and the two motor wheels.
+var (
+	chiSizes = [5]int{41, 31, 29, 26, 23}
+	psiSizes = [5]int{43, 47, 51, 53, 59}
+	muSizes  = [2]int{61, 37}
+)
+
+// Patterns hold the cam settings of each wheel, with x (or 1) for a raised cam, or cross, and . (or 0) for a lowered cam, or dot.
+// Mu holds the 61-cam motor wheel followed by the 37-cam motor wheel.
+type Patterns struct {
+	Chi [5]string `json:"chi"`
+	Psi [5]string `json:"psi"`
+	Mu  [2]string `json:"mu"`
+}
+
+// SamplePatterns are arbitrary cam patterns, balanced between crosses and dots, for demonstration and testing.
+// They are not historical settings.
+var SamplePatterns = Patterns{
+	Chi: [5]string{
+		"x.xxxx.x..xxx..x...x..xxx..x...x.x.xx..xx",
+		".xx.xx...xx.xxx.x.x..x.x...xx..",
+		"x.xx.x.xxxx.....x.xx.x...x..x",
+		"x.xx.x..x..x.x.xxx....xx..",
+		"xx..x..x...x..xxx.x.xxx",
+	},
+	Psi: [5]string{
+		"x...x...x.xxxxx.xxx..xx.x.x..xxxxx.......xx",
+		".xx.xx.xxx...x...xx.x.x.xx..x...x.xxxx..xxx....",
+		"xxx.xx...xxxxxx.xx.xxxx....x...x....x..x....xx.x.x.",
+		".x.xx..x...xx.xx.x...xxx....xxx..xx...x..xxxxx..x.x.x",
+		"x.x..xxxx..xxxx.xx....xx.xxx.xxx..x..x.xx.x.....x....xxx...",
+	},
+	Mu: [2]string{
+		"xxx...xx.x..xxx..x..xx..xx.x.xxx..xxxx...xxx....x.....x...xxx",
+		"...x..xxxxx..xx.x...x.xx.x..x..x.xxxx",
+	},
+}
+
+// Cams parses a pattern for a wheel of the given size.
+func cams(s string, size int, name string) ([]bool, error) {
+	if len(s) != size {
+		return nil, fmt.Errorf("Wheel %s must have %d cams, not %d", name, size, len(s))
+	}
+	out := make([]bool, size)
+	for i, r := range s {
+		switch r {
+		case 'x', 'X', '1':
+			out[i] = true
+		case '.', '0':
+		default:
+			return nil, fmt.Errorf("Wheel %s has invalid cam %q", name, r)
+		}
+	}
+	return out, nil
+}
+
+// Wheels parses the patterns into the cams of each wheel, in the order chi 1 to 5, psi 1 to 5, then the 61-cam and 37-cam motor wheels.
+func (p *Patterns) wheels() ([][]bool, error) {
+	var out [][]bool
+	for i, s := range p.Chi {
+		w, err := cams(s, chiSizes[i], fmt.Sprintf("chi %d", i+1))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, w)
+	}
+	for i, s := range p.Psi {
+		w, err := cams(s, psiSizes[i], fmt.Sprintf("psi %d", i+1))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, w)
+	}
+	for i, s := range p.Mu {
+		w, err := cams(s, muSizes[i], fmt.Sprintf("mu %d", muSizes[i]))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, w)
+	}
+	return out, nil
+}
+
+// Validate the patterns.
+func (p *Patterns) Validate() error {
+	_, err := p.wheels()
+	return err
+}
+
+// ReadPatterns decodes and validates patterns from JSON.
+func ReadPatterns(r io.Reader) (*Patterns, error) {
+	var p Patterns
+	if err := json.NewDecoder(r).Decode(&p); err != nil {
+		return nil, err
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// WritePatterns encodes patterns as indented JSON.
+func WritePatterns(w io.Writer, p *Patterns) error {
+	e := json.NewEncoder(w)
+	e.SetIndent("", "    ")
+	return e.Encode(p)
+}
@@ -0,0 +1,94 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package m209
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/merenbach/goldbug/internal/format"
+)
+
+// ErrCheck is returned when a key list fails its letter check.
+var ErrCheck = errors.New("Letter check failed")
+
+// A KeyList holds the internal settings of an M-209 for a period, as issued to operators.
+// Indicator identifies the key list, and LetterCheck, if given, is the result of enciphering 26 letters A from positions AAAAAA.
+type KeyList struct {
+	Indicator   string   `json:"indicator"`
+	Lugs        string   `json:"lugs"`
+	Pins        []string `json:"pins"`
+	LetterCheck string   `json:"letterCheck,omitempty"`
+}
+
+// ReadKeyLists decodes key lists from JSON holding either a single key list or an array of them.
+func ReadKeyLists(r io.Reader) ([]KeyList, error) {
+	bb, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []KeyList
+	if bb = bytes.TrimSpace(bb); len(bb) > 0 && bb[0] == '{' {
+		var k KeyList
+		if err := json.Unmarshal(bb, &k); err != nil {
+			return nil, err
+		}
+		return append(out, k), nil
+	}
+	if err := json.Unmarshal(bb, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Cipher creates a cipher with the settings of this key list and the given key wheel positions.
+func (k *KeyList) Cipher(positions string) *Cipher {
+	return &Cipher{
+		Pins:      k.Pins,
+		Lugs:      k.Lugs,
+		Positions: positions,
+	}
+}
+
+// Check compares the letter check of this key list, ignoring spaces, with that of the machine it describes.
+func (k *KeyList) Check() error {
+	out, err := k.Cipher("").LetterCheck()
+	if err != nil {
+		return err
+	}
+	if strings.Join(strings.Fields(out), "") != strings.Join(strings.Fields(strings.ToUpper(k.LetterCheck)), "") {
+		return fmt.Errorf("%w for key list %q: expected %q, but got %q", ErrCheck, k.Indicator, k.LetterCheck, out)
+	}
+	return nil
+}
+
+// LetterCheck enciphers 26 letters A from positions AAAAAA, with the result in groups of five.
+func (c *Cipher) LetterCheck() (string, error) {
+	d := *c
+	d.Positions = ""
+	out, err := d.Encipher(strings.Repeat("A", len(Alphabet)))
+	if err != nil {
+		return "", err
+	}
+
+	f := format.Formatter{Alphabet: Alphabet, GroupSize: 5}
+	return f.Format(out), nil
+}
@@ -0,0 +1,280 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package m209 simulates the Hagelin M-209 pin-and-lug cipher machine.
+package m209
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/merenbach/goldbug/internal/normalize"
+	"github.com/merenbach/goldbug/internal/trace"
+	"github.com/merenbach/goldbug/pkg/beaufort"
+)
+
+// Alphabet of the M-209 print wheel.
+const Alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// Letters on each of the six key wheels, whose lengths are relatively prime.
+var wheels = [...]string{
+	"ABCDEFGHIJKLMNOPQRSTUVWXYZ",
+	"ABCDEFGHIJKLMNOPQRSTUVXYZ",
+	"ABCDEFGHIJKLMNOPQRSTUVX",
+	"ABCDEFGHIJKLMNOPQRSTU",
+	"ABCDEFGHIJKLMNOPQRS",
+	"ABCDEFGHIJKLMNOPQ",
+}
+
+// Offsets from the window letter of each key wheel to the pin that meets the lug cage.
+var guides = [...]int{15, 14, 13, 12, 11, 10}
+
+// Bars in the lug cage.
+const bars = 27
+
+// Cipher implements an M-209.
+// Pins holds, for each of the six key wheels from left to right, the letters of its effective pins.
+// Lugs describes the lug cage as space-separated bars, each naming the two wheels (1 to 6, or 0 for none) against which its lugs are set, as in "1-0 2-5", with a suffix such as "*8" to repeat a bar.
+// Positions holds the window letter of each key wheel, and defaults to "AAAAAA".
+// As on the machine, encipherment turns spaces into Z and decipherment turns Z into spaces.
+type Cipher struct {
+	Pins       []string
+	Lugs       string
+	Positions  string
+	Strict     bool
+	Normalizer normalize.Normalizer
+}
+
+// A machine holds the pins of the key wheels, the lug cage and the current wheel positions.
+type machine struct {
+	pins [6][]bool
+	lugs [][2]int
+	pos  [6]int
+}
+
+// ParseLugs parses a lug cage description into bars, each with the zero-based wheels against which its lugs are set, or (-1) for none.
+func parseLugs(s string) ([][2]int, error) {
+	var out [][2]int
+	for _, f := range strings.Fields(s) {
+		n := 1
+		if i := strings.IndexByte(f, '*'); i != (-1) {
+			v, err := strconv.Atoi(f[i+1:])
+			if err != nil || v < 1 {
+				return nil, fmt.Errorf("Invalid repetition in lug setting %q", f)
+			}
+			f, n = f[:i], v
+		}
+
+		ww := strings.Split(f, "-")
+		if len(ww) != 2 {
+			return nil, fmt.Errorf("Lug setting %q must name two wheels", f)
+		}
+		var bar [2]int
+		for i, w := range ww {
+			v, err := strconv.Atoi(w)
+			if err != nil || v < 0 || v > len(wheels) {
+				return nil, fmt.Errorf("Lug setting %q must name wheels from 0 to %d", f, len(wheels))
+			}
+			bar[i] = v - 1
+		}
+		if bar[0] == bar[1] && bar[0] != (-1) {
+			return nil, fmt.Errorf("Lug setting %q sets both lugs against the same wheel", f)
+		}
+
+		for ; n > 0; n-- {
+			out = append(out, bar)
+		}
+	}
+	if len(out) > bars {
+		return nil, fmt.Errorf("Lug cage has only %d bars", bars)
+	}
+	return out, nil
+}
+
+// Machine creates a machine with the settings of this cipher.
+func (c *Cipher) machine() (*machine, error) {
+	if len(c.Pins) != 0 && len(c.Pins) != len(wheels) {
+		return nil, fmt.Errorf("Pins must be given for all %d key wheels", len(wheels))
+	}
+	lugs, err := parseLugs(c.Lugs)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &machine{lugs: lugs}
+	for i, w := range wheels {
+		m.pins[i] = make([]bool, len(w))
+		if len(c.Pins) == 0 {
+			continue
+		}
+		for _, r := range strings.ToUpper(c.Pins[i]) {
+			j := strings.IndexRune(w, r)
+			if j == (-1) {
+				return nil, fmt.Errorf("Key wheel %d has no pin %q", i+1, r)
+			}
+			m.pins[i][j] = true
+		}
+	}
+
+	positions := strings.ToUpper(c.Positions)
+	if positions == "" {
+		positions = strings.Repeat("A", len(wheels))
+	}
+	if len([]rune(positions)) != len(wheels) {
+		return nil, fmt.Errorf("Positions must have one letter for each of %d key wheels", len(wheels))
+	}
+	for i, r := range []rune(positions) {
+		j := strings.IndexRune(wheels[i], r)
+		if j == (-1) {
+			return nil, fmt.Errorf("Key wheel %d has no letter %q", i+1, r)
+		}
+		m.pos[i] = j
+	}
+	return m, nil
+}
+
+// Shift counts the bars whose lugs meet an effective pin, then advances every key wheel.
+func (m *machine) shift() int {
+	var active [6]bool
+	for i, pp := range m.pins {
+		active[i] = pp[(m.pos[i]+guides[i])%len(pp)]
+	}
+
+	n := 0
+	for _, bar := range m.lugs {
+		for _, w := range bar {
+			if w != (-1) && active[w] {
+				n++
+				break
+			}
+		}
+	}
+
+	for i, pp := range m.pins {
+		m.pos[i] = (m.pos[i] + 1) % len(pp)
+	}
+	return n
+}
+
+// Key stream for a number of letters.
+// The machine enciphers each letter p as Z - p + shift, which is a Beaufort encipherment with key letter shift - 1.
+func (m *machine) key(n int) string {
+	out := make([]byte, n)
+	for i := range out {
+		out[i] = Alphabet[(m.shift()+len(Alphabet)-1)%len(Alphabet)]
+	}
+	return string(out)
+}
+
+// Prepare a message for the print wheel, returning it along with a Beaufort cipher keyed for its letters.
+func (c *Cipher) prepare(s string, encipher bool) (string, *beaufort.Cipher, error) {
+	m, err := c.machine()
+	if err != nil {
+		return "", nil, err
+	}
+
+	present := func(r rune) bool {
+		return strings.ContainsRune(Alphabet, r)
+	}
+
+	rr := []rune(c.Normalizer.Normalize(s))
+	n := 0
+	for i, r := range rr {
+		if encipher && r == ' ' {
+			rr[i] = 'Z'
+		}
+		if present(c.Normalizer.Fold(rr[i], present)) {
+			n++
+		}
+	}
+
+	b := &beaufort.Cipher{
+		Alphabet:   Alphabet,
+		Key:        m.key(n),
+		Strict:     c.Strict,
+		Normalizer: normalize.Normalizer{Case: c.Normalizer.Case},
+	}
+	if n == 0 {
+		b.Key = "A"
+	}
+	return string(rr), b, nil
+}
+
+// Spaces restores spaces from the letter Z in a deciphered message.
+func spaces(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == 'Z' || r == 'z' {
+			return ' '
+		}
+		return r
+	}, s)
+}
+
+// Encipher a message.
+func (c *Cipher) Encipher(s string) (string, error) {
+	s, b, err := c.prepare(s, true)
+	if err != nil {
+		return "", err
+	}
+	return b.Encipher(s)
+}
+
+// Decipher a message.
+func (c *Cipher) Decipher(s string) (string, error) {
+	s, b, err := c.prepare(s, false)
+	if err != nil {
+		return "", err
+	}
+	out, err := b.Encipher(s)
+	return spaces(out), err
+}
+
+// EncipherTrace enciphers a message and records how each rune was produced.
+// The key of each event is the Beaufort key letter for the shift of the lug cage.
+func (c *Cipher) EncipherTrace(s string) (string, trace.Trace, error) {
+	s, b, err := c.prepare(s, true)
+	if err != nil {
+		return "", nil, err
+	}
+	return b.EncipherTrace(s)
+}
+
+// DecipherTrace deciphers a message and records how each rune was produced.
+func (c *Cipher) DecipherTrace(s string) (string, trace.Trace, error) {
+	s, b, err := c.prepare(s, false)
+	if err != nil {
+		return "", nil, err
+	}
+	out, tr, err := b.EncipherTrace(s)
+	return spaces(out), tr, err
+}
+
+// Tableau for encipherment and decipherment, which is the Beaufort tableau keyed by the shift of the lug cage less one.
+func (c *Cipher) Tableau() (string, error) {
+	if _, err := c.machine(); err != nil {
+		return "", err
+	}
+	b := beaufort.Cipher{Alphabet: Alphabet}
+	return b.Tableau()
+}
+
+// TableauMatrix for encipherment and decipherment.
+func (c *Cipher) TableauMatrix() ([][]string, error) {
+	if _, err := c.machine(); err != nil {
+		return nil, err
+	}
+	b := beaufort.Cipher{Alphabet: Alphabet}
+	return b.TableauMatrix()
+}
@@ -0,0 +1,174 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package m209
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCipher_Encipher(t *testing.T) {
+	testdata, err := ioutil.ReadFile(filepath.Join("testdata", "cipher_encipher.json"))
+	if err != nil {
+		t.Fatal("Could not read testdata fixture:", err)
+	}
+
+	var tables []struct {
+		Cipher
+
+		Input  string
+		Output string
+	}
+	if err := json.Unmarshal(testdata, &tables); err != nil {
+		t.Fatal("Could not unmarshal testdata:", err)
+	}
+
+	for _, table := range tables {
+		if out, err := table.Encipher(table.Input); err != nil {
+			t.Error("Could not encipher:", err)
+		} else if out != table.Output {
+			t.Errorf("Expected %q to encipher to %q, but instead got %q", table.Input, table.Output, out)
+		}
+	}
+}
+
+func TestCipher_Decipher(t *testing.T) {
+	testdata, err := ioutil.ReadFile(filepath.Join("testdata", "cipher_decipher.json"))
+	if err != nil {
+		t.Fatal("Could not read testdata fixture:", err)
+	}
+
+	var tables []struct {
+		Cipher
+
+		Input  string
+		Output string
+	}
+	if err := json.Unmarshal(testdata, &tables); err != nil {
+		t.Fatal("Could not unmarshal testdata:", err)
+	}
+
+	for _, table := range tables {
+		if out, err := table.Decipher(table.Input); err != nil {
+			t.Error("Could not decipher:", err)
+		} else if out != table.Output {
+			t.Errorf("Expected %q to decipher to %q, but instead got %q", table.Input, table.Output, out)
+		}
+	}
+}
+
+func TestCipher_shift(t *testing.T) {
+	tables := []struct {
+		Cipher
+		Input  string
+		Output string
+	}{
+		// No bars move, so each letter p becomes Z - p.
+		{Cipher{Lugs: "1-0*27"}, "AMZ", "ZNA"},
+		// All 27 bars move, so each letter p becomes Z - p + 27, or A - p.
+		{Cipher{Pins: []string{"ABCDEFGHIJKLMNOPQRSTUVWXYZ", "", "", "", "", ""}, Lugs: "1-0*27"}, "AMZ", "AOB"},
+		// A bar with lugs against two effective pins moves only once, so two bars make a shift of 2.
+		{Cipher{Pins: []string{"ABCDEFGHIJKLMNOPQRSTUVWXYZ", "ABCDEFGHIJKLMNOPQRSTUVXYZ", "", "", "", ""}, Lugs: "1-2 1-0"}, "AMZ", "BPC"},
+	}
+
+	for _, table := range tables {
+		if out, err := table.Encipher(table.Input); err != nil {
+			t.Error("Could not encipher:", err)
+		} else if out != table.Output {
+			t.Errorf("Expected %q to encipher to %q, but instead got %q", table.Input, table.Output, out)
+		}
+	}
+}
+
+func TestCipher_errors(t *testing.T) {
+	tables := []Cipher{
+		{Pins: []string{"A"}},
+		{Pins: []string{"W", "W", "", "", "", ""}},
+		{Lugs: "1-7"},
+		{Lugs: "1-1"},
+		{Lugs: "1"},
+		{Lugs: "1-0*0"},
+		{Lugs: "1-0*28"},
+		{Positions: "AAAAA"},
+		{Positions: "AAAAAZ"},
+	}
+
+	for _, table := range tables {
+		if _, err := table.Encipher("HELLO"); err == nil {
+			t.Errorf("Expected error enciphering with %+v", table)
+		}
+	}
+}
+
+func TestReadKeyLists(t *testing.T) {
+	f, err := os.Open(filepath.Join("testdata", "keylists.json"))
+	if err != nil {
+		t.Fatal("Could not open testdata fixture:", err)
+	}
+	defer f.Close()
+
+	kk, err := ReadKeyLists(f)
+	if err != nil {
+		t.Fatal("Could not read key lists:", err)
+	}
+	if len(kk) != 2 {
+		t.Fatalf("Expected 2 key lists, but got %d", len(kk))
+	}
+	for _, k := range kk {
+		if err := k.Check(); err != nil {
+			t.Error("Unexpected error:", err)
+		}
+	}
+
+	// The letter check above is published with the key list; this message is only a regression vector, checked against an independent implementation
+	if out, err := kk[0].Cipher("GAQLMB").Decipher("ZSOARYVCBNBYGV"); err != nil {
+		t.Error("Could not decipher:", err)
+	} else if out != "ATTACK AT DAWN" {
+		t.Errorf("Expected %q, but got %q", "ATTACK AT DAWN", out)
+	}
+
+	kk, err = ReadKeyLists(strings.NewReader(`{"indicator": "XX", "lugs": "1-0", "letterCheck": "ABCDE"}`))
+	if err != nil {
+		t.Fatal("Could not read key list:", err)
+	}
+	if len(kk) != 1 {
+		t.Fatalf("Expected 1 key list, but got %d", len(kk))
+	}
+	if err := kk[0].Check(); !errors.Is(err, ErrCheck) {
+		t.Errorf("Expected letter check to fail, but got %v", err)
+	}
+}
+
+func ExampleCipher_LetterCheck() {
+	// The key list from the technical manual, with its published letter check
+	c := Cipher{
+		Pins: []string{"ABDHIKMNSTVW", "ADEGJKLORSUX", "ABGHJLMNRSTUX", "CEFHIMNPSTU", "BDEFHIMNPS", "ABDHKNOQ"},
+		Lugs: "3-6 0-6 1-6 1-5 4-5 0-4*4 2-0*10 2-5*2 0-5*6",
+	}
+	out, err := c.LetterCheck()
+	if err != nil {
+		fmt.Println("Error:", err)
+	}
+	fmt.Println(out)
+
+	// Output:
+	// TNJUW AUQTK CZKNU TOTBC WARMI O
+}
@@ -0,0 +1,263 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package nihilist implements the Nihilist substitution and Nihilist transposition ciphers.
+package nihilist
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/merenbach/goldbug/internal/normalize"
+	"github.com/merenbach/goldbug/internal/stringutil"
+	"github.com/merenbach/goldbug/internal/trace"
+	"github.com/merenbach/goldbug/pkg/polybius"
+)
+
+// Cipher implements the Nihilist substitution cipher.
+// Each rune of the message and of the key is written as the row and column of its place in a Polybius square, read as a two-digit number, and the numbers are added.
+// Keyword, if set, mixes the square.
+// Sums are written as numbers separated by spaces, or, if Groups is set, as two digits apiece with any hundreds dropped, ready to be grouped.
+// Runes outside the square have no number, so they are dropped.
+type Cipher struct {
+	Alphabet   string
+	Keyword    string
+	Key        string
+	Groups     bool
+	Normalizer normalize.Normalizer
+}
+
+// Square for this cipher, with the alphabet mixed by the keyword.
+func (c *Cipher) square() (*polybius.Cipher, error) {
+	a, merge := c.Alphabet, ""
+	if a == "" || a == polybius.Alphabet {
+		a, merge = polybius.Alphabet, polybius.Merge
+	}
+
+	// The merge rule applies to the keyword as well, so that a J keys the square as an I
+	mm := []rune(merge)
+	keyword := strings.Map(func(r rune) rune {
+		for i := 0; i+1 < len(mm); i += 2 {
+			if r == mm[i] {
+				return mm[i+1]
+			}
+		}
+		return r
+	}, c.Keyword)
+
+	mixed := stringutil.Deduplicate(keyword + a)
+	if len([]rune(mixed)) != len([]rune(stringutil.Deduplicate(a))) {
+		return nil, fmt.Errorf("Keyword %q has characters outside the alphabet", c.Keyword)
+	}
+	return &polybius.Cipher{
+		Alphabet:   mixed,
+		Merge:      merge,
+		Strict:     true,
+		Normalizer: c.Normalizer,
+	}, nil
+}
+
+// A number is the value of a rune's coordinates in the square.
+type number struct {
+	index int
+	input rune
+	value int
+}
+
+// Numbers for each rune of a message found in the square, along with a trace in which other runes are skipped.
+func numbers(sq *polybius.Cipher, s string) ([]number, trace.Trace, error) {
+	_, tr, err := sq.EncipherTrace(s)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var out []number
+	for _, e := range tr {
+		if e.Action == trace.Transcoded {
+			out = append(out, number{index: e.Index, input: e.Input, value: 10*(e.Row+1) + e.Col + 1})
+		}
+	}
+	return out, tr, nil
+}
+
+// Key numbers for this cipher.
+func (c *Cipher) key(sq *polybius.Cipher) ([]number, error) {
+	kk, _, err := numbers(sq, c.Key)
+	if err != nil {
+		return nil, err
+	}
+	if len(kk) == 0 {
+		return nil, errors.New("Key must have at least one character in the square")
+	}
+	return kk, nil
+}
+
+// CheckGroups ensures that sums written in groups can be told apart once their hundreds are dropped, which needs a square no larger than 5 by 5.
+func (c *Cipher) checkGroups(sq *polybius.Cipher) error {
+	if !c.Groups {
+		return nil
+	}
+	labels, err := sq.Labels()
+	if err != nil {
+		return err
+	}
+	if side := len(labels) / 2; side > 5 {
+		return fmt.Errorf("Groups need a square of at most 5 by 5, but this one is %d by %d", side, side)
+	}
+	return nil
+}
+
+// Encipher a message.
+func (c *Cipher) Encipher(s string) (string, error) {
+	out, _, err := c.EncipherTrace(s)
+	return out, err
+}
+
+// EncipherTrace enciphers a message and records how each rune was produced.
+// The output of each event is the first digit of its sum.
+func (c *Cipher) EncipherTrace(s string) (string, trace.Trace, error) {
+	sq, err := c.square()
+	if err != nil {
+		return "", nil, err
+	}
+	if err := c.checkGroups(sq); err != nil {
+		return "", nil, err
+	}
+	kk, err := c.key(sq)
+	if err != nil {
+		return "", nil, err
+	}
+	nn, tr, err := numbers(sq, s)
+	if err != nil {
+		return "", nil, err
+	}
+
+	out := make([]string, len(nn))
+	for i, n := range nn {
+		k := kk[i%len(kk)]
+		sum := n.value + k.value
+		if c.Groups {
+			out[i] = fmt.Sprintf("%02d", sum%100)
+		} else {
+			out[i] = strconv.Itoa(sum)
+		}
+
+		e := &tr[n.index]
+		e.Key, e.Output = k.input, rune(out[i][0])
+	}
+
+	if c.Groups {
+		return strings.Join(out, ""), tr, nil
+	}
+	return strings.Join(out, " "), tr, nil
+}
+
+// Decipher a message.
+// Without Groups, numbers may be separated by any whitespace; with Groups, runes other than digits are ignored.
+func (c *Cipher) Decipher(s string) (string, error) {
+	out, _, err := c.DecipherTrace(s)
+	return out, err
+}
+
+// DecipherTrace deciphers a message and records how each rune was produced.
+// Each number yields a single event, with its first digit as the input.
+func (c *Cipher) DecipherTrace(s string) (string, trace.Trace, error) {
+	sq, err := c.square()
+	if err != nil {
+		return "", nil, err
+	}
+	if err := c.checkGroups(sq); err != nil {
+		return "", nil, err
+	}
+	kk, err := c.key(sq)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var fields []string
+	if c.Groups {
+		dd := strings.Map(func(r rune) rune {
+			if r < '0' || r > '9' {
+				return -1
+			}
+			return r
+		}, s)
+		if len(dd)%2 != 0 {
+			return "", nil, errors.New("Groups must have an even number of digits")
+		}
+		for i := 0; i < len(dd); i += 2 {
+			fields = append(fields, dd[i:i+2])
+		}
+	} else {
+		fields = strings.Fields(s)
+	}
+
+	// Differences are the coordinates of each rune, written as the row and column labels of the square
+	var b strings.Builder
+	for i, f := range fields {
+		sum, err := strconv.Atoi(f)
+		if err != nil {
+			return "", nil, fmt.Errorf("Invalid number %q", f)
+		}
+		n := sum - kk[i%len(kk)].value
+		if c.Groups && n < 11 {
+			n += 100
+		}
+		if n < 11 || n > 99 {
+			return "", nil, fmt.Errorf("Number %q does not decipher to a place in the square", f)
+		}
+		b.WriteString(strconv.Itoa(n))
+	}
+
+	out, sqtr, err := sq.DecipherTrace(b.String())
+	if err != nil {
+		return "", nil, err
+	}
+	for _, e := range sqtr {
+		if e.Action != trace.Transcoded || len(sqtr) != len(fields) {
+			return "", nil, errors.New("Message has numbers that do not decipher to places in the square")
+		}
+	}
+
+	tr := make(trace.Trace, len(sqtr))
+	for i, e := range sqtr {
+		tr[i] = trace.Event{Index: i, Input: rune(fields[i][0]), Key: kk[i%len(kk)].input, Row: e.Row, Col: e.Col, Output: e.Output, Action: trace.Transcoded}
+	}
+	return out, tr, nil
+}
+
+// Labels that may appear in ciphertext.
+func (c *Cipher) Labels() (string, error) {
+	return "0123456789", nil
+}
+
+// Tableau showing the square.
+func (c *Cipher) Tableau() (string, error) {
+	sq, err := c.square()
+	if err != nil {
+		return "", err
+	}
+	return sq.Tableau()
+}
+
+// TableauMatrix showing the square, with row labels in the first column and column labels in the first row.
+func (c *Cipher) TableauMatrix() ([][]string, error) {
+	sq, err := c.square()
+	if err != nil {
+		return nil, err
+	}
+	return sq.TableauMatrix()
+}
@@ -0,0 +1,153 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nihilist
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/merenbach/goldbug/internal/trace"
+)
+
+func TestCipher_Encipher(t *testing.T) {
+	testdata, err := ioutil.ReadFile(filepath.Join("testdata", "cipher_encipher.json"))
+	if err != nil {
+		t.Fatal("Could not read testdata fixture:", err)
+	}
+
+	var tables []struct {
+		Cipher
+
+		Input  string
+		Output string
+	}
+	if err := json.Unmarshal(testdata, &tables); err != nil {
+		t.Fatal("Could not unmarshal testdata:", err)
+	}
+
+	for _, table := range tables {
+		if out, err := table.Encipher(table.Input); err != nil {
+			t.Error("Could not encipher:", err)
+		} else if out != table.Output {
+			t.Errorf("Expected %q to encipher to %q, but instead got %q", table.Input, table.Output, out)
+		}
+	}
+}
+
+func TestCipher_Decipher(t *testing.T) {
+	testdata, err := ioutil.ReadFile(filepath.Join("testdata", "cipher_decipher.json"))
+	if err != nil {
+		t.Fatal("Could not read testdata fixture:", err)
+	}
+
+	var tables []struct {
+		Cipher
+
+		Input  string
+		Output string
+	}
+	if err := json.Unmarshal(testdata, &tables); err != nil {
+		t.Fatal("Could not unmarshal testdata:", err)
+	}
+
+	for _, table := range tables {
+		if out, err := table.Decipher(table.Input); err != nil {
+			t.Error("Could not decipher:", err)
+		} else if out != table.Output {
+			t.Errorf("Expected %q to decipher to %q, but instead got %q", table.Input, table.Output, out)
+		}
+	}
+}
+
+func TestCipher_errors(t *testing.T) {
+	tables := []struct {
+		Cipher
+		Input string
+	}{
+		{Cipher{Key: ""}, "HELLO"},
+		{Cipher{Key: "123"}, "HELLO"},
+		{Cipher{Key: "KEY", Keyword: "KEY!"}, "HELLO"},
+		{Cipher{Key: "KEY", Alphabet: "ABCA"}, "HELLO"},
+		{Cipher{Key: "KEY", Alphabet: "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789", Groups: true}, "HELLO"},
+	}
+	for _, table := range tables {
+		if _, err := table.Encipher(table.Input); err == nil {
+			t.Errorf("Expected error enciphering %q with %+v", table.Input, table.Cipher)
+		}
+	}
+
+	decipherTables := []struct {
+		Cipher
+		Input string
+	}{
+		{Cipher{Key: "KEY"}, "40 A1"},
+		{Cipher{Key: "KEY"}, "40 75"},
+		{Cipher{Key: "KEY"}, "40 300"},
+		{Cipher{Key: "KEY", Groups: true}, "402"},
+	}
+	for _, table := range decipherTables {
+		if _, err := table.Decipher(table.Input); err == nil {
+			t.Errorf("Expected error deciphering %q with %+v", table.Input, table.Cipher)
+		}
+	}
+}
+
+func TestCipher_EncipherTrace(t *testing.T) {
+	c := Cipher{Keyword: "ZEBRAS", Key: "RUSSIAN"}
+	_, tr, err := c.EncipherTrace("DY N")
+	if err != nil {
+		t.Fatal("Could not encipher:", err)
+	}
+
+	expected := []struct {
+		key    rune
+		output rune
+		action trace.Action
+	}{
+		{'R', '3', trace.Transcoded},
+		{'U', '1', trace.Transcoded},
+		{0, 0, trace.Skipped},
+		{'S', '6', trace.Transcoded},
+	}
+	if len(tr) != len(expected) {
+		t.Fatalf("Expected %d events, but got %d", len(expected), len(tr))
+	}
+	for i, e := range expected {
+		if tr[i].Key != e.key || tr[i].Output != e.output || tr[i].Action != e.action {
+			t.Errorf("Event %d: expected key %q, output %q and action %v, but got %+v", i, e.key, e.output, e.action, tr[i])
+		}
+	}
+}
+
+func ExampleCipher_Tableau() {
+	c := Cipher{Keyword: "ZEBRAS"}
+	out, err := c.Tableau()
+	if err != nil {
+		fmt.Println("Error:", err)
+	}
+	fmt.Println(out)
+
+	// Output:
+	//     1 2 3 4 5
+	//   +----------
+	// 1 | Z E B R A
+	// 2 | S C D F G
+	// 3 | H I K L M
+	// 4 | N O P Q T
+	// 5 | U V W X Y
+}
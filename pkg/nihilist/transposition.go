@@ -0,0 +1,266 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nihilist
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/merenbach/goldbug/internal/grid"
+	"github.com/merenbach/goldbug/internal/trace"
+)
+
+// Transposition implements the Nihilist transposition cipher.
+// The message is written by rows into squares with a side as long as the key, and the rows and columns of each square are both rearranged into the alphabetical order of the key's runes.
+// The squares are read off by rows, or by columns if ByColumn is set.
+// Padding, if set, is appended as nulls upon encipherment to fill the final square.
+type Transposition struct {
+	Key      string
+	ByColumn bool
+	Padding  rune
+}
+
+// Order gives the place of each row and column once rearranged, with ties broken by position.
+func (c *Transposition) order() ([]int, error) {
+	kk := []rune(c.Key)
+	if len(kk) == 0 {
+		return nil, errors.New("Key must not be empty")
+	}
+
+	ii := make([]int, len(kk))
+	for i := range ii {
+		ii[i] = i
+	}
+	sort.SliceStable(ii, func(i, j int) bool {
+		return kk[ii[i]] < kk[ii[j]]
+	})
+
+	out := make([]int, len(kk))
+	for pos, i := range ii {
+		out[i] = pos
+	}
+	return out, nil
+}
+
+// Makegrid creates a grid of n cells written by rows into a square of the given side.
+func makegrid(n int, side int) grid.Grid {
+	g := make(grid.Grid, n)
+	for i := range g {
+		g[i].Row = i / side
+		g[i].Col = i % side
+	}
+	return g
+}
+
+// Blocks splits a message into runs of runes that each fill a square, except perhaps the last.
+func blocks(s string, side int) []string {
+	rr := []rune(s)
+	var out []string
+	for len(rr) > side*side {
+		out = append(out, string(rr[:side*side]))
+		rr = rr[side*side:]
+	}
+	return append(out, string(rr))
+}
+
+// Pad a message with nulls to fill the final square.
+func (c *Transposition) pad(s string, side int) string {
+	n := len([]rune(s))
+	if c.Padding == 0 || n%(side*side) == 0 {
+		return s
+	}
+	return s + strings.Repeat(string(c.Padding), side*side-n%(side*side))
+}
+
+// Inverse of an order, giving the original row or column for each place.
+func inverse(order []int) []int {
+	out := make([]int, len(order))
+	for i, pos := range order {
+		out[pos] = i
+	}
+	return out
+}
+
+// Encipher a message.
+func (c *Transposition) Encipher(s string) (string, error) {
+	out, _, err := c.EncipherTrace(s)
+	return out, err
+}
+
+// Decipher a message.
+func (c *Transposition) Decipher(s string) (string, error) {
+	out, _, err := c.DecipherTrace(s)
+	return out, err
+}
+
+// Enciphered grids for each square of a message.
+func (c *Transposition) encipherGrids(s string) ([]grid.Grid, error) {
+	order, err := c.order()
+	if err != nil {
+		return nil, err
+	}
+
+	var out []grid.Grid
+	for _, b := range blocks(c.pad(s, len(order)), len(order)) {
+		g := makegrid(len([]rune(b)), len(order))
+		g.FillByCol(b)
+		g.Rekey(order, order)
+		out = append(out, g)
+	}
+	return out, nil
+}
+
+// Deciphered grids for each square of a message, left with their rows and columns rearranged.
+func (c *Transposition) decipherGrids(s string) ([]grid.Grid, error) {
+	order, err := c.order()
+	if err != nil {
+		return nil, err
+	}
+
+	var out []grid.Grid
+	for _, b := range blocks(s, len(order)) {
+		g := makegrid(len([]rune(b)), len(order))
+		g.Rekey(order, order)
+		if c.ByColumn {
+			g.FillByRow(b)
+		} else {
+			g.FillByCol(b)
+		}
+		out = append(out, g)
+	}
+	return out, nil
+}
+
+// Concatenate traces for successive squares, numbering events throughout the message.
+func concatenate(gg []grid.Grid) trace.Trace {
+	var out trace.Trace
+	for _, g := range gg {
+		n := len(out)
+		for _, e := range g.Trace() {
+			e.Index += n
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// EncipherTrace enciphers a message and records the cell of the rearranged square for each rune.
+func (c *Transposition) EncipherTrace(s string) (string, trace.Trace, error) {
+	gg, err := c.encipherGrids(s)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var b strings.Builder
+	for _, g := range gg {
+		if c.ByColumn {
+			b.WriteString(g.ReadByCol())
+		} else {
+			b.WriteString(g.ReadByRow())
+		}
+	}
+	return b.String(), concatenate(gg), nil
+}
+
+// DecipherTrace deciphers a message and records the cell of the rearranged square for each rune.
+func (c *Transposition) DecipherTrace(s string) (string, trace.Trace, error) {
+	order, err := c.order()
+	if err != nil {
+		return "", nil, err
+	}
+	gg, err := c.decipherGrids(s)
+	if err != nil {
+		return "", nil, err
+	}
+	tr := concatenate(gg)
+
+	inv := inverse(order)
+	var b strings.Builder
+	for _, g := range gg {
+		g.Rekey(inv, inv)
+		b.WriteString(g.ReadByRow())
+	}
+	return b.String(), tr, nil
+}
+
+// Printable squares, separated by blank lines.
+func printable(gg []grid.Grid) string {
+	out := make([]string, len(gg))
+	for i, g := range gg {
+		out[i] = g.Printable()
+	}
+	return strings.Join(out, "\n\n")
+}
+
+// EnciphermentGrid returns the rearranged squares upon encipherment.
+func (c *Transposition) EnciphermentGrid(s string) (string, error) {
+	gg, err := c.encipherGrids(s)
+	if err != nil {
+		return "", err
+	}
+	return printable(gg), nil
+}
+
+// DeciphermentGrid returns the rearranged squares upon decipherment.
+func (c *Transposition) DeciphermentGrid(s string) (string, error) {
+	gg, err := c.decipherGrids(s)
+	if err != nil {
+		return "", err
+	}
+	return printable(gg), nil
+}
+
+// Matrix of squares stacked one above another, each filled out to the full size of a square.
+func matrix(gg []grid.Grid, side int) [][]string {
+	var out [][]string
+	for _, g := range gg {
+		m := g.Matrix()
+		for i := 0; i < side; i++ {
+			row := make([]string, side)
+			if i < len(m) {
+				copy(row, m[i])
+			}
+			out = append(out, row)
+		}
+	}
+	return out
+}
+
+// EnciphermentMatrix returns the rearranged squares upon encipherment as a matrix, with empty strings for empty cells.
+func (c *Transposition) EnciphermentMatrix(s string) ([][]string, error) {
+	order, err := c.order()
+	if err != nil {
+		return nil, err
+	}
+	gg, err := c.encipherGrids(s)
+	if err != nil {
+		return nil, err
+	}
+	return matrix(gg, len(order)), nil
+}
+
+// DeciphermentMatrix returns the rearranged squares upon decipherment as a matrix, with empty strings for empty cells.
+func (c *Transposition) DeciphermentMatrix(s string) ([][]string, error) {
+	order, err := c.order()
+	if err != nil {
+		return nil, err
+	}
+	gg, err := c.decipherGrids(s)
+	if err != nil {
+		return nil, err
+	}
+	return matrix(gg, len(order)), nil
+}
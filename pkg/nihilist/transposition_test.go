@@ -0,0 +1,123 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nihilist
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestTransposition_Encipher(t *testing.T) {
+	testdata, err := ioutil.ReadFile(filepath.Join("testdata", "transposition_encipher.json"))
+	if err != nil {
+		t.Fatal("Could not read testdata fixture:", err)
+	}
+
+	var tables []struct {
+		Transposition
+
+		Input  string
+		Output string
+	}
+	if err := json.Unmarshal(testdata, &tables); err != nil {
+		t.Fatal("Could not unmarshal testdata:", err)
+	}
+
+	for _, table := range tables {
+		if out, err := table.Encipher(table.Input); err != nil {
+			t.Error("Could not encipher:", err)
+		} else if out != table.Output {
+			t.Errorf("Expected %q to encipher to %q, but instead got %q", table.Input, table.Output, out)
+		}
+	}
+}
+
+func TestTransposition_Decipher(t *testing.T) {
+	testdata, err := ioutil.ReadFile(filepath.Join("testdata", "transposition_decipher.json"))
+	if err != nil {
+		t.Fatal("Could not read testdata fixture:", err)
+	}
+
+	var tables []struct {
+		Transposition
+
+		Input  string
+		Output string
+	}
+	if err := json.Unmarshal(testdata, &tables); err != nil {
+		t.Fatal("Could not unmarshal testdata:", err)
+	}
+
+	for _, table := range tables {
+		if out, err := table.Decipher(table.Input); err != nil {
+			t.Error("Could not decipher:", err)
+		} else if out != table.Output {
+			t.Errorf("Expected %q to decipher to %q, but instead got %q", table.Input, table.Output, out)
+		}
+	}
+}
+
+func TestTransposition_Padding(t *testing.T) {
+	c := Transposition{Key: "CAB", Padding: 'X'}
+	out, err := c.Encipher("ABCDEFG")
+	if err != nil {
+		t.Fatal("Could not encipher:", err)
+	}
+	if out != "EFDXXGBCA" {
+		t.Errorf("Expected %q, but got %q", "EFDXXGBCA", out)
+	}
+
+	if _, err := (&Transposition{}).Encipher("ABC"); err == nil {
+		t.Error("Expected error for empty key")
+	}
+}
+
+func TestTransposition_EncipherTrace(t *testing.T) {
+	c := Transposition{Key: "CAB"}
+	_, tr, err := c.EncipherTrace("ABCDEFGHIJ")
+	if err != nil {
+		t.Fatal("Could not encipher:", err)
+	}
+	if len(tr) != 10 {
+		t.Fatalf("Expected 10 events, but got %d", len(tr))
+	}
+
+	// A moves from the first row and column of its square to the last, and J begins the next square
+	if e := tr[0]; e.Input != 'A' || e.Row != 2 || e.Col != 2 {
+		t.Errorf("Unexpected event %+v", e)
+	}
+	if e := tr[9]; e.Index != 9 || e.Input != 'J' || e.Row != 2 || e.Col != 2 {
+		t.Errorf("Unexpected event %+v", e)
+	}
+}
+
+func ExampleTransposition_EnciphermentGrid() {
+	c := Transposition{Key: "ZEBRA"}
+	out, err := c.EnciphermentGrid("WEAREDISCOVEREDFLEEATONCE")
+	if err != nil {
+		fmt.Println("Error:", err)
+	}
+	fmt.Println(out)
+
+	// Output:
+	// ENOCT
+	// DREEV
+	// OSICD
+	// AELEF
+	// EAERW
+}
@@ -0,0 +1,113 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otp
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/merenbach/goldbug/internal/alphabet"
+	"github.com/merenbach/goldbug/internal/format"
+	"github.com/merenbach/goldbug/internal/pasc"
+)
+
+// A Book holds pages of pad material.
+// ID identifies the book in a ledger, and pages are numbered from 1.
+type Book struct {
+	ID       string   `json:"id"`
+	Alphabet string   `json:"alphabet"`
+	Pages    []string `json:"pages"`
+}
+
+// NewBook generates a book with pages of the given length, drawing from crypto/rand.
+func NewBook(a string, pages int, length int) (*Book, error) {
+	return GenerateBook(rand.Reader, a, pages, length)
+}
+
+// GenerateBook generates a book with pages of the given length, drawing uniformly from the alphabet with random bytes from r.
+func GenerateBook(r io.Reader, a string, pages int, length int) (*Book, error) {
+	if a == "" {
+		a = pasc.Alphabet
+	}
+	if err := alphabet.Validate(a); err != nil {
+		return nil, err
+	}
+	if pages < 1 || length < 1 {
+		return nil, errors.New("Book must have at least one page of at least one rune")
+	}
+
+	id := make([]byte, 8)
+	if _, err := io.ReadFull(r, id); err != nil {
+		return nil, err
+	}
+
+	rr := []rune(a)
+	n := big.NewInt(int64(len(rr)))
+	b := &Book{ID: hex.EncodeToString(id), Alphabet: a, Pages: make([]string, pages)}
+	for i := range b.Pages {
+		page := make([]rune, length)
+		for j := range page {
+			v, err := rand.Int(r, n)
+			if err != nil {
+				return nil, err
+			}
+			page[j] = rr[v.Int64()]
+		}
+		b.Pages[i] = string(page)
+	}
+	return b, nil
+}
+
+// Page returns the pad material on a page.
+func (b *Book) Page(n int) (string, error) {
+	if n < 1 || n > len(b.Pages) {
+		return "", fmt.Errorf("Book has no page %d", n)
+	}
+	return b.Pages[n-1], nil
+}
+
+// Format a page in groups of five runes, with ten groups to a line.
+func (b *Book) Format(n int) (string, error) {
+	p, err := b.Page(n)
+	if err != nil {
+		return "", err
+	}
+	f := format.Formatter{Alphabet: b.Alphabet, GroupSize: 5, GroupsPerLine: 10}
+	return f.Format(p), nil
+}
+
+// ReadBook decodes a book from JSON.
+func ReadBook(r io.Reader) (*Book, error) {
+	var b Book
+	if err := json.NewDecoder(r).Decode(&b); err != nil {
+		return nil, err
+	}
+	if b.ID == "" {
+		return nil, errors.New("Book must have an ID")
+	}
+	return &b, nil
+}
+
+// WriteBook encodes a book as indented JSON.
+func WriteBook(w io.Writer, b *Book) error {
+	e := json.NewEncoder(w)
+	e.SetIndent("", "    ")
+	return e.Encode(b)
+}
@@ -0,0 +1,161 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otp
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewBook(t *testing.T) {
+	b, err := NewBook("", 3, 100)
+	if err != nil {
+		t.Fatal("Could not generate book:", err)
+	}
+	if len(b.ID) != 16 {
+		t.Errorf("Expected a 16-digit ID, but got %q", b.ID)
+	}
+	if len(b.Pages) != 3 {
+		t.Fatalf("Expected 3 pages, but got %d", len(b.Pages))
+	}
+	for _, p := range b.Pages {
+		if len(p) != 100 || strings.Trim(p, b.Alphabet) != "" {
+			t.Errorf("Expected 100 letters, but got %q", p)
+		}
+	}
+	if b.Pages[0] == b.Pages[1] {
+		t.Error("Expected pages to differ")
+	}
+
+	for _, table := range []struct {
+		a      string
+		pages  int
+		length int
+	}{
+		{"AA", 1, 1},
+		{"", 0, 1},
+		{"", 1, 0},
+	} {
+		if _, err := NewBook(table.a, table.pages, table.length); err == nil {
+			t.Errorf("Expected error generating book of %d pages of %d runes of %q", table.pages, table.length, table.a)
+		}
+	}
+}
+
+func TestBook_Format(t *testing.T) {
+	b := &Book{ID: "test", Alphabet: "ABCDEFGHIJKLMNOPQRSTUVWXYZ", Pages: []string{strings.Repeat("ABCDEFGHIJKLMNOPQRSTUVWXY", 3)}}
+	out, err := b.Format(1)
+	if err != nil {
+		t.Fatal("Could not format page:", err)
+	}
+	const expected = "ABCDE FGHIJ KLMNO PQRST UVWXY ABCDE FGHIJ KLMNO PQRST UVWXY\nABCDE FGHIJ KLMNO PQRST UVWXY"
+	if out != expected {
+		t.Errorf("Expected %q, but got %q", expected, out)
+	}
+
+	if _, err := b.Format(2); err == nil {
+		t.Error("Expected error formatting a missing page")
+	}
+}
+
+func TestReadBook(t *testing.T) {
+	b, err := NewBook("0123456789", 2, 50)
+	if err != nil {
+		t.Fatal("Could not generate book:", err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteBook(&buf, b); err != nil {
+		t.Fatal("Could not write book:", err)
+	}
+	if c, err := ReadBook(&buf); err != nil {
+		t.Error("Could not read book:", err)
+	} else if !reflect.DeepEqual(b, c) {
+		t.Errorf("Expected book %+v to survive JSON, but got %+v", b, c)
+	}
+
+	if _, err := ReadBook(strings.NewReader(`{"pages": ["ABC"]}`)); err == nil {
+		t.Error("Expected error reading a book without an ID")
+	}
+}
+
+func TestLedger(t *testing.T) {
+	dir, err := ioutil.TempDir("", "otp")
+	if err != nil {
+		t.Fatal("Could not create temporary directory:", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "ledger.json")
+
+	b := &Book{ID: "test", Alphabet: "ABCDEFGHIJKLMNOPQRSTUVWXYZ", Pages: []string{"ABCDEFGHIJ", "KLMNOPQRST"}}
+	l, err := OpenLedger(path)
+	if err != nil {
+		t.Fatal("Could not open ledger:", err)
+	}
+
+	if out, err := l.Take(b, 1, 4); err != nil {
+		t.Fatal("Could not take pad material:", err)
+	} else if out != "ABCD" {
+		t.Errorf("Expected %q, but got %q", "ABCD", out)
+	}
+
+	// A fresh ledger from the same file continues where the first left off.
+	l, err = OpenLedger(path)
+	if err != nil {
+		t.Fatal("Could not reopen ledger:", err)
+	}
+	if out, err := l.Take(b, 1, 4); err != nil {
+		t.Fatal("Could not take pad material:", err)
+	} else if out != "EFGH" {
+		t.Errorf("Expected %q, but got %q", "EFGH", out)
+	}
+
+	if _, err := l.TakeAt(b, 1, 6, 2, false); !errors.Is(err, ErrReused) {
+		t.Errorf("Expected reuse to be refused, but got %v", err)
+	}
+	if out, err := l.TakeAt(b, 1, 6, 2, true); err != nil {
+		t.Error("Could not force reuse:", err)
+	} else if out != "GH" {
+		t.Errorf("Expected %q, but got %q", "GH", out)
+	}
+	if _, err := l.Take(b, 1, 3); !errors.Is(err, ErrShortPad) {
+		t.Errorf("Expected page to run out, but got %v", err)
+	}
+	if _, err := l.Take(b, 3, 1); err == nil {
+		t.Error("Expected error taking from a missing page")
+	}
+
+	if out, err := l.Take(b, 2, 10); err != nil {
+		t.Error("Could not take pad material:", err)
+	} else if out != "KLMNOPQRST" {
+		t.Errorf("Expected %q, but got %q", "KLMNOPQRST", out)
+	}
+	if l.Offset(b, 1) != 8 || l.Offset(b, 2) != 10 {
+		t.Errorf("Unexpected offsets %v", l.Used)
+	}
+
+	if err := ioutil.WriteFile(path, []byte("{"), 0600); err != nil {
+		t.Fatal("Could not corrupt ledger:", err)
+	}
+	if _, err := OpenLedger(path); err == nil {
+		t.Error("Expected error opening a corrupt ledger")
+	}
+}
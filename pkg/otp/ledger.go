@@ -0,0 +1,112 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// ErrReused is returned when pad material has already been used.
+var ErrReused = errors.New("Pad material has already been used")
+
+// A Ledger records, in a local file, how far each page of each book has been used.
+type Ledger struct {
+	path string
+
+	// Used holds the number of runes used on each page, keyed by book ID and then by page number.
+	Used map[string]map[int]int `json:"used"`
+}
+
+// OpenLedger reads the ledger at a path, or starts an empty one if there is no file yet.
+func OpenLedger(path string) (*Ledger, error) {
+	l := &Ledger{path: path, Used: make(map[string]map[int]int)}
+	bb, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return l, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(bb, l); err != nil {
+		return nil, fmt.Errorf("Could not read ledger %s: %w", path, err)
+	}
+	if l.Used == nil {
+		l.Used = make(map[string]map[int]int)
+	}
+	return l, nil
+}
+
+// Save the ledger, replacing its file only once the new contents are fully written.
+func (l *Ledger) save() error {
+	bb, err := json.MarshalIndent(l, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile(filepath.Dir(l.path), ".ledger")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(bb); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), l.path)
+}
+
+// Offset at which unused material begins on a page of a book.
+func (l *Ledger) Offset(b *Book, page int) int {
+	return l.Used[b.ID][page]
+}
+
+// Take n runes of pad material from a page, starting where earlier use left off, and record their use.
+func (l *Ledger) Take(b *Book, page int, n int) (string, error) {
+	return l.TakeAt(b, page, l.Offset(b, page), n, false)
+}
+
+// TakeAt takes n runes of pad material from a page, starting at an offset, and records their use.
+// TakeAt refuses to take material that has already been used unless forced.
+func (l *Ledger) TakeAt(b *Book, page int, offset int, n int, force bool) (string, error) {
+	p, err := b.Page(page)
+	if err != nil {
+		return "", err
+	}
+	rr := []rune(p)
+	if offset < 0 || n < 0 || offset+n > len(rr) {
+		return "", fmt.Errorf("%w: page %d has %d runes left at offset %d, but %d are needed", ErrShortPad, page, len(rr)-offset, offset, n)
+	}
+	if used := l.Offset(b, page); offset < used && !force {
+		return "", fmt.Errorf("%w: page %d of book %s is used up to offset %d", ErrReused, page, b.ID, used)
+	}
+
+	if end := offset + n; end > l.Offset(b, page) {
+		if l.Used[b.ID] == nil {
+			l.Used[b.ID] = make(map[int]int)
+		}
+		l.Used[b.ID][page] = end
+		if err := l.save(); err != nil {
+			return "", err
+		}
+	}
+	return string(rr[offset : offset+n]), nil
+}
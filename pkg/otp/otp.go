@@ -0,0 +1,167 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package otp implements a one-time pad over any alphabet, along with pad books and a ledger of pad material already used.
+package otp
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/merenbach/goldbug/internal/alphabet"
+	"github.com/merenbach/goldbug/internal/normalize"
+	"github.com/merenbach/goldbug/internal/pasc"
+	"github.com/merenbach/goldbug/internal/stringutil"
+	"github.com/merenbach/goldbug/internal/trace"
+)
+
+// A Combination determines how pad material combines with a message on the tabula recta.
+type Combination uint8
+
+const (
+	// Vigenere adds the pad to the message.
+	Vigenere Combination = iota
+
+	// Beaufort subtracts the message from the pad, which makes encipherment and decipherment the same.
+	Beaufort
+)
+
+// ErrShortPad is returned when pad material runs out before the message does.
+var ErrShortPad = errors.New("Pad is shorter than the message")
+
+// Cipher implements a one-time pad.
+// Pad holds the pad material, from which any runes outside the alphabet, such as the spaces between groups, are dropped.
+type Cipher struct {
+	Alphabet    string
+	Pad         string
+	Combination Combination
+	Strict      bool
+	Normalizer  normalize.Normalizer
+}
+
+func (c *Cipher) maketableau() (*pasc.TabulaRecta, error) {
+	a := c.Alphabet
+	if a == "" {
+		a = pasc.Alphabet
+	}
+	if err := alphabet.Validate(a); err != nil {
+		return nil, err
+	}
+
+	switch c.Combination {
+	case Vigenere:
+		return &pasc.TabulaRecta{
+			PtAlphabet:  a,
+			CtAlphabet:  a,
+			KeyAlphabet: a,
+			Strict:      c.Strict,
+			Normalizer:  c.Normalizer,
+		}, nil
+	case Beaufort:
+		r := stringutil.Reverse(a)
+		return &pasc.TabulaRecta{
+			PtAlphabet:  a,
+			CtAlphabet:  r,
+			KeyAlphabet: r,
+			Strict:      c.Strict,
+			Normalizer:  c.Normalizer,
+		}, nil
+	}
+	return nil, fmt.Errorf("Unknown combination %d", c.Combination)
+}
+
+// Key checks that the pad holds enough material for a message and returns the material to use.
+func (c *Cipher) key(t *pasc.TabulaRecta, s string) (string, error) {
+	present := func(r rune) bool {
+		return strings.ContainsRune(t.PtAlphabet, r)
+	}
+	fold := func(r rune) rune {
+		if r = c.Normalizer.Fold(r, present); present(r) {
+			return r
+		}
+		return (-1)
+	}
+
+	pad := []rune(strings.Map(fold, c.Normalizer.Normalize(c.Pad)))
+	n := len([]rune(strings.Map(fold, c.Normalizer.Normalize(s))))
+	if n > len(pad) {
+		return "", fmt.Errorf("%w: %d runes of pad for %d runes of message", ErrShortPad, len(pad), n)
+	}
+	return string(pad[:n]), nil
+}
+
+// Transcode a message.
+func (c *Cipher) transcode(s string, decipher bool, tr *trace.Trace) (string, error) {
+	t, err := c.maketableau()
+	if err != nil {
+		return "", err
+	}
+	k, err := c.key(t, s)
+	if err != nil {
+		return "", err
+	}
+	if k == "" {
+		// Nothing will be transcoded, but the tableau still needs a key.
+		k = string([]rune(t.KeyAlphabet)[0])
+	}
+
+	t.Trace = tr
+	if decipher {
+		return t.Decipher(s, k, nil)
+	}
+	return t.Encipher(s, k, nil)
+}
+
+// Encipher a message.
+func (c *Cipher) Encipher(s string) (string, error) {
+	return c.transcode(s, false, nil)
+}
+
+// Decipher a message.
+func (c *Cipher) Decipher(s string) (string, error) {
+	return c.transcode(s, true, nil)
+}
+
+// EncipherTrace enciphers a message and records how each rune was produced.
+func (c *Cipher) EncipherTrace(s string) (string, trace.Trace, error) {
+	var tr trace.Trace
+	out, err := c.transcode(s, false, &tr)
+	return out, tr, err
+}
+
+// DecipherTrace deciphers a message and records how each rune was produced.
+func (c *Cipher) DecipherTrace(s string) (string, trace.Trace, error) {
+	var tr trace.Trace
+	out, err := c.transcode(s, true, &tr)
+	return out, tr, err
+}
+
+// Tableau for encipherment and decipherment.
+func (c *Cipher) Tableau() (string, error) {
+	t, err := c.maketableau()
+	if err != nil {
+		return "", err
+	}
+	return t.Printable()
+}
+
+// TableauMatrix for encipherment and decipherment.
+func (c *Cipher) TableauMatrix() ([][]string, error) {
+	t, err := c.maketableau()
+	if err != nil {
+		return nil, err
+	}
+	return t.Matrix()
+}
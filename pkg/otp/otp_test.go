@@ -0,0 +1,120 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestCipher_Encipher(t *testing.T) {
+	testdata, err := ioutil.ReadFile(filepath.Join("testdata", "cipher_encipher.json"))
+	if err != nil {
+		t.Fatal("Could not read testdata fixture:", err)
+	}
+
+	var tables []struct {
+		Cipher
+
+		Input  string
+		Output string
+	}
+	if err := json.Unmarshal(testdata, &tables); err != nil {
+		t.Fatal("Could not unmarshal testdata:", err)
+	}
+
+	for _, table := range tables {
+		if out, err := table.Encipher(table.Input); err != nil {
+			t.Error("Could not encipher:", err)
+		} else if out != table.Output {
+			t.Errorf("Expected %q to encipher to %q, but instead got %q", table.Input, table.Output, out)
+		}
+	}
+}
+
+func TestCipher_Decipher(t *testing.T) {
+	testdata, err := ioutil.ReadFile(filepath.Join("testdata", "cipher_decipher.json"))
+	if err != nil {
+		t.Fatal("Could not read testdata fixture:", err)
+	}
+
+	var tables []struct {
+		Cipher
+
+		Input  string
+		Output string
+	}
+	if err := json.Unmarshal(testdata, &tables); err != nil {
+		t.Fatal("Could not unmarshal testdata:", err)
+	}
+
+	for _, table := range tables {
+		if out, err := table.Decipher(table.Input); err != nil {
+			t.Error("Could not decipher:", err)
+		} else if out != table.Output {
+			t.Errorf("Expected %q to decipher to %q, but instead got %q", table.Input, table.Output, out)
+		}
+	}
+}
+
+func TestCipher_Beaufort(t *testing.T) {
+	c := Cipher{Pad: "XMCKL", Combination: Beaufort}
+	for _, f := range []func(string) (string, error){c.Encipher, c.Decipher} {
+		if out, err := f("HELLO"); err != nil {
+			t.Error("Could not transcode:", err)
+		} else if out != "QIRZX" {
+			t.Errorf("Expected %q, but got %q", "QIRZX", out)
+		}
+	}
+}
+
+func TestCipher_errors(t *testing.T) {
+	tables := []struct {
+		Cipher
+		Input string
+	}{
+		{Cipher{Pad: "XMCK"}, "HELLO"},
+		{Cipher{Pad: "XMCK!"}, "HELLO"},
+		{Cipher{Pad: "XMCKL", Alphabet: "ABCA"}, "HELLO"},
+		{Cipher{Pad: "XMCKL", Combination: 2}, "HELLO"},
+	}
+
+	for _, table := range tables {
+		if _, err := table.Encipher(table.Input); err == nil {
+			t.Errorf("Expected error enciphering %q with %+v", table.Input, table.Cipher)
+		}
+	}
+
+	c := Cipher{Pad: "XMCK"}
+	if _, err := c.Encipher("HELLO"); !errors.Is(err, ErrShortPad) {
+		t.Errorf("Expected a short pad, but got %v", err)
+	}
+}
+
+func ExampleCipher_Encipher() {
+	c := Cipher{Pad: "XMCKL QWERT", Combination: Beaufort}
+	out, err := c.Encipher("HELLO")
+	if err != nil {
+		fmt.Println("Error:", err)
+	}
+	fmt.Println(out)
+
+	// Output:
+	// QIRZX
+}
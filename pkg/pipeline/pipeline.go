@@ -0,0 +1,48 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pipeline composes ciphers for product ciphers and superencipherment.
+package pipeline
+
+// A Cipher enciphers and deciphers messages.
+type Cipher interface {
+	Encipher(string) (string, error)
+	Decipher(string) (string, error)
+}
+
+// A Pipeline composes ciphers in sequence.
+// Encipherment runs each stage in order, while decipherment runs them in reverse order.
+type Pipeline []Cipher
+
+// Encipher a message.
+func (p Pipeline) Encipher(s string) (string, error) {
+	for _, c := range p {
+		var err error
+		if s, err = c.Encipher(s); err != nil {
+			return "", err
+		}
+	}
+	return s, nil
+}
+
+// Decipher a message.
+func (p Pipeline) Decipher(s string) (string, error) {
+	for i := len(p) - 1; i >= 0; i-- {
+		var err error
+		if s, err = p[i].Decipher(s); err != nil {
+			return "", err
+		}
+	}
+	return s, nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/merenbach/goldbug/pkg/keyword"
+	"github.com/merenbach/goldbug/pkg/railfence"
+	"github.com/merenbach/goldbug/pkg/vigenere"
+)
+
+func TestPipeline(t *testing.T) {
+	tables := []struct {
+		p          Pipeline
+		plaintext  string
+		ciphertext string
+	}{
+		{Pipeline{}, "HELLO", "HELLO"},
+		{Pipeline{&keyword.Cipher{Keyword: "KANGAROO"}}, "HELLO", "CRHHL"},
+		{Pipeline{&railfence.Cipher{Rows: 3}}, "WEAREDISCOVEREDFLEEATONCE", "WECRLTEERDSOEEFEAOCAIVDEN"},
+		{Pipeline{&keyword.Cipher{Keyword: "KANGAROO"}, &railfence.Cipher{Rows: 3}}, "WEAREDISCOVEREDFLEEATONCE", "WRNQHTRRQGSLRRORKLNKDVGRJ"},
+		{Pipeline{&vigenere.Cipher{Key: "LEMON"}, &vigenere.Cipher{Key: "ABC"}}, "ATTACKATDAWN", "LYHOQXEGTNIT"},
+	}
+
+	for _, table := range tables {
+		if out, err := table.p.Encipher(table.plaintext); err != nil {
+			t.Error("Error:", err)
+		} else if out != table.ciphertext {
+			t.Errorf("Expected %q to encipher as %q, but got %q", table.plaintext, table.ciphertext, out)
+		}
+		if out, err := table.p.Decipher(table.ciphertext); err != nil {
+			t.Error("Error:", err)
+		} else if out != table.plaintext {
+			t.Errorf("Expected %q to decipher as %q, but got %q", table.ciphertext, table.plaintext, out)
+		}
+	}
+}
+
+func ExamplePipeline() {
+	p := Pipeline{
+		&keyword.Cipher{Keyword: "KANGAROO"},
+		&railfence.Cipher{Rows: 3},
+	}
+
+	encrypted, _ := p.Encipher("WEAREDISCOVEREDFLEEATONCE")
+	fmt.Println(encrypted)
+
+	decrypted, _ := p.Decipher(encrypted)
+	fmt.Println(decrypted)
+
+	// Output:
+	// WRNQHTRRQGSLRRORKLNKDVGRJ
+	// WEAREDISCOVEREDFLEEATONCE
+}
@@ -0,0 +1,282 @@
+// Copyright 2019 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package polybius
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/merenbach/goldbug/internal/alphabet"
+	"github.com/merenbach/goldbug/internal/normalize"
+	"github.com/merenbach/goldbug/internal/trace"
+)
+
+// Alphabet to use by default, which omits J to fill a five-by-five square.
+const Alphabet = alphabet.Latin25
+
+// Merge rule to use by default with the default alphabet, or any alphabet equal to it, enciphering J as I.
+const Merge = "JI"
+
+// Labels to use by default for rows and columns, as many as a square needs.
+const labels = "123456789"
+
+// Cipher implements a Polybius square, which replaces each rune with the labels of its row and column.
+// Merge holds pairs of runes, each replacing the first with the second before encipherment.
+type Cipher struct {
+	Alphabet     string
+	RowLabels    string
+	ColumnLabels string
+	Merge        string
+	Strict       bool
+	Normalizer   normalize.Normalizer
+}
+
+// A square holds the lookup tables for a Polybius square.
+type square struct {
+	alphabet []rune
+	rows     []rune
+	cols     []rune
+	merge    map[rune]rune
+	coords   map[rune][2]int
+}
+
+func (c *Cipher) makesquare() (*square, error) {
+	a, merge := c.Alphabet, c.Merge
+	if a == "" {
+		a = Alphabet
+	}
+	if a == Alphabet && merge == "" {
+		merge = Merge
+	}
+	if err := alphabet.Validate(a); err != nil {
+		return nil, err
+	}
+	aa := []rune(a)
+
+	// Default labels make the smallest square that holds the alphabet
+	side := int(math.Ceil(math.Sqrt(float64(len(aa)))))
+	rows, cols := c.RowLabels, c.ColumnLabels
+	if rows == "" || cols == "" {
+		if side > len(labels) {
+			return nil, errors.New("Alphabet is too long for default labels")
+		}
+		if rows == "" {
+			rows = labels[:side]
+		}
+		if cols == "" {
+			cols = labels[:side]
+		}
+	}
+	if err := alphabet.Unique(rows); err != nil {
+		return nil, fmt.Errorf("Invalid row labels: %w", err)
+	}
+	if err := alphabet.Unique(cols); err != nil {
+		return nil, fmt.Errorf("Invalid column labels: %w", err)
+	}
+	rr, cc := []rune(rows), []rune(cols)
+	if len(rr)*len(cc) < len(aa) {
+		return nil, fmt.Errorf("A square of %d rows and %d columns cannot hold %d characters", len(rr), len(cc), len(aa))
+	}
+
+	sq := &square{
+		alphabet: aa,
+		rows:     rr,
+		cols:     cc,
+		merge:    make(map[rune]rune),
+		coords:   make(map[rune][2]int, len(aa)),
+	}
+	for i, r := range aa {
+		sq.coords[r] = [2]int{i / len(cc), i % len(cc)}
+	}
+
+	mm := []rune(merge)
+	if len(mm)%2 != 0 {
+		return nil, errors.New("Merge rule must consist of pairs of characters")
+	}
+	for i := 0; i < len(mm); i += 2 {
+		if _, ok := sq.coords[mm[i+1]]; !ok {
+			return nil, fmt.Errorf("Merge target %q is not in the alphabet", mm[i+1])
+		}
+		sq.merge[mm[i]] = mm[i+1]
+	}
+	return sq, nil
+}
+
+// Find the rune in a square, after case folding and merging, and report whether it was found.
+func (c *Cipher) find(sq *square, r rune) (rune, bool) {
+	present := func(r rune) bool {
+		if _, ok := sq.coords[r]; ok {
+			return true
+		}
+		_, ok := sq.merge[r]
+		return ok
+	}
+	f := c.Normalizer.Fold(r, present)
+	if m, ok := sq.merge[f]; ok {
+		f = m
+	}
+	_, ok := sq.coords[f]
+	return f, ok
+}
+
+// Encipher a message.
+func (c *Cipher) Encipher(s string) (string, error) {
+	return c.encipher(s, nil)
+}
+
+// EncipherTrace enciphers a message and records how each rune was produced.
+// The output of each event is the row label, as a single event cannot hold both labels.
+func (c *Cipher) EncipherTrace(s string) (string, trace.Trace, error) {
+	var tr trace.Trace
+	out, err := c.encipher(s, &tr)
+	return out, tr, err
+}
+
+// Encipher a message, recording an event for each rune if a trace is given.
+func (c *Cipher) encipher(s string, tr *trace.Trace) (string, error) {
+	sq, err := c.makesquare()
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	for i, r := range []rune(c.Normalizer.Normalize(s)) {
+		if f, ok := c.find(sq, r); ok {
+			rc := sq.coords[f]
+			tr.Add(trace.Event{Index: i, Input: r, Row: rc[0], Col: rc[1], Output: sq.rows[rc[0]], Action: trace.Transcoded})
+			b.WriteRune(sq.rows[rc[0]])
+			b.WriteRune(sq.cols[rc[1]])
+		} else if !c.Strict {
+			tr.Pass(i, r, 0)
+			b.WriteRune(r)
+		} else {
+			tr.Skip(i, r, 0)
+		}
+	}
+	return b.String(), nil
+}
+
+// Decipher a message.
+// Each row label followed by a column label is deciphered, and other runes are passed through unless strict.
+func (c *Cipher) Decipher(s string) (string, error) {
+	return c.decipher(s, nil)
+}
+
+// DecipherTrace deciphers a message and records how each rune was produced.
+// Each pair of labels yields a single event, with the row label as its input.
+func (c *Cipher) DecipherTrace(s string) (string, trace.Trace, error) {
+	var tr trace.Trace
+	out, err := c.decipher(s, &tr)
+	return out, tr, err
+}
+
+// Decipher a message, recording an event for each pair of labels or other rune if a trace is given.
+func (c *Cipher) decipher(s string, tr *trace.Trace) (string, error) {
+	sq, err := c.makesquare()
+	if err != nil {
+		return "", err
+	}
+
+	rows, cols := indices(sq.rows), indices(sq.cols)
+
+	var b strings.Builder
+	rr := []rune(s)
+	for i := 0; i < len(rr); i++ {
+		if i+1 < len(rr) {
+			y, ok1 := rows[rr[i]]
+			x, ok2 := cols[rr[i+1]]
+			if n := y*len(sq.cols) + x; ok1 && ok2 && n < len(sq.alphabet) {
+				tr.Add(trace.Event{Index: i, Input: rr[i], Row: y, Col: x, Output: sq.alphabet[n], Action: trace.Transcoded})
+				b.WriteRune(sq.alphabet[n])
+				i++
+				continue
+			}
+		}
+		if !c.Strict {
+			tr.Pass(i, rr[i], 0)
+			b.WriteRune(rr[i])
+		} else {
+			tr.Skip(i, rr[i], 0)
+		}
+	}
+	return b.String(), nil
+}
+
+// Labels that may appear in ciphertext, with row labels followed by column labels.
+func (c *Cipher) Labels() (string, error) {
+	sq, err := c.makesquare()
+	if err != nil {
+		return "", err
+	}
+	return string(sq.rows) + string(sq.cols), nil
+}
+
+// Indices of runes by position.
+func indices(rr []rune) map[rune]int {
+	out := make(map[rune]int, len(rr))
+	for i, r := range rr {
+		out[r] = i
+	}
+	return out
+}
+
+// Tableau for encipherment and decipherment.
+func (c *Cipher) Tableau() (string, error) {
+	sq, err := c.makesquare()
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString("   ")
+	for _, r := range sq.cols {
+		b.WriteString(" " + string(r))
+	}
+	b.WriteString("\n  +")
+	b.WriteString(strings.Repeat("-", 2*len(sq.cols)))
+	for i, r := range sq.rows {
+		b.WriteString("\n" + string(r) + " |")
+		for j := range sq.cols {
+			if n := i*len(sq.cols) + j; n < len(sq.alphabet) {
+				b.WriteString(" " + string(sq.alphabet[n]))
+			}
+		}
+	}
+	return b.String(), nil
+}
+
+// TableauMatrix for encipherment and decipherment, with row labels in the first column and column labels in the first row.
+func (c *Cipher) TableauMatrix() ([][]string, error) {
+	sq, err := c.makesquare()
+	if err != nil {
+		return nil, err
+	}
+
+	out := [][]string{append([]string{""}, strings.Split(string(sq.cols), "")...)}
+	for i, r := range sq.rows {
+		row := []string{string(r)}
+		for j := range sq.cols {
+			var s string
+			if n := i*len(sq.cols) + j; n < len(sq.alphabet) {
+				s = string(sq.alphabet[n])
+			}
+			row = append(row, s)
+		}
+		out = append(out, row)
+	}
+	return out, nil
+}
@@ -0,0 +1,139 @@
+// Copyright 2019 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package polybius
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/merenbach/goldbug/internal/alphabet"
+	"github.com/merenbach/goldbug/internal/normalize"
+)
+
+func TestCipher_Encipher(t *testing.T) {
+	testdata, err := ioutil.ReadFile(filepath.Join("testdata", "cipher_encipher.json"))
+	if err != nil {
+		t.Fatal("Could not read testdata fixture:", err)
+	}
+
+	var tables []struct {
+		Cipher
+
+		Input  string
+		Output string
+	}
+	if err := json.Unmarshal(testdata, &tables); err != nil {
+		t.Fatal("Could not unmarshal testdata:", err)
+	}
+
+	for _, table := range tables {
+		if out, err := table.Encipher(table.Input); err != nil {
+			t.Error("Could not encipher:", err)
+		} else if out != table.Output {
+			t.Errorf("Expected %q to encipher to %q, but instead got %q", table.Input, table.Output, out)
+		}
+	}
+}
+
+func TestCipher_Decipher(t *testing.T) {
+	testdata, err := ioutil.ReadFile(filepath.Join("testdata", "cipher_decipher.json"))
+	if err != nil {
+		t.Fatal("Could not read testdata fixture:", err)
+	}
+
+	var tables []struct {
+		Cipher
+
+		Input  string
+		Output string
+	}
+	if err := json.Unmarshal(testdata, &tables); err != nil {
+		t.Fatal("Could not unmarshal testdata:", err)
+	}
+
+	for _, table := range tables {
+		if out, err := table.Decipher(table.Input); err != nil {
+			t.Error("Could not decipher:", err)
+		} else if out != table.Output {
+			t.Errorf("Expected %q to decipher to %q, but instead got %q", table.Input, table.Output, out)
+		}
+	}
+}
+
+func TestCipher_PreserveCase(t *testing.T) {
+	c := Cipher{Normalizer: normalize.Normalizer{Case: normalize.PreserveCase}}
+	if out, err := c.Encipher("Jump"); err != nil {
+		t.Error("Could not encipher:", err)
+	} else if out != "24453235" {
+		t.Errorf("Expected %q, but got %q", "24453235", out)
+	}
+}
+
+func TestCipher_errors(t *testing.T) {
+	tables := []Cipher{
+		{Alphabet: "ABCA"},
+		{RowLabels: "1234"},
+		{RowLabels: "11234"},
+		{Merge: "JIV"},
+		{Merge: "J9"},
+		{Alphabet: alphabet.ASCII},
+	}
+
+	for _, c := range tables {
+		if _, err := c.Encipher("A"); err == nil {
+			t.Errorf("Expected error for cipher %+v", c)
+		}
+	}
+}
+
+func ExampleCipher_Tableau() {
+	c := Cipher{}
+	out, err := c.Tableau()
+	if err != nil {
+		fmt.Println("Error:", err)
+	}
+	fmt.Println(out)
+
+	// Output:
+	//     1 2 3 4 5
+	//   +----------
+	// 1 | A B C D E
+	// 2 | F G H I K
+	// 3 | L M N O P
+	// 4 | Q R S T U
+	// 5 | V W X Y Z
+}
+
+func ExampleCipher_Tableau_labels() {
+	c := Cipher{Alphabet: "ABCDEFGHIJKLMNOPQRSTUVWXYZ", RowLabels: "ADFGVX", ColumnLabels: "ADFGVX"}
+	out, err := c.Tableau()
+	if err != nil {
+		fmt.Println("Error:", err)
+	}
+	fmt.Println(out)
+
+	// Output:
+	//     A D F G V X
+	//   +------------
+	// A | A B C D E F
+	// D | G H I J K L
+	// F | M N O P Q R
+	// G | S T U V W X
+	// V | Y Z
+	// X |
+}
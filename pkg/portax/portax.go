@@ -0,0 +1,239 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package portax
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/merenbach/goldbug/internal/normalize"
+	"github.com/merenbach/goldbug/internal/pasc"
+	"github.com/merenbach/goldbug/internal/trace"
+)
+
+// Cipher implements the ACA Portax cipher.
+// The message is written in pairs of rows as wide as the key, and each vertical pair of runes is transcoded on a slide chosen by the key rune above it.
+// The upper half of each slide pairs the alphabet halves as in the Della Porta cipher, while the lower half splits the alphabet into alternating runes.
+// Runes outside the alphabet are dropped, and the cipher is reciprocal.
+type Cipher struct {
+	Alphabet   string
+	Key        string
+	Normalizer normalize.Normalizer
+}
+
+// A slide holds the upper and lower sections for one pair of key runes.
+type slide struct {
+	upper [2][]rune
+	lower [2][]rune
+}
+
+// A position locates a rune within one section of a slide.
+type position struct {
+	row int
+	col int
+}
+
+// Find the row and column of a rune within a section.
+func find(section [2][]rune, r rune) (position, bool) {
+	for i, row := range section {
+		for j, o := range row {
+			if o == r {
+				return position{i, j}, true
+			}
+		}
+	}
+	return position{}, false
+}
+
+// Transcode a pair of runes, the first from the upper section and the second from the lower section.
+// Runes in the same column are exchanged for the other runes in that column, and runes in different columns for the opposite corners of their rectangle.
+func (sl *slide) transcode(a rune, b rune) (rune, rune) {
+	p, _ := find(sl.upper, a)
+	q, _ := find(sl.lower, b)
+	if p.col == q.col {
+		return sl.upper[1-p.row][p.col], sl.lower[1-q.row][q.col]
+	}
+	return sl.upper[p.row][q.col], sl.lower[q.row][p.col]
+}
+
+// A tableau holds a slide for each pair of key runes.
+type tableau struct {
+	alphabet []rune
+	slides   []slide
+}
+
+func (c *Cipher) maketableau() (*tableau, error) {
+	alphabet := c.Alphabet
+	if alphabet == "" {
+		alphabet = pasc.Alphabet
+	}
+
+	a := []rune(alphabet)
+	if len(a) == 0 || len(a)%2 != 0 {
+		return nil, errors.New("Portax cipher alphabets must have even length")
+	}
+	h := len(a) / 2
+
+	lower := [2][]rune{make([]rune, h), make([]rune, h)}
+	for i, r := range a {
+		lower[i%2][i/2] = r
+	}
+
+	slides := make([]slide, h)
+	for s := range slides {
+		upper := [2][]rune{a[:h], make([]rune, h)}
+		for x := range upper[1] {
+			upper[1][x] = a[h+(x+s)%h]
+		}
+		slides[s] = slide{upper: upper, lower: lower}
+	}
+
+	return &tableau{alphabet: a, slides: slides}, nil
+}
+
+// Transcode a message, which is the same operation in both directions, recording an event for each input rune if a trace is given.
+// Rows 0 and 1 of each event belong to the upper section of the slide, and rows 2 and 3 to the lower section.
+func (c *Cipher) transcode(s string, tr *trace.Trace) (string, error) {
+	t, err := c.maketableau()
+	if err != nil {
+		return "", err
+	}
+
+	cols := make(map[rune]int, len(t.alphabet))
+	for i, r := range t.alphabet {
+		cols[r] = i
+	}
+	present := func(r rune) bool {
+		_, ok := cols[r]
+		return ok
+	}
+
+	var key []rune
+	for _, r := range c.Key {
+		f := c.Normalizer.Fold(r, present)
+		if !present(f) {
+			return "", fmt.Errorf("Key character %q is not in the alphabet", r)
+		}
+		key = append(key, f)
+	}
+	if len(key) == 0 {
+		return "", errors.New("Key must not be empty")
+	}
+
+	in := []rune(c.Normalizer.Normalize(s))
+	var msg []rune
+	var pos []int
+	for i, r := range in {
+		if f := c.Normalizer.Fold(r, present); present(f) {
+			msg = append(msg, f)
+			pos = append(pos, i)
+		}
+	}
+	if len(msg)%2 != 0 {
+		return "", errors.New("Portax messages must have even length")
+	}
+
+	events := make([]trace.Event, len(in))
+	for i, r := range in {
+		events[i] = trace.Event{Index: i, Input: r, Row: (-1), Col: (-1), Action: trace.Skipped}
+	}
+
+	out := make([]rune, len(msg))
+	for start, period := 0, len(key); start < len(msg); start += 2 * period {
+		// The final block may be narrower than the key
+		w := period
+		if n := (len(msg) - start) / 2; n < w {
+			w = n
+		}
+		for i := 0; i < w; i++ {
+			j, k := start+i, start+w+i
+			sl := &t.slides[cols[key[i]]/2]
+			out[j], out[k] = sl.transcode(msg[j], msg[k])
+
+			p, _ := find(sl.upper, msg[j])
+			q, _ := find(sl.lower, msg[k])
+			events[pos[j]] = trace.Event{Index: pos[j], Input: in[pos[j]], Key: key[i], Row: p.row, Col: p.col, Output: out[j], Action: trace.Transcoded}
+			events[pos[k]] = trace.Event{Index: pos[k], Input: in[pos[k]], Key: key[i], Row: 2 + q.row, Col: q.col, Output: out[k], Action: trace.Transcoded}
+		}
+	}
+	for _, e := range events {
+		tr.Add(e)
+	}
+	return string(out), nil
+}
+
+// Encipher a message.
+func (c *Cipher) Encipher(s string) (string, error) {
+	return c.transcode(s, nil)
+}
+
+// Decipher a message.
+func (c *Cipher) Decipher(s string) (string, error) {
+	return c.transcode(s, nil)
+}
+
+// EncipherTrace enciphers a message and records how each rune was produced.
+func (c *Cipher) EncipherTrace(s string) (string, trace.Trace, error) {
+	var tr trace.Trace
+	out, err := c.transcode(s, &tr)
+	return out, tr, err
+}
+
+// DecipherTrace deciphers a message and records how each rune was produced.
+func (c *Cipher) DecipherTrace(s string) (string, trace.Trace, error) {
+	return c.EncipherTrace(s)
+}
+
+// TableauMatrix for encipherment and decipherment, with the key runes for each slide in the first column.
+// The lower section, shared by all slides, follows the slides.
+func (c *Cipher) TableauMatrix() ([][]string, error) {
+	t, err := c.maketableau()
+	if err != nil {
+		return nil, err
+	}
+
+	row := func(label string, rr []rune) []string {
+		return append([]string{label}, strings.Split(string(rr), "")...)
+	}
+
+	var out [][]string
+	for s, sl := range t.slides {
+		out = append(out, row(string(t.alphabet[2*s:2*s+2]), sl.upper[0]), row("", sl.upper[1]))
+	}
+	lower := t.slides[0].lower
+	return append(out, row("", lower[0]), row("", lower[1])), nil
+}
+
+// Tableau for encipherment and decipherment.
+func (c *Cipher) Tableau() (string, error) {
+	m, err := c.TableauMatrix()
+	if err != nil {
+		return "", err
+	}
+
+	w := 0
+	for _, row := range m {
+		if n := utf8.RuneCountInString(row[0]); n > w {
+			w = n
+		}
+	}
+	lines := make([]string, len(m))
+	for i, row := range m {
+		lines[i] = fmt.Sprintf("%-*s | %s", w, row[0], strings.Join(row[1:], " "))
+	}
+	return strings.Join(lines, "\n"), nil
+}
@@ -0,0 +1,157 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package portax
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/merenbach/goldbug/internal/normalize"
+	"github.com/merenbach/goldbug/internal/trace"
+)
+
+func TestCipher_Encipher(t *testing.T) {
+	testdata, err := ioutil.ReadFile(filepath.Join("testdata", "cipher_encipher.json"))
+	if err != nil {
+		t.Fatal("Could not read testdata fixture:", err)
+	}
+
+	var tables []struct {
+		Cipher
+
+		Input  string
+		Output string
+	}
+	if err := json.Unmarshal(testdata, &tables); err != nil {
+		t.Fatal("Could not unmarshal testdata:", err)
+	}
+
+	for _, table := range tables {
+		if out, err := table.Encipher(table.Input); err != nil {
+			t.Error("Could not encipher:", err)
+		} else if out != table.Output {
+			t.Errorf("Expected %q to encipher to %q, but instead got %q", table.Input, table.Output, out)
+		}
+	}
+}
+
+func TestCipher_Decipher(t *testing.T) {
+	testdata, err := ioutil.ReadFile(filepath.Join("testdata", "cipher_decipher.json"))
+	if err != nil {
+		t.Fatal("Could not read testdata fixture:", err)
+	}
+
+	var tables []struct {
+		Cipher
+
+		Input  string
+		Output string
+	}
+	if err := json.Unmarshal(testdata, &tables); err != nil {
+		t.Fatal("Could not unmarshal testdata:", err)
+	}
+
+	for _, table := range tables {
+		if out, err := table.Decipher(table.Input); err != nil {
+			t.Error("Could not decipher:", err)
+		} else if out != table.Output {
+			t.Errorf("Expected %q to decipher to %q, but instead got %q", table.Input, table.Output, out)
+		}
+	}
+}
+
+func TestCipher_PreserveCase(t *testing.T) {
+	c := Cipher{Key: "portax", Normalizer: normalize.Normalizer{Case: normalize.PreserveCase}}
+	if out, err := c.Encipher("Attack at dawn!"); err != nil {
+		t.Error("Could not encipher:", err)
+	} else if out != "UQWWLGBZXBEV" {
+		t.Errorf("Expected %q, but got %q", "UQWWLGBZXBEV", out)
+	}
+}
+
+func TestCipher_EncipherTrace(t *testing.T) {
+	c := Cipher{Key: "NEW"}
+	out, tr, err := c.EncipherTrace("AT-TACK")
+	if err != nil {
+		t.Fatal("Could not encipher:", err)
+	}
+	if out != "TQQBIQ" {
+		t.Errorf("Expected %q, but got %q", "TQQBIQ", out)
+	}
+	if len(tr) != 7 {
+		t.Fatalf("Expected 7 events, but got %d", len(tr))
+	}
+
+	tables := []struct {
+		index  int
+		key    rune
+		row    int
+		col    int
+		output rune
+		action trace.Action
+	}{
+		{0, 'N', 0, 0, 'T', trace.Transcoded},
+		{2, 0, (-1), (-1), 0, trace.Skipped},
+		{4, 'N', 2, 0, 'B', trace.Transcoded},
+	}
+	for _, table := range tables {
+		e := tr[table.index]
+		if e.Key != table.key || e.Row != table.row || e.Col != table.col || e.Output != table.output || e.Action != table.action {
+			t.Errorf("Unexpected event at index %d: %+v", table.index, e)
+		}
+	}
+}
+
+func TestCipher_errors(t *testing.T) {
+	tables := []struct {
+		Cipher
+		Input string
+	}{
+		{Cipher{Key: "KEY"}, "ODD"},
+		{Cipher{Key: ""}, "EVEN"},
+		{Cipher{Key: "K3Y"}, "EVEN"},
+		{Cipher{Key: "KEY", Alphabet: "ABC"}, "AB"},
+	}
+
+	for _, table := range tables {
+		if _, err := table.Encipher(table.Input); err == nil {
+			t.Errorf("Expected error enciphering %q with key %q and alphabet %q", table.Input, table.Key, table.Alphabet)
+		}
+	}
+}
+
+func ExampleCipher_Tableau() {
+	c := Cipher{Alphabet: "ABCDEFGH"}
+	out, err := c.Tableau()
+	if err != nil {
+		fmt.Println("Error:", err)
+	}
+	fmt.Println(out)
+
+	// Output:
+	// AB | A B C D
+	//    | E F G H
+	// CD | A B C D
+	//    | F G H E
+	// EF | A B C D
+	//    | G H E F
+	// GH | A B C D
+	//    | H E F G
+	//    | A C E G
+	//    | B D F H
+}
@@ -15,24 +15,71 @@
 package railfence
 
 import (
+	"errors"
+	"sort"
+	"strings"
 	"unicode/utf8"
 
 	"github.com/merenbach/goldbug/internal/grid"
+	"github.com/merenbach/goldbug/internal/trace"
 )
 
 // Cipher implements a rail fence (or zig-zag) cipher.
+// Offset begins the message that many places into the zig-zag cycle.
+// Padding, if set, is appended as nulls upon encipherment to complete the final cycle.
+// Key, if set, implements the ACA Redefence variant, reading the rails in the alphabetical order of its runes.
 type Cipher struct {
-	Rows int
+	Rows    int
+	Offset  int
+	Padding rune
+	Key     string
 }
 
 // Row for the message character at the given index.
 func (c *Cipher) row(i int) int {
 	k := c.Rows - 1
-	return k - abs(k-i%(2*k))
+	if k == 0 {
+		return 0
+	}
+	return k - abs(k-(i+c.Offset)%(2*k))
+}
+
+// Order in which to read each rail, with ties broken by position.
+func (c *Cipher) order() ([]int, error) {
+	if c.Key == "" {
+		return nil, nil
+	}
+
+	kk := []rune(c.Key)
+	if len(kk) != c.Rows {
+		return nil, errors.New("Key must have one character for each rail")
+	}
+
+	ii := make([]int, len(kk))
+	for i := range ii {
+		ii[i] = i
+	}
+	sort.SliceStable(ii, func(i, j int) bool {
+		return kk[ii[i]] < kk[ii[j]]
+	})
+
+	out := make([]int, len(kk))
+	for pos, rail := range ii {
+		out[rail] = pos
+	}
+	return out, nil
 }
 
 // Makegrid creates a grid and numbers its cells.
-func (c *Cipher) makegrid(n int) grid.Grid {
+// Rows are numbered in reading order if the rails are keyed.
+func (c *Cipher) makegrid(n int, order []int) (grid.Grid, error) {
+	if c.Rows < 1 {
+		return nil, errors.New("Rows must be positive")
+	}
+	if c.Offset < 0 {
+		return nil, errors.New("Offset must not be negative")
+	}
+
 	g := make(grid.Grid, n)
 
 	for i := range g {
@@ -40,51 +87,162 @@ func (c *Cipher) makegrid(n int) grid.Grid {
 
 		// Cycle length is 2*(rows - 1), or 2*k
 		g[i].Row = c.row(i)
+		if order != nil {
+			g[i].Row = order[g[i].Row]
+		}
+	}
+
+	return g, nil
+}
+
+// Pad a message with nulls to complete the final zig-zag cycle.
+func (c *Cipher) pad(s string) string {
+	k := c.Rows - 1
+	if c.Padding == 0 || k < 1 {
+		return s
+	}
+	n := utf8.RuneCountInString(s) + c.Offset
+	if m := n % (2 * k); m != 0 {
+		return s + strings.Repeat(string(c.Padding), 2*k-m)
 	}
+	return s
+}
+
+// Rails for each position in the reading order.
+func rails(order []int) []int {
+	out := make([]int, len(order))
+	for rail, pos := range order {
+		out[pos] = rail
+	}
+	return out
+}
 
+// Unkey restores the rail number of each event from its reading order.
+func unkey(tr trace.Trace, order []int) trace.Trace {
+	if order == nil {
+		return tr
+	}
+	rr := rails(order)
+	for i := range tr {
+		tr[i].Row = rr[tr[i].Row]
+	}
+	return tr
+}
+
+// Unkey restores the rail number of each cell from its reading order.
+func unkeyGrid(g grid.Grid, order []int) grid.Grid {
+	if order == nil {
+		return g
+	}
+	rr := rails(order)
+	for i := range g {
+		g[i].Row = rr[g[i].Row]
+	}
 	return g
 }
 
 // Encipher a message.
 func (c *Cipher) Encipher(s string) (string, error) {
-	if c.Rows == 1 {
-		return s, nil
-	}
-
-	g := c.makegrid(utf8.RuneCountInString(s))
-	g.FillByRow(s)
-	return g.ReadByRow(), nil
+	out, _, err := c.EncipherTrace(s)
+	return out, err
 }
 
 // Decipher a message.
 func (c *Cipher) Decipher(s string) (string, error) {
-	if c.Rows == 1 {
-		return s, nil
+	out, _, err := c.DecipherTrace(s)
+	return out, err
+}
+
+// EncipherTrace enciphers a message and records the grid cell for each rune.
+func (c *Cipher) EncipherTrace(s string) (string, trace.Trace, error) {
+	order, err := c.order()
+	if err != nil {
+		return "", nil, err
+	}
+	s = c.pad(s)
+	g, err := c.makegrid(utf8.RuneCountInString(s), order)
+	if err != nil {
+		return "", nil, err
 	}
+	g.FillByRow(s)
+	return g.ReadByRow(), unkey(g.Trace(), order), nil
+}
 
-	g := c.makegrid(utf8.RuneCountInString(s))
+// DecipherTrace deciphers a message and records the grid cell for each rune.
+func (c *Cipher) DecipherTrace(s string) (string, trace.Trace, error) {
+	order, err := c.order()
+	if err != nil {
+		return "", nil, err
+	}
+	g, err := c.makegrid(utf8.RuneCountInString(s), order)
+	if err != nil {
+		return "", nil, err
+	}
 	g.FillByCol(s)
-	return g.ReadByCol(), nil
+	return g.ReadByCol(), unkey(g.Trace(), order), nil
 }
 
-// EnciphermentGrid returns the output tableau upon encipherment.
-func (c *Cipher) enciphermentGrid(s string) (string, error) {
-	if c.Rows == 1 {
-		return s, nil
+// Grid filled upon encipherment, with rails in their natural order.
+func (c *Cipher) encipherGrid(s string) (grid.Grid, error) {
+	order, err := c.order()
+	if err != nil {
+		return nil, err
+	}
+	s = c.pad(s)
+	g, err := c.makegrid(utf8.RuneCountInString(s), order)
+	if err != nil {
+		return nil, err
 	}
-
-	g := c.makegrid(utf8.RuneCountInString(s))
 	g.FillByRow(s)
+	return unkeyGrid(g, order), nil
+}
+
+// Grid filled upon decipherment, with rails in their natural order.
+func (c *Cipher) decipherGrid(s string) (grid.Grid, error) {
+	order, err := c.order()
+	if err != nil {
+		return nil, err
+	}
+	g, err := c.makegrid(utf8.RuneCountInString(s), order)
+	if err != nil {
+		return nil, err
+	}
+	g.FillByCol(s)
+	return unkeyGrid(g, order), nil
+}
+
+// EnciphermentGrid returns the output tableau upon encipherment.
+func (c *Cipher) EnciphermentGrid(s string) (string, error) {
+	g, err := c.encipherGrid(s)
+	if err != nil {
+		return "", err
+	}
 	return g.Printable(), nil
 }
 
 // DeciphermentGrid returns the output tableau upon encipherment.
-func (c *Cipher) deciphermentGrid(s string) (string, error) {
-	if c.Rows == 1 {
-		return s, nil
+func (c *Cipher) DeciphermentGrid(s string) (string, error) {
+	g, err := c.decipherGrid(s)
+	if err != nil {
+		return "", err
 	}
-
-	g := c.makegrid(utf8.RuneCountInString(s))
-	g.FillByCol(s)
 	return g.Printable(), nil
 }
+
+// EnciphermentMatrix returns the output tableau upon encipherment as a matrix, with empty strings for empty cells.
+func (c *Cipher) EnciphermentMatrix(s string) ([][]string, error) {
+	g, err := c.encipherGrid(s)
+	if err != nil {
+		return nil, err
+	}
+	return g.Matrix(), nil
+}
+
+// DeciphermentMatrix returns the output tableau upon decipherment as a matrix, with empty strings for empty cells.
+func (c *Cipher) DeciphermentMatrix(s string) ([][]string, error) {
+	g, err := c.decipherGrid(s)
+	if err != nil {
+		return nil, err
+	}
+	return g.Matrix(), nil
+}
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -136,9 +137,48 @@ func TestCipherReversibility(t *testing.T) {
 	}
 }
 
-func ExampleCipher_enciphermentGrid() {
+func TestCipher_Padding(t *testing.T) {
+	tables := []struct {
+		Cipher
+		Input  string
+		Output string
+	}{
+		{Cipher{Rows: 3, Padding: 'X'}, "WEAREDISCOVEREDFLEEATONCE", "WECRLTEERDSOEEFEAOCXXAIVDENX"},
+		{Cipher{Rows: 3, Padding: 'X'}, "WEAREDISCOVEREDFLEEATONC", "WECRLTERDSOEEFEAOCAIVDEN"},
+		{Cipher{Rows: 4, Offset: 2, Padding: 'X', Key: "KEYS"}, "WEAREDISCOVEREDFLEEATONCE", "RDOEFEOCXEVLNESEAXWAICRDETEX"},
+	}
+
+	for _, table := range tables {
+		if out, err := table.Encipher(table.Input); err != nil {
+			t.Error("Could not encipher:", err)
+		} else if out != table.Output {
+			t.Errorf("Expected %q to encipher to %q, but instead got %q", table.Input, table.Output, out)
+		}
+		if out, err := table.Decipher(table.Output); err != nil {
+			t.Error("Could not decipher:", err)
+		} else if !strings.HasPrefix(out, table.Input) || strings.Trim(out[len(table.Input):], "X") != "" {
+			t.Errorf("Expected %q to decipher to %q with nulls, but instead got %q", table.Output, table.Input, out)
+		}
+	}
+}
+
+func TestCipher_errors(t *testing.T) {
+	tables := []Cipher{
+		{Rows: 0},
+		{Rows: 3, Offset: (-1)},
+		{Rows: 3, Key: "ABCD"},
+	}
+
+	for _, c := range tables {
+		if _, err := c.Encipher("HELLO"); err == nil {
+			t.Errorf("Expected error for cipher %+v", c)
+		}
+	}
+}
+
+func ExampleCipher_EnciphermentGrid() {
 	c := Cipher{Rows: 3}
-	out, err := c.enciphermentGrid("WEAREDISCOVEREDFLEEATONCE")
+	out, err := c.EnciphermentGrid("WEAREDISCOVEREDFLEEATONCE")
 	if err != nil {
 		fmt.Println("Error:", err)
 	}
@@ -150,9 +190,24 @@ func ExampleCipher_enciphermentGrid() {
 	//   A   I   V   D   E   N
 }
 
-func ExampleCipher_deciphermentGrid() {
+func ExampleCipher_EnciphermentGrid_redefence() {
+	c := Cipher{Rows: 4, Offset: 2, Padding: 'X', Key: "KEYS"}
+	out, err := c.EnciphermentGrid("WEAREDISCOVEREDFLEEATONCE")
+	if err != nil {
+		fmt.Println("Error:", err)
+	}
+	fmt.Println(out)
+
+	// Output:
+	//     E     V     L     N
+	//    R D   O E   F E   O C   X
+	// W A   I C   R D   E T   E X
+	//  E     S     E     A     X
+}
+
+func ExampleCipher_DeciphermentGrid() {
 	c := Cipher{Rows: 3}
-	out, err := c.deciphermentGrid("WECRLTEERDSOEEFEAOCAIVDEN")
+	out, err := c.DeciphermentGrid("WECRLTEERDSOEEFEAOCAIVDEN")
 	if err != nil {
 		fmt.Println("Error:", err)
 	}
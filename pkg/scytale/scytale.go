@@ -0,0 +1,200 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scytale
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/merenbach/goldbug/internal/grid"
+	"github.com/merenbach/goldbug/internal/trace"
+)
+
+// A Cipher implements the scytale (or skytale) cipher.
+// Turns is the number of runes written around the rod in each turn of the strip.
+type Cipher struct {
+	Turns int
+}
+
+// Makegrid creates a grid and numbers its cells.
+func (c *Cipher) makegrid(n int) (grid.Grid, error) {
+	if c.Turns < 1 {
+		return nil, errors.New("Turns must be positive")
+	}
+
+	g := make(grid.Grid, n)
+	for i := range g {
+		g[i].Col = i % c.Turns
+		g[i].Row = i / c.Turns
+	}
+	return g, nil
+}
+
+// Encipher a message.
+func (c *Cipher) Encipher(s string) (string, error) {
+	out, _, err := c.EncipherTrace(s)
+	return out, err
+}
+
+// Decipher a message.
+func (c *Cipher) Decipher(s string) (string, error) {
+	out, _, err := c.DecipherTrace(s)
+	return out, err
+}
+
+// EncipherTrace enciphers a message and records the grid cell for each rune.
+func (c *Cipher) EncipherTrace(s string) (string, trace.Trace, error) {
+	g, err := c.makegrid(utf8.RuneCountInString(s))
+	if err != nil {
+		return "", nil, err
+	}
+	g.FillByCol(s)
+	return g.ReadByCol(), g.Trace(), nil
+}
+
+// DecipherTrace deciphers a message and records the grid cell for each rune.
+func (c *Cipher) DecipherTrace(s string) (string, trace.Trace, error) {
+	g, err := c.makegrid(utf8.RuneCountInString(s))
+	if err != nil {
+		return "", nil, err
+	}
+	g.FillByRow(s)
+	return g.ReadByRow(), g.Trace(), nil
+}
+
+// Grid filled upon encipherment.
+func (c *Cipher) encipherGrid(s string) (grid.Grid, error) {
+	g, err := c.makegrid(utf8.RuneCountInString(s))
+	if err != nil {
+		return nil, err
+	}
+	g.FillByCol(s)
+	return g, nil
+}
+
+// Grid filled upon decipherment.
+func (c *Cipher) decipherGrid(s string) (grid.Grid, error) {
+	g, err := c.makegrid(utf8.RuneCountInString(s))
+	if err != nil {
+		return nil, err
+	}
+	g.FillByRow(s)
+	return g, nil
+}
+
+// EnciphermentGrid returns the output tableau upon encipherment.
+func (c *Cipher) EnciphermentGrid(s string) (string, error) {
+	g, err := c.encipherGrid(s)
+	if err != nil {
+		return "", err
+	}
+	return g.Printable(), nil
+}
+
+// DeciphermentGrid returns the output tableau upon encipherment.
+func (c *Cipher) DeciphermentGrid(s string) (string, error) {
+	g, err := c.decipherGrid(s)
+	if err != nil {
+		return "", err
+	}
+	return g.Printable(), nil
+}
+
+// EnciphermentMatrix returns the output tableau upon encipherment as a matrix, with empty strings for empty cells.
+func (c *Cipher) EnciphermentMatrix(s string) ([][]string, error) {
+	g, err := c.encipherGrid(s)
+	if err != nil {
+		return nil, err
+	}
+	return g.Matrix(), nil
+}
+
+// DeciphermentMatrix returns the output tableau upon decipherment as a matrix, with empty strings for empty cells.
+func (c *Cipher) DeciphermentMatrix(s string) ([][]string, error) {
+	g, err := c.decipherGrid(s)
+	if err != nil {
+		return nil, err
+	}
+	return g.Matrix(), nil
+}
+
+// Number of rows of the wrap grid shown in a tableau.
+const tableauRows = 3
+
+// Positions of plaintext runes in the first rows of the wrap grid, numbered from one.
+// Ciphertext is read down each column in turn.
+func (c *Cipher) positions() ([][]int, error) {
+	g, err := c.makegrid(tableauRows * c.Turns)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([][]int, tableauRows)
+	for i := range out {
+		out[i] = make([]int, c.Turns)
+	}
+	for i, cell := range g {
+		out[cell.Row][cell.Col] = i + 1
+	}
+	return out, nil
+}
+
+// Tableau of plaintext positions in the first rows of the wrap grid.
+func (c *Cipher) Tableau() (string, error) {
+	pp, err := c.positions()
+	if err != nil {
+		return "", err
+	}
+
+	w := len(strconv.Itoa(tableauRows * c.Turns))
+	var b strings.Builder
+	b.WriteString(strings.Repeat(" ", w+1))
+	for j := range pp[0] {
+		fmt.Fprintf(&b, " %*d", w, j+1)
+	}
+	b.WriteString("\n" + strings.Repeat(" ", w) + " +" + strings.Repeat("-", (w+1)*c.Turns))
+	for i, row := range pp {
+		fmt.Fprintf(&b, "\n%*d |", w, i+1)
+		for _, n := range row {
+			fmt.Fprintf(&b, " %*d", w, n)
+		}
+	}
+	return b.String(), nil
+}
+
+// TableauMatrix of plaintext positions, with row numbers in the first column and column numbers in the first row.
+// The top left cell is an empty string.
+func (c *Cipher) TableauMatrix() ([][]string, error) {
+	pp, err := c.positions()
+	if err != nil {
+		return nil, err
+	}
+
+	out := [][]string{{""}}
+	for j := range pp[0] {
+		out[0] = append(out[0], strconv.Itoa(j+1))
+	}
+	for i, row := range pp {
+		line := []string{strconv.Itoa(i + 1)}
+		for _, n := range row {
+			line = append(line, strconv.Itoa(n))
+		}
+		out = append(out, line)
+	}
+	return out, nil
+}
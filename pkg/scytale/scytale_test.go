@@ -0,0 +1,185 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scytale
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCipher_Encipher(t *testing.T) {
+	testdata, err := ioutil.ReadFile(filepath.Join("testdata", "cipher_encipher.json"))
+	if err != nil {
+		t.Fatal("Could not read testdata fixture:", err)
+	}
+
+	var tables []struct {
+		Cipher
+
+		Input  string
+		Output string
+	}
+	if err := json.Unmarshal(testdata, &tables); err != nil {
+		t.Fatal("Could not unmarshal testdata:", err)
+	}
+
+	for _, table := range tables {
+		if out, err := table.Encipher(table.Input); err != nil {
+			t.Error("Could not encipher:", err)
+		} else if out != table.Output {
+			t.Errorf("Expected %q to encipher to %q, but instead got %q", table.Input, table.Output, out)
+		}
+	}
+}
+
+func TestCipher_Decipher(t *testing.T) {
+	testdata, err := ioutil.ReadFile(filepath.Join("testdata", "cipher_decipher.json"))
+	if err != nil {
+		t.Fatal("Could not read testdata fixture:", err)
+	}
+
+	var tables []struct {
+		Cipher
+
+		Input  string
+		Output string
+	}
+	if err := json.Unmarshal(testdata, &tables); err != nil {
+		t.Fatal("Could not unmarshal testdata:", err)
+	}
+
+	for _, table := range tables {
+		if out, err := table.Decipher(table.Input); err != nil {
+			t.Error("Could not decipher:", err)
+		} else if out != table.Output {
+			t.Errorf("Expected %q to decipher to %q, but instead got %q", table.Input, table.Output, out)
+		}
+	}
+}
+
+func TestCipherReversibility(t *testing.T) {
+	base := "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+	baseRunes := []rune(base)
+
+	for msglen := 1; msglen < len(baseRunes); msglen++ {
+		for turns := 1; turns < len(baseRunes); turns++ {
+			msg := string(baseRunes[:msglen])
+			c := Cipher{Turns: turns}
+
+			enciphered, err := c.Encipher(msg)
+			if err != nil {
+				t.Error("Error:", err)
+			}
+			deciphered, err := c.Decipher(enciphered)
+			if err != nil {
+				t.Error("Error:", err)
+			}
+			if deciphered != msg {
+				t.Errorf("Expected encipherment-then-decipherment of %q with %d turns to be %q but got %q", enciphered, turns, msg, deciphered)
+			}
+		}
+	}
+}
+
+func TestCipher_EncipherTrace(t *testing.T) {
+	c := Cipher{Turns: 2}
+
+	out, tr, err := c.EncipherTrace("ABCD")
+	if err != nil {
+		t.Fatal("Could not encipher:", err)
+	}
+	if out != "ACBD" {
+		t.Errorf("Expected %q, but got %q", "ACBD", out)
+	}
+
+	expected := []struct {
+		input rune
+		row   int
+		col   int
+	}{
+		{'A', 0, 0},
+		{'B', 0, 1},
+		{'C', 1, 0},
+		{'D', 1, 1},
+	}
+	if len(tr) != len(expected) {
+		t.Fatalf("Expected %d events, but got %d", len(expected), len(tr))
+	}
+	for i, e := range expected {
+		if tr[i].Index != i || tr[i].Input != e.input || tr[i].Row != e.row || tr[i].Col != e.col {
+			t.Errorf("Expected event %d to place %q at (%d, %d), but got %+v", i, e.input, e.row, e.col, tr[i])
+		}
+	}
+}
+
+func TestCipher_errors(t *testing.T) {
+	c := Cipher{}
+	if _, err := c.Encipher("HELLO"); err == nil {
+		t.Error("Expected error for zero turns")
+	}
+}
+
+func ExampleCipher_EnciphermentGrid() {
+	c := Cipher{Turns: 5}
+	out, err := c.EnciphermentGrid("HELPMEIAMUNDERATTACK")
+	if err != nil {
+		fmt.Println("Error:", err)
+	}
+	fmt.Println(out)
+
+	// Output:
+	// HELPM
+	// EIAMU
+	// NDERA
+	// TTACK
+}
+
+func ExampleCipher_Tableau() {
+	c := Cipher{Turns: 5}
+	out, err := c.Tableau()
+	if err != nil {
+		fmt.Println("Error:", err)
+	}
+	fmt.Println(out)
+
+	// Output:
+	//      1  2  3  4  5
+	//    +---------------
+	//  1 |  1  2  3  4  5
+	//  2 |  6  7  8  9 10
+	//  3 | 11 12 13 14 15
+}
+
+func TestCipher_TableauMatrix(t *testing.T) {
+	c := Cipher{Turns: 2}
+	out, err := c.TableauMatrix()
+	if err != nil {
+		t.Fatal("Could not render tableau:", err)
+	}
+
+	expected := [][]string{
+		{"", "1", "2"},
+		{"1", "1", "2"},
+		{"2", "3", "4"},
+		{"3", "5", "6"},
+	}
+	if !reflect.DeepEqual(out, expected) {
+		t.Errorf("Expected %q, but got %q", expected, out)
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package solitaire
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Suits in bridge order.
+const suits = "CDHS"
+
+// Ranks within a suit.
+var ranks = [...]string{"A", "2", "3", "4", "5", "6", "7", "8", "9", "10", "J", "Q", "K"}
+
+// A Card is numbered from 1 to 52 in bridge order, from the ace of clubs to the king of spades, with the jokers numbered 53 and 54.
+type Card int
+
+// The jokers.
+const (
+	JokerA Card = 53
+	JokerB Card = 54
+)
+
+// Number of cards in a deck.
+const deckSize = 54
+
+// Value of this card for counting, at which both jokers count as 53.
+func (c Card) value() int {
+	if c == JokerB {
+		return int(JokerA)
+	}
+	return int(c)
+}
+
+// String names a card by rank and suit, such as "10H" or "KS", or names a joker "A" or "B".
+func (c Card) String() string {
+	switch {
+	case c == JokerA:
+		return "A"
+	case c == JokerB:
+		return "B"
+	case c >= 1 && c <= 52:
+		return ranks[(c-1)%13] + string(suits[(c-1)/13])
+	}
+	return fmt.Sprintf("Card(%d)", int(c))
+}
+
+// ParseCard parses the name of a card.
+func ParseCard(s string) (Card, error) {
+	s = strings.ToUpper(s)
+	switch s {
+	case "A":
+		return JokerA, nil
+	case "B":
+		return JokerB, nil
+	}
+	if len(s) >= 2 {
+		if suit := strings.IndexByte(suits, s[len(s)-1]); suit != (-1) {
+			for i, r := range ranks {
+				if r == s[:len(s)-1] {
+					return Card(suit*13 + i + 1), nil
+				}
+			}
+		}
+	}
+	return 0, fmt.Errorf("Invalid card %q", s)
+}
+
+// MarshalJSON renders a card by name.
+func (c Card) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.String())
+}
+
+// UnmarshalJSON parses a card by name or by number.
+func (c *Card) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		v, err := strconv.Atoi(string(b))
+		if err != nil {
+			return fmt.Errorf("Invalid card %s", b)
+		}
+		*c = Card(v)
+		return nil
+	}
+	v, err := ParseCard(s)
+	if err != nil {
+		return err
+	}
+	*c = v
+	return nil
+}
+
+// A Deck lists cards from top to bottom.
+type Deck []Card
+
+// NewDeck returns a deck in bridge order, with joker A and then joker B at the bottom.
+func NewDeck() Deck {
+	out := make(Deck, deckSize)
+	for i := range out {
+		out[i] = Card(i + 1)
+	}
+	return out
+}
+
+// Validate that a deck holds each card exactly once.
+func (d Deck) Validate() error {
+	if len(d) != deckSize {
+		return fmt.Errorf("Deck must have %d cards", deckSize)
+	}
+	seen := make(map[Card]bool)
+	for _, c := range d {
+		if c < 1 || c > deckSize {
+			return errors.New("Deck contains an invalid card")
+		}
+		if seen[c] {
+			return fmt.Errorf("Deck contains %s more than once", c)
+		}
+		seen[c] = true
+	}
+	return nil
+}
+
+// Index of a card in this deck.
+func (d Deck) index(c Card) int {
+	for i, o := range d {
+		if o == c {
+			return i
+		}
+	}
+	return (-1)
+}
+
+// Move a card down by n places, treating the deck as circular except that a card never becomes the top card.
+func (d Deck) move(c Card, n int) {
+	i := d.index(c)
+	for ; n > 0; n-- {
+		j := i + 1
+		if j == len(d) {
+			// Passing the bottom, the card goes just below the top card.
+			copy(d[2:], d[1:i])
+			j = 1
+		} else {
+			d[i] = d[j]
+		}
+		d[j] = c
+		i = j
+	}
+}
+
+// TripleCut swaps the cards above the first joker with the cards below the second.
+func (d Deck) tripleCut() {
+	a, b := d.index(JokerA), d.index(JokerB)
+	if a > b {
+		a, b = b, a
+	}
+	out := make(Deck, 0, len(d))
+	out = append(append(append(out, d[b+1:]...), d[a:b+1]...), d[:a]...)
+	copy(d, out)
+}
+
+// CountCut moves n cards from the top to just above the bottom card.
+func (d Deck) countCut(n int) {
+	last := len(d) - 1
+	if n >= last {
+		return
+	}
+	out := make(Deck, 0, len(d))
+	out = append(append(append(out, d[n:last]...), d[:n]...), d[last])
+	copy(d, out)
+}
+
+// Step the deck through one round of moves, ending with a count cut by the value of the bottom card.
+func (d Deck) step() {
+	d.move(JokerA, 1)
+	d.move(JokerB, 2)
+	d.tripleCut()
+	d.countCut(d[len(d)-1].value())
+}
+
+// Next keystream value, from 1 to 26.
+func (d Deck) next() int {
+	for {
+		d.step()
+		if c := d[d[0].value()]; c != JokerA && c != JokerB {
+			return (int(c)-1)%26 + 1
+		}
+	}
+}
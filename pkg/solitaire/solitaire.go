@@ -0,0 +1,184 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package solitaire implements Bruce Schneier's Solitaire cipher, also known as Pontifex, which draws its keystream from a deck of cards.
+package solitaire
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/merenbach/goldbug/internal/normalize"
+	"github.com/merenbach/goldbug/internal/trace"
+)
+
+// Alphabet for Solitaire, whose letters count from 1 for A to 26 for Z.
+const Alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// Cipher implements a Solitaire cipher.
+// Deck is the starting order of the deck, which defaults to bridge order, and Key is a passphrase with which to key it further.
+// Padding, if set, completes the final group of five letters of plaintext.
+type Cipher struct {
+	Deck       Deck
+	Key        string
+	Padding    rune
+	Strict     bool
+	Normalizer normalize.Normalizer
+}
+
+// KeyedDeck returns the deck as keyed, ready to produce a keystream.
+// Keying steps the deck once for each letter of the passphrase, following each step with a further count cut by the value of the letter.
+func (c *Cipher) KeyedDeck() (Deck, error) {
+	d := NewDeck()
+	if c.Deck != nil {
+		if err := c.Deck.Validate(); err != nil {
+			return nil, err
+		}
+		d = append(Deck{}, c.Deck...)
+	}
+
+	for _, r := range strings.ToUpper(c.Key) {
+		if i := strings.IndexRune(Alphabet, r); i != (-1) {
+			d.step()
+			d.countCut(i + 1)
+		}
+	}
+	return d, nil
+}
+
+// Keystream returns the first n keystream values, each from 1 to 26.
+func (c *Cipher) Keystream(n int) ([]int, error) {
+	d, err := c.KeyedDeck()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]int, n)
+	for i := range out {
+		out[i] = d.next()
+	}
+	return out, nil
+}
+
+// Pad a message with the padding rune to a multiple of five letters.
+func (c *Cipher) pad(s string, present func(rune) bool) string {
+	if c.Padding == 0 {
+		return s
+	}
+	n := 0
+	for _, r := range s {
+		if present(c.Normalizer.Fold(r, present)) {
+			n++
+		}
+	}
+	if n%5 == 0 {
+		return s
+	}
+	return s + strings.Repeat(string(c.Padding), 5-n%5)
+}
+
+// Transcode a message, adding keystream values in encipherment and subtracting them in decipherment.
+func (c *Cipher) transcode(s string, sign int, tr *trace.Trace) (string, error) {
+	if c.Padding != 0 && !strings.ContainsRune(Alphabet, c.Padding) {
+		return "", fmt.Errorf("Padding %q must be a letter of the alphabet", c.Padding)
+	}
+	d, err := c.KeyedDeck()
+	if err != nil {
+		return "", err
+	}
+
+	present := func(r rune) bool {
+		return strings.ContainsRune(Alphabet, r)
+	}
+	s = c.Normalizer.Normalize(s)
+	if sign > 0 {
+		s = c.pad(s, present)
+	}
+
+	var b strings.Builder
+	for i, r := range []rune(s) {
+		f := c.Normalizer.Fold(r, present)
+		j := strings.IndexRune(Alphabet, f)
+		if j == (-1) {
+			if !c.Strict {
+				tr.Pass(i, r, 0)
+				b.WriteRune(r)
+			} else {
+				tr.Skip(i, r, 0)
+			}
+			continue
+		}
+
+		k := d.next()
+		o := c.Normalizer.Restore(r, f, rune(Alphabet[((j+sign*k)%26+26)%26]))
+		tr.Add(trace.Event{Index: i, Input: r, Key: rune(Alphabet[k-1]), Row: (-1), Col: (-1), Output: o, Action: trace.Transcoded})
+		b.WriteRune(o)
+	}
+	return b.String(), nil
+}
+
+// Encipher a message.
+func (c *Cipher) Encipher(s string) (string, error) {
+	return c.transcode(s, 1, nil)
+}
+
+// Decipher a message.
+func (c *Cipher) Decipher(s string) (string, error) {
+	return c.transcode(s, (-1), nil)
+}
+
+// EncipherTrace enciphers a message and records how each rune was produced.
+// The key of each event is the letter for its keystream value.
+func (c *Cipher) EncipherTrace(s string) (string, trace.Trace, error) {
+	var tr trace.Trace
+	out, err := c.transcode(s, 1, &tr)
+	return out, tr, err
+}
+
+// DecipherTrace deciphers a message and records how each rune was produced.
+func (c *Cipher) DecipherTrace(s string) (string, trace.Trace, error) {
+	var tr trace.Trace
+	out, err := c.transcode(s, (-1), &tr)
+	return out, tr, err
+}
+
+// TableauMatrix shows the keyed deck from top to bottom in rows of 13 cards.
+func (c *Cipher) TableauMatrix() ([][]string, error) {
+	d, err := c.KeyedDeck()
+	if err != nil {
+		return nil, err
+	}
+
+	var out [][]string
+	for i, card := range d {
+		if i%13 == 0 {
+			out = append(out, nil)
+		}
+		out[len(out)-1] = append(out[len(out)-1], card.String())
+	}
+	return out, nil
+}
+
+// Tableau shows the keyed deck from top to bottom.
+func (c *Cipher) Tableau() (string, error) {
+	mm, err := c.TableauMatrix()
+	if err != nil {
+		return "", err
+	}
+
+	lines := make([]string, len(mm))
+	for i, row := range mm {
+		lines[i] = strings.Join(row, " ")
+	}
+	return strings.Join(lines, "\n"), nil
+}
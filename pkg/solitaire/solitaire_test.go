@@ -0,0 +1,173 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package solitaire
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCipher_Encipher(t *testing.T) {
+	testdata, err := ioutil.ReadFile(filepath.Join("testdata", "cipher_encipher.json"))
+	if err != nil {
+		t.Fatal("Could not read testdata fixture:", err)
+	}
+
+	var tables []struct {
+		Cipher
+
+		Input  string
+		Output string
+	}
+	if err := json.Unmarshal(testdata, &tables); err != nil {
+		t.Fatal("Could not unmarshal testdata:", err)
+	}
+
+	for _, table := range tables {
+		if out, err := table.Encipher(table.Input); err != nil {
+			t.Error("Could not encipher:", err)
+		} else if out != table.Output {
+			t.Errorf("Expected %q to encipher to %q, but instead got %q", table.Input, table.Output, out)
+		}
+	}
+}
+
+func TestCipher_Decipher(t *testing.T) {
+	testdata, err := ioutil.ReadFile(filepath.Join("testdata", "cipher_decipher.json"))
+	if err != nil {
+		t.Fatal("Could not read testdata fixture:", err)
+	}
+
+	var tables []struct {
+		Cipher
+
+		Input  string
+		Output string
+	}
+	if err := json.Unmarshal(testdata, &tables); err != nil {
+		t.Fatal("Could not unmarshal testdata:", err)
+	}
+
+	for _, table := range tables {
+		if out, err := table.Decipher(table.Input); err != nil {
+			t.Error("Could not decipher:", err)
+		} else if out != table.Output {
+			t.Errorf("Expected %q to decipher to %q, but instead got %q", table.Input, table.Output, out)
+		}
+	}
+}
+
+func TestCipher_Keystream(t *testing.T) {
+	c := Cipher{}
+	out, err := c.Keystream(10)
+	if err != nil {
+		t.Fatal("Could not generate keystream:", err)
+	}
+	if expected := []int{4, 23, 10, 24, 8, 25, 18, 6, 4, 7}; !reflect.DeepEqual(out, expected) {
+		t.Errorf("Expected keystream %v, but got %v", expected, out)
+	}
+}
+
+func TestCipher_Padding(t *testing.T) {
+	c := Cipher{Key: "CRYPTONOMICON", Padding: 'X'}
+	if out, err := c.Encipher("SOLITAIRE"); err != nil {
+		t.Error("Could not encipher:", err)
+	} else if out != "KIRAKSFJAN" {
+		t.Errorf("Expected %q, but got %q", "KIRAKSFJAN", out)
+	}
+
+	c.Padding = '!'
+	if _, err := c.Encipher("SOLITAIRE"); err == nil {
+		t.Error("Expected error for padding outside the alphabet")
+	}
+}
+
+func TestDeck_JSON(t *testing.T) {
+	c := Cipher{Key: "CRYPTONOMICON"}
+	d, err := c.KeyedDeck()
+	if err != nil {
+		t.Fatal("Could not key deck:", err)
+	}
+
+	bb, err := json.Marshal(d)
+	if err != nil {
+		t.Fatal("Could not marshal deck:", err)
+	}
+	var e Deck
+	if err := json.Unmarshal(bb, &e); err != nil {
+		t.Fatal("Could not unmarshal deck:", err)
+	}
+	if !reflect.DeepEqual(d, e) {
+		t.Errorf("Expected deck %v to survive JSON, but got %v", d, e)
+	}
+
+	c = Cipher{Deck: e}
+	if out, err := c.Encipher("SOLITAIREX"); err != nil {
+		t.Error("Could not encipher:", err)
+	} else if out != "KIRAKSFJAN" {
+		t.Errorf("Expected %q, but got %q", "KIRAKSFJAN", out)
+	}
+
+	if err := json.Unmarshal([]byte(`["AC", 2, "10H", "b"]`), &e); err != nil {
+		t.Error("Could not unmarshal deck:", err)
+	} else if expected := (Deck{1, 2, 36, JokerB}); !reflect.DeepEqual(e, expected) {
+		t.Errorf("Expected deck %v, but got %v", expected, e)
+	}
+	if err := json.Unmarshal([]byte(`["1C"]`), &e); err == nil {
+		t.Error("Expected error for invalid card")
+	}
+}
+
+func TestDeck_Validate(t *testing.T) {
+	d := NewDeck()
+	if err := d.Validate(); err != nil {
+		t.Error("Unexpected error:", err)
+	}
+
+	tables := []Deck{
+		d[1:],
+		append(Deck{JokerA}, d[1:]...),
+		append(Deck{0}, d[1:]...),
+	}
+	for _, table := range tables {
+		if err := table.Validate(); err == nil {
+			t.Errorf("Expected error validating deck %v", table)
+		}
+		c := Cipher{Deck: table}
+		if _, err := c.Encipher("HELLO"); err == nil {
+			t.Errorf("Expected error enciphering with deck %v", table)
+		}
+	}
+}
+
+func ExampleCipher_Tableau() {
+	c := Cipher{Key: "F"}
+	out, err := c.Tableau()
+	if err != nil {
+		fmt.Println("Error:", err)
+	}
+	fmt.Println(out)
+
+	// Output:
+	// 8C 9C 10C JC QC KC AD 2D 3D 4D 5D 6D 7D
+	// 8D 9D 10D JD QD KD AH 2H 3H 4H 5H 6H 7H
+	// 8H 9H 10H JH QH KH AS 2S 3S 4S 5S 6S 7S
+	// 8S 9S 10S JS QS KS A B 2C 3C 4C 5C 6C
+	// 7C AC
+}
@@ -15,13 +15,73 @@
 package trithemius
 
 import (
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/merenbach/goldbug/internal/normalize"
 	"github.com/merenbach/goldbug/internal/pasc"
+	"github.com/merenbach/goldbug/internal/stream"
+	"github.com/merenbach/goldbug/internal/trace"
+)
+
+// A Restart determines where the key progression starts over.
+type Restart uint8
+
+const (
+	// Continuous never restarts the key progression.
+	Continuous Restart = iota
+
+	// PerWord restarts the key progression after each whitespace rune.
+	PerWord
+
+	// PerLine restarts the key progression after each newline.
+	PerLine
 )
 
+// Boundary tests whether the key progression restarts after a rune.
+func (r Restart) boundary(o rune) bool {
+	switch r {
+	case PerWord:
+		return unicode.IsSpace(o)
+	case PerLine:
+		return o == '\n'
+	}
+	return false
+}
+
+// Segments of a string, each ending just after a boundary rune.
+func (r Restart) segments(s string) []string {
+	if r == Continuous {
+		return []string{s}
+	}
+
+	var out []string
+	start := 0
+	for i, o := range s {
+		if r.boundary(o) {
+			end := i + utf8.RuneLen(o)
+			out = append(out, s[start:end])
+			start = end
+		}
+	}
+	if start < len(s) {
+		out = append(out, s[start:])
+	}
+	return out
+}
+
 // Cipher implements a Trithemius cipher.
+// The key progresses through the alphabet from the rune at Offset, advancing by Step positions for each rune transcoded.
+// A Step of zero is treated as one, and a negative Step progresses backward.
 type Cipher struct {
-	Alphabet string
-	Strict   bool
+	Alphabet   string
+	Offset     int
+	Step       int
+	Restart    Restart
+	Strict     bool
+	Normalizer normalize.Normalizer
 }
 
 func (c *Cipher) maketableau() (*pasc.TabulaRecta, error) {
@@ -35,16 +95,95 @@ func (c *Cipher) maketableau() (*pasc.TabulaRecta, error) {
 		CtAlphabet:  alphabet,
 		KeyAlphabet: alphabet,
 		Strict:      c.Strict,
+		Normalizer:  c.Normalizer,
 	}, nil
 }
 
+// Key for one full cycle of the progression through the key alphabet.
+func (c *Cipher) key(t *pasc.TabulaRecta) string {
+	step := c.Step
+	if step == 0 {
+		step = 1
+	}
+
+	a := []rune(t.KeyAlphabet)
+	n := len(a)
+	out := make([]rune, n)
+	for i := range out {
+		out[i] = a[((c.Offset+i*step)%n+n)%n]
+	}
+	return string(out)
+}
+
+// Transform a message in portions, starting the key progression over after each boundary.
+// A trace, if given, receives events indexed from the start of the message rather than from each restart.
+func (c *Cipher) transform(next func() (pasc.Transform, error), tr *trace.Trace) (pasc.Transform, error) {
+	f, err := next()
+	if err != nil {
+		return nil, err
+	}
+
+	// Runes consumed before the current progression, and during it
+	offset, consumed := 0, 0
+
+	return func(s string) (string, error) {
+		var out strings.Builder
+		for _, seg := range c.Restart.segments(s) {
+			n := 0
+			if tr != nil {
+				n = len(*tr)
+			}
+			o, err := f(seg)
+			if err != nil {
+				return "", err
+			}
+			out.WriteString(o)
+
+			if tr != nil {
+				for i := n; i < len(*tr); i++ {
+					(*tr)[i].Index += offset
+				}
+				consumed += utf8.RuneCountInString(c.Normalizer.Normalize(seg))
+			}
+
+			if r, _ := utf8.DecodeLastRuneInString(seg); c.Restart.boundary(r) {
+				if f, err = next(); err != nil {
+					return "", err
+				}
+				offset, consumed = offset+consumed, 0
+			}
+		}
+		return out.String(), nil
+	}, nil
+}
+
+// Encipherer returns a transform to encipher a message in portions.
+func (c *Cipher) encipherer(t *pasc.TabulaRecta) (pasc.Transform, error) {
+	k := c.key(t)
+	return c.transform(func() (pasc.Transform, error) {
+		return t.Encipherer(k, nil)
+	}, t.Trace)
+}
+
+// Decipherer returns a transform to decipher a message in portions.
+func (c *Cipher) decipherer(t *pasc.TabulaRecta) (pasc.Transform, error) {
+	k := c.key(t)
+	return c.transform(func() (pasc.Transform, error) {
+		return t.Decipherer(k, nil)
+	}, t.Trace)
+}
+
 // Encipher a message.
 func (c *Cipher) Encipher(s string) (string, error) {
 	t, err := c.maketableau()
 	if err != nil {
 		return "", err
 	}
-	return t.Encipher(s, t.KeyAlphabet, nil)
+	f, err := c.encipherer(t)
+	if err != nil {
+		return "", err
+	}
+	return f(s)
 }
 
 // Decipher a message.
@@ -53,14 +192,130 @@ func (c *Cipher) Decipher(s string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return t.Decipher(s, t.KeyAlphabet, nil)
+	f, err := c.decipherer(t)
+	if err != nil {
+		return "", err
+	}
+	return f(s)
+}
+
+// EncipherTrace enciphers a message and records how each rune was produced.
+func (c *Cipher) EncipherTrace(s string) (string, trace.Trace, error) {
+	t, err := c.maketableau()
+	if err != nil {
+		return "", nil, err
+	}
+	var tr trace.Trace
+	t.Trace = &tr
+	f, err := c.encipherer(t)
+	if err != nil {
+		return "", nil, err
+	}
+	out, err := f(s)
+	return out, tr, err
+}
+
+// DecipherTrace deciphers a message and records how each rune was produced.
+func (c *Cipher) DecipherTrace(s string) (string, trace.Trace, error) {
+	t, err := c.maketableau()
+	if err != nil {
+		return "", nil, err
+	}
+	var tr trace.Trace
+	t.Trace = &tr
+	f, err := c.decipherer(t)
+	if err != nil {
+		return "", nil, err
+	}
+	out, err := f(s)
+	return out, tr, err
+}
+
+// NewEncipherWriter returns a writer that enciphers text before writing it to w.
+// Close the writer to flush any incomplete rune at the end of the text.
+func (c *Cipher) NewEncipherWriter(w io.Writer) (io.WriteCloser, error) {
+	t, err := c.maketableau()
+	if err != nil {
+		return nil, err
+	}
+	f, err := c.encipherer(t)
+	if err != nil {
+		return nil, err
+	}
+	return stream.NewWriter(w, stream.Transform(f)), nil
+}
+
+// NewDecipherReader returns a reader that deciphers text read from r.
+func (c *Cipher) NewDecipherReader(r io.Reader) (io.Reader, error) {
+	t, err := c.maketableau()
+	if err != nil {
+		return nil, err
+	}
+	f, err := c.decipherer(t)
+	if err != nil {
+		return nil, err
+	}
+	return stream.NewReader(r, stream.Transform(f)), nil
+}
+
+// A Compiled cipher holds precomputed lookup tables.
+// A Compiled cipher is immutable and safe for concurrent use.
+type Compiled struct {
+	t       *pasc.Compiled
+	key     string
+	restart Restart
+}
+
+// Compile this cipher.
+func (c *Cipher) Compile() (*Compiled, error) {
+	t, err := c.maketableau()
+	if err != nil {
+		return nil, err
+	}
+	ct, err := t.Compile()
+	if err != nil {
+		return nil, err
+	}
+	return &Compiled{t: ct, key: c.key(t), restart: c.Restart}, nil
+}
+
+// Transcode a message one segment at a time, starting the key progression over for each.
+func (c *Compiled) transcode(s string, f func(string, string, func(rune, rune) rune) (string, error)) (string, error) {
+	var out strings.Builder
+	for _, seg := range c.restart.segments(s) {
+		o, err := f(seg, c.key, nil)
+		if err != nil {
+			return "", err
+		}
+		out.WriteString(o)
+	}
+	return out.String(), nil
+}
+
+// Encipher a message.
+func (c *Compiled) Encipher(s string) (string, error) {
+	return c.transcode(s, c.t.Encipher)
+}
+
+// Decipher a message.
+func (c *Compiled) Decipher(s string) (string, error) {
+	return c.transcode(s, c.t.Decipher)
 }
 
 // Tableau for encipherment and decipherment.
-func (c *Cipher) tableau() (string, error) {
+func (c *Cipher) Tableau() (string, error) {
 	t, err := c.maketableau()
 	if err != nil {
 		return "", err
 	}
 	return t.Printable()
 }
+
+// TableauMatrix for encipherment and decipherment.
+func (c *Cipher) TableauMatrix() ([][]string, error) {
+	t, err := c.maketableau()
+	if err != nil {
+		return nil, err
+	}
+	return t.Matrix()
+}
@@ -72,9 +72,56 @@ func TestCipher_Decipher(t *testing.T) {
 	}
 }
 
-func ExampleCipher_tableau() {
+func TestCipher_Restart(t *testing.T) {
+	tables := []struct {
+		restart Restart
+		step    int
+		input   string
+		output  string
+	}{
+		{Continuous, 0, "HELLO, WORLD!\nHELLO AGAIN", "HFNOS, BUYTM!\nRPXYC PWRAG"},
+		{PerWord, 3, "HELLO, WORLD!\nHELLO AGAIN", "HHRUA, WRXUP!\nHHRUA AJGRZ"},
+		{PerLine, 0, "HELLO, WORLD!\nHELLO AGAIN", "HFNOS, BUYTM!\nHFNOS FMHQW"},
+	}
+
+	for _, table := range tables {
+		c := Cipher{Restart: table.restart, Step: table.step}
+		if out, err := c.Encipher(table.input); err != nil {
+			t.Error("Could not encipher:", err)
+		} else if out != table.output {
+			t.Errorf("Expected %q to encipher to %q, but instead got %q", table.input, table.output, out)
+		}
+		if out, err := c.Decipher(table.output); err != nil {
+			t.Error("Could not decipher:", err)
+		} else if out != table.input {
+			t.Errorf("Expected %q to decipher to %q, but instead got %q", table.output, table.input, out)
+		}
+
+		cc, err := c.Compile()
+		if err != nil {
+			t.Fatal("Could not compile:", err)
+		}
+		if out, err := cc.Encipher(table.input); err != nil {
+			t.Error("Could not encipher:", err)
+		} else if out != table.output {
+			t.Errorf("Expected compiled %q to encipher to %q, but instead got %q", table.input, table.output, out)
+		}
+
+		_, tr, err := c.EncipherTrace(table.input)
+		if err != nil {
+			t.Error("Could not encipher:", err)
+		}
+		for i, e := range tr {
+			if e.Index != i {
+				t.Errorf("Expected event %d to have index %d, but got %d", i, i, e.Index)
+			}
+		}
+	}
+}
+
+func ExampleCipher_Tableau() {
 	c := Cipher{}
-	out, err := c.tableau()
+	out, err := c.Tableau()
 	if err != nil {
 		fmt.Println("Error:", err)
 	}
@@ -0,0 +1,198 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package vernam implements Gilbert Vernam's teleprinter cipher, which adds a key tape to a message in ITA2 code.
+package vernam
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/merenbach/goldbug/internal/normalize"
+	"github.com/merenbach/goldbug/internal/trace"
+	"github.com/merenbach/goldbug/pkg/ita2"
+)
+
+// A Generator creates an iterator over pseudo-random numbers, such as the Iterator method of an LCG from internal/prng.
+type Generator func() (func() int, error)
+
+// Cipher implements a Vernam cipher.
+// The key tape comes from exactly one of Key, text that is encoded in ITA2 and repeated as a loop of tape; Pad, a one-time tape in tape notation at least as long as the message; or Generator, whose numbers are reduced to five bits.
+// Encipherment encodes the message in ITA2 and returns the enciphered codes in tape notation, while decipherment reverses the process.
+type Cipher struct {
+	Key        string
+	Pad        string
+	Generator  Generator `json:"-"`
+	Normalizer normalize.Normalizer
+}
+
+// Tape returns a function that supplies key codes in turn.
+func (c *Cipher) tape() (func() (ita2.Code, error), error) {
+	n := 0
+	for _, set := range []bool{c.Key != "", c.Pad != "", c.Generator != nil} {
+		if set {
+			n++
+		}
+	}
+	if n != 1 {
+		return nil, errors.New("Key tape must come from exactly one of a key, a pad or a generator")
+	}
+
+	switch {
+	case c.Key != "":
+		cc, err := ita2.Encode(c.Key)
+		if err != nil {
+			return nil, err
+		}
+		if len(cc) == 0 {
+			return nil, errors.New("Key must encode to at least one code")
+		}
+		i := 0
+		return func() (ita2.Code, error) {
+			k := cc[i%len(cc)]
+			i++
+			return k, nil
+		}, nil
+
+	case c.Pad != "":
+		cc, err := ita2.Parse(c.Pad)
+		if err != nil {
+			return nil, err
+		}
+		i := 0
+		return func() (ita2.Code, error) {
+			if i >= len(cc) {
+				return 0, errors.New("Pad is shorter than the message")
+			}
+			k := cc[i]
+			i++
+			return k, nil
+		}, nil
+	}
+
+	next, err := c.Generator()
+	if err != nil {
+		return nil, err
+	}
+	return func() (ita2.Code, error) {
+		return ita2.Code((next()%ita2.Size + ita2.Size) % ita2.Size), nil
+	}, nil
+}
+
+// Add the key tape to a message tape, recording an event for each code if a trace is given.
+func (c *Cipher) add(cc []ita2.Code, tr *trace.Trace) ([]ita2.Code, error) {
+	next, err := c.tape()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]ita2.Code, len(cc))
+	for i, m := range cc {
+		k, err := next()
+		if err != nil {
+			return nil, err
+		}
+		out[i] = m ^ k
+		tr.Add(trace.Event{Index: i, Input: rune(ita2.Tape[m]), Key: rune(ita2.Tape[k]), Row: int(k), Col: int(m), Output: rune(ita2.Tape[out[i]]), Action: trace.Transcoded})
+	}
+	return out, nil
+}
+
+// Encipher a message, recording an event for each code if a trace is given.
+func (c *Cipher) encipher(s string, tr *trace.Trace) (string, error) {
+	cc, err := ita2.Encode(c.Normalizer.Normalize(s))
+	if err != nil {
+		return "", err
+	}
+	out, err := c.add(cc, tr)
+	if err != nil {
+		return "", err
+	}
+	return ita2.Format(out), nil
+}
+
+// Decipher a message, recording an event for each code if a trace is given.
+// Whitespace in the enciphered tape is ignored.
+func (c *Cipher) decipher(s string, tr *trace.Trace) (string, error) {
+	cc, err := ita2.Parse(strings.Join(strings.Fields(s), ""))
+	if err != nil {
+		return "", err
+	}
+	out, err := c.add(cc, tr)
+	if err != nil {
+		return "", err
+	}
+	return ita2.Decode(out), nil
+}
+
+// Encipher a message.
+func (c *Cipher) Encipher(s string) (string, error) {
+	return c.encipher(s, nil)
+}
+
+// Decipher a message.
+func (c *Cipher) Decipher(s string) (string, error) {
+	return c.decipher(s, nil)
+}
+
+// EncipherTrace enciphers a message and records how each code was produced.
+// Events describe codes of the encoded message in tape notation, with the key code as the row and the message code as the column of the tableau.
+func (c *Cipher) EncipherTrace(s string) (string, trace.Trace, error) {
+	var tr trace.Trace
+	out, err := c.encipher(s, &tr)
+	return out, tr, err
+}
+
+// DecipherTrace deciphers a message and records how each code was produced.
+func (c *Cipher) DecipherTrace(s string) (string, trace.Trace, error) {
+	var tr trace.Trace
+	out, err := c.decipher(s, &tr)
+	return out, tr, err
+}
+
+// Labels that may appear in ciphertext, which are the characters of tape notation.
+func (c *Cipher) Labels() (string, error) {
+	return ita2.Tape, nil
+}
+
+// TableauMatrix for encipherment and decipherment, with message codes across the top and key codes down the side, all in tape notation.
+func (c *Cipher) TableauMatrix() ([][]string, error) {
+	header := append([]string{""}, strings.Split(ita2.Tape, "")...)
+	out := [][]string{header}
+	for k := 0; k < ita2.Size; k++ {
+		row := []string{string(ita2.Tape[k])}
+		for m := 0; m < ita2.Size; m++ {
+			row = append(row, string(ita2.Tape[m^k]))
+		}
+		out = append(out, row)
+	}
+	return out, nil
+}
+
+// Tableau for encipherment and decipherment.
+func (c *Cipher) Tableau() (string, error) {
+	mm, err := c.TableauMatrix()
+	if err != nil {
+		return "", err
+	}
+
+	lines := make([]string, 0, len(mm)+1)
+	for i, row := range mm {
+		lines = append(lines, row[0]+" | "+strings.Join(row[1:], " "))
+		if i == 0 {
+			lines = append(lines, "  +"+strings.Repeat("-", 2*ita2.Size))
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
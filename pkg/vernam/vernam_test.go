@@ -0,0 +1,150 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vernam
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/merenbach/goldbug/internal/prng"
+)
+
+func TestCipher_Encipher(t *testing.T) {
+	testdata, err := ioutil.ReadFile(filepath.Join("testdata", "cipher_encipher.json"))
+	if err != nil {
+		t.Fatal("Could not read testdata fixture:", err)
+	}
+
+	var tables []struct {
+		Cipher
+
+		Input  string
+		Output string
+	}
+	if err := json.Unmarshal(testdata, &tables); err != nil {
+		t.Fatal("Could not unmarshal testdata:", err)
+	}
+
+	for _, table := range tables {
+		if out, err := table.Encipher(table.Input); err != nil {
+			t.Error("Could not encipher:", err)
+		} else if out != table.Output {
+			t.Errorf("Expected %q to encipher to %q, but instead got %q", table.Input, table.Output, out)
+		}
+	}
+}
+
+func TestCipher_Decipher(t *testing.T) {
+	testdata, err := ioutil.ReadFile(filepath.Join("testdata", "cipher_decipher.json"))
+	if err != nil {
+		t.Fatal("Could not read testdata fixture:", err)
+	}
+
+	var tables []struct {
+		Cipher
+
+		Input  string
+		Output string
+	}
+	if err := json.Unmarshal(testdata, &tables); err != nil {
+		t.Fatal("Could not unmarshal testdata:", err)
+	}
+
+	for _, table := range tables {
+		if out, err := table.Decipher(table.Input); err != nil {
+			t.Error("Could not decipher:", err)
+		} else if out != table.Output {
+			t.Errorf("Expected %q to decipher to %q, but instead got %q", table.Input, table.Output, out)
+		}
+	}
+}
+
+func TestCipher_Generator(t *testing.T) {
+	tables := []struct {
+		Cipher
+		Input  string
+		Output string
+	}{
+		{Cipher{Generator: (&prng.LCG{Modulus: 1024, Multiplier: 5, Increment: 1, Seed: 7}).Iterator}, "HELLO WORLD", "3SUOE9AHYY8"},
+		{Cipher{Generator: (&prng.LFG{Modulus: 32, Seed: []int{1, 2, 3, 4, 5, 6, 7}, Taps: []int{1, 7}}).IteratorA}, "HELLO WORLD", "PJ9AYAJ48GT"},
+	}
+
+	for _, table := range tables {
+		if out, err := table.Encipher(table.Input); err != nil {
+			t.Error("Could not encipher:", err)
+		} else if out != table.Output {
+			t.Errorf("Expected %q to encipher to %q, but instead got %q", table.Input, table.Output, out)
+		} else if out, err := table.Decipher(out); err != nil {
+			t.Error("Could not decipher:", err)
+		} else if out != table.Input {
+			t.Errorf("Expected %q to decipher to %q, but instead got %q", table.Output, table.Input, out)
+		}
+	}
+}
+
+func TestCipher_EncipherTrace(t *testing.T) {
+	c := Cipher{Key: "V"}
+	out, tr, err := c.EncipherTrace("A1")
+	if err != nil {
+		t.Fatal("Could not encipher:", err)
+	}
+	if out != "XSD" {
+		t.Errorf("Expected %q, but got %q", "XSD", out)
+	}
+	if len(tr) != 3 {
+		t.Fatalf("Expected 3 events, but got %d", len(tr))
+	}
+	if e := tr[1]; e.Input != '5' || e.Key != 'V' || e.Row != 15 || e.Col != 27 || e.Output != 'S' {
+		t.Errorf("Unexpected event: %+v", e)
+	}
+}
+
+func TestCipher_errors(t *testing.T) {
+	lcg := (&prng.LCG{Modulus: 32, Multiplier: 5, Increment: 1}).Iterator
+	tables := []struct {
+		Cipher
+		Input string
+	}{
+		{Cipher{}, "HELLO"},
+		{Cipher{Key: "KEY", Pad: "KEY"}, "HELLO"},
+		{Cipher{Key: "KEY", Generator: lcg}, "HELLO"},
+		{Cipher{Pad: "ABCD"}, "HELLO"},
+		{Cipher{Pad: "ABCDE!"}, "HELLO"},
+		{Cipher{Key: "K%Y"}, "HELLO"},
+		{Cipher{Key: "KEY"}, "100%"},
+		{Cipher{Generator: (&prng.LCG{}).Iterator}, "HELLO"},
+	}
+
+	for _, table := range tables {
+		if _, err := table.Encipher(table.Input); err == nil {
+			t.Errorf("Expected error enciphering %q with %+v", table.Input, table.Cipher)
+		}
+	}
+}
+
+func ExampleCipher_Encipher() {
+	c := Cipher{Generator: (&prng.LCG{Modulus: 1024, Multiplier: 5, Increment: 1, Seed: 7}).Iterator}
+	out, err := c.Encipher("HELLO WORLD")
+	if err != nil {
+		fmt.Println("Error:", err)
+	}
+	fmt.Println(out)
+
+	// Output:
+	// 3SUOE9AHYY8
+}
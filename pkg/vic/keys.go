@@ -0,0 +1,230 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vic
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Keys are the lines of the VIC key derivation worksheet, lettered as in the FBI's account of Hayhanen's procedure.
+type Keys struct {
+	A []int // the message indicator
+	B []int // the first five digits of the date
+	C []int // A minus B, digit by digit without borrowing
+	D string
+	E []int // each half of D sequenced
+	F []int // C extended by chain addition, then 1234567890
+	G []int // the first halves of E and F added without carrying
+	H []int // G encoded from the second half of F to the second half of E
+	J []int // H sequenced
+
+	// Block holds lines K to P, fifty digits chained from H.
+	Block [][]int
+
+	Q []int // the first transposition key
+	R []int // the second transposition key
+	S []int // line P sequenced, to label the checkerboard columns
+}
+
+// Derive the keys for a message from the agent's phrase, a date, the agent's personal number and the message indicator.
+// The phrase must have at least twenty letters, and the date at least five digits; other runes in either are ignored.
+// The indicator is a group of five digits.
+func Derive(phrase string, date string, personal int, indicator string) (*Keys, error) {
+	var d []rune
+	for _, r := range phrase {
+		if unicode.IsLetter(r) {
+			d = append(d, unicode.ToUpper(r))
+		}
+	}
+	if len(d) < 20 {
+		return nil, fmt.Errorf("Phrase needs at least 20 letters, but has %d", len(d))
+	}
+	d = d[:20]
+
+	b := digits(date)
+	if len(b) < 5 {
+		return nil, fmt.Errorf("Date needs at least 5 digits, but has %d", len(b))
+	}
+	b = b[:5]
+
+	a := digits(indicator)
+	if len(a) != 5 || len(a) != len([]rune(indicator)) {
+		return nil, fmt.Errorf("Indicator must be 5 digits, but got %q", indicator)
+	}
+	if personal < 0 {
+		return nil, fmt.Errorf("Personal number %d must not be negative", personal)
+	}
+
+	k := &Keys{A: a, B: b, D: string(d)}
+
+	k.C = make([]int, 5)
+	for i := range k.C {
+		k.C[i] = (a[i] - b[i] + 10) % 10
+	}
+
+	vals := make([]int, len(d))
+	for i, r := range d {
+		vals[i] = int(r)
+	}
+	k.E = append(digitsOf(sequence(vals[:10])), digitsOf(sequence(vals[10:]))...)
+	k.F = append(chain(k.C, 10), 1, 2, 3, 4, 5, 6, 7, 8, 9, 0)
+
+	k.G = make([]int, 10)
+	for i := range k.G {
+		k.G[i] = (k.E[i] + k.F[i]) % 10
+	}
+
+	// Each digit of G is found in 1234567890 and replaced with the digit below it in the second half of E
+	k.H = make([]int, 10)
+	for i, g := range k.G {
+		k.H[i] = k.E[10+(g+9)%10]
+	}
+	k.J = digitsOf(sequence(digitValues(k.H)))
+
+	kp := chain(k.H, 60)[10:]
+	for i := 0; i < len(kp); i += 10 {
+		k.Block = append(k.Block, kp[i:i+10])
+	}
+	p := k.Block[len(k.Block)-1]
+
+	// The last two unequal digits of P, each added to the personal number, give the lengths of the transposition keys
+	i := len(p) - 2
+	for i >= 0 && p[i] == p[len(p)-1] {
+		i--
+	}
+	if i < 0 {
+		return nil, errors.New("Line P has no two unequal digits")
+	}
+	first, second := personal+p[i], personal+p[len(p)-1]
+	if first < 1 || second < 1 || first+second > len(kp) {
+		return nil, fmt.Errorf("Personal number %d gives unusable transposition key lengths %d and %d", personal, first, second)
+	}
+
+	// Lines K to P are read off by columns in the order given by J
+	var qr []int
+	for _, col := range order(digitValues(k.J)) {
+		for _, row := range k.Block {
+			qr = append(qr, row[col])
+		}
+	}
+	k.Q, k.R = qr[:first], qr[first:first+second]
+	k.S = digitsOf(sequence(digitValues(p)))
+
+	return k, nil
+}
+
+// Block lines are lettered K to P, skipping O.
+const blockLines = "KLMNP"
+
+// String representation of the worksheet, one line per row.
+func (k *Keys) String() string {
+	var b strings.Builder
+	line := func(name string, dd []int) {
+		b.WriteString(name + " ")
+		for i, d := range dd {
+			if i > 0 && i%5 == 0 {
+				b.WriteRune(' ')
+			}
+			fmt.Fprint(&b, d)
+		}
+		b.WriteRune('\n')
+	}
+	line("A", k.A)
+	line("B", k.B)
+	line("C", k.C)
+	d := []rune(k.D)
+	b.WriteString("D " + string(d[:len(d)/2]) + " " + string(d[len(d)/2:]) + "\n")
+	line("E", k.E)
+	line("F", k.F)
+	line("G", k.G)
+	line("H", k.H)
+	line("J", k.J)
+	for i, row := range k.Block {
+		line(blockLines[i:i+1], row)
+	}
+	line("Q", k.Q)
+	line("R", k.R)
+	line("S", k.S)
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// Chain addition extends a series of digits by repeatedly adding the first two digits of the last stretch, without carrying.
+// This is an additive lagged Fibonacci generator with modulus 10 and taps 1 and 2, but prng.LFG refuses seeds of only even digits, which are valid here.
+// Chain returns the seed followed by enough digits to make n in all.
+func chain(seed []int, n int) []int {
+	out := make([]int, len(seed), n)
+	copy(out, seed)
+	for i := 0; len(out) < n; i++ {
+		out = append(out, (out[i]+out[i+1])%10)
+	}
+	return out
+}
+
+// Order gives the positions of values in ascending order, with ties taken from left to right.
+func order(vals []int) []int {
+	out := make([]int, len(vals))
+	for i := range out {
+		out[i] = i
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return vals[out[i]] < vals[out[j]]
+	})
+	return out
+}
+
+// Sequence ranks values in ascending order, counting from 1, with ties ranked from left to right.
+func sequence(vals []int) []int {
+	out := make([]int, len(vals))
+	for rank, i := range order(vals) {
+		out[i] = rank + 1
+	}
+	return out
+}
+
+// DigitValues orders digits as they are ranked in VIC keys, with 0 after 9.
+func digitValues(dd []int) []int {
+	out := make([]int, len(dd))
+	for i, d := range dd {
+		if d == 0 {
+			d = 10
+		}
+		out[i] = d
+	}
+	return out
+}
+
+// DigitsOf writes ranks of at most 10 as single digits, with 10 as 0.
+func digitsOf(ranks []int) []int {
+	out := make([]int, len(ranks))
+	for i, r := range ranks {
+		out[i] = r % 10
+	}
+	return out
+}
+
+// Digits in a string, ignoring other runes.
+func digits(s string) []int {
+	var out []int
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			out = append(out, int(r-'0'))
+		}
+	}
+	return out
+}
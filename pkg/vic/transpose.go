@@ -0,0 +1,75 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vic
+
+// Permute runes, taking each in turn from the given position.
+func permute(rr []rune, positions []int) []rune {
+	out := make([]rune, len(positions))
+	for i, p := range positions {
+		out[i] = rr[p]
+	}
+	return out
+}
+
+// Unpermute runes, putting each in turn at the given position.
+func unpermute(rr []rune, positions []int) []rune {
+	out := make([]rune, len(positions))
+	for i, p := range positions {
+		out[p] = rr[i]
+	}
+	return out
+}
+
+// Columns gives the positions of n runes written by rows under a key and read off by columns, taking the columns in the given order.
+func columns(n int, cols []int) []int {
+	out := make([]int, 0, n)
+	for _, c := range cols {
+		for p := c; p < n; p += len(cols) {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// Disruption gives the positions in which n runes are written into a table with disrupted areas.
+// Each area is a triangle that begins in the top row, or in the row after the last area ends, at the column taken next in the given order.
+// It covers the rest of that row, and each row below it starts one column further right, ending with a row in which it covers nothing.
+// Runes fill the table by rows, first skipping the disrupted areas and then filling them in.
+func disruption(n int, cols []int) []int {
+	w := len(cols)
+	rows := (n + w - 1) / w
+
+	disrupted := make([]bool, n)
+	for k, row := 0, 0; row < rows; k++ {
+		c := cols[k%w]
+		for i := 0; c+i <= w && row < rows; i, row = i+1, row+1 {
+			for col := c + i; col < w; col++ {
+				if p := row*w + col; p < n {
+					disrupted[p] = true
+				}
+			}
+		}
+	}
+
+	out := make([]int, 0, n)
+	for _, skip := range []bool{false, true} {
+		for p, d := range disrupted {
+			if d == skip {
+				out = append(out, p)
+			}
+		}
+	}
+	return out
+}
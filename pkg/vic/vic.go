@@ -0,0 +1,170 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package vic implements the VIC cipher, which the Soviet agent Reino Hayhanen carried in the 1950s.
+// A straddling checkerboard turns the message into digits, which then pass through a columnar transposition and a disrupted transposition.
+package vic
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/merenbach/goldbug/internal/normalize"
+	"github.com/merenbach/goldbug/internal/trace"
+	"github.com/merenbach/goldbug/pkg/checkerboard"
+)
+
+// Cipher implements the VIC cipher.
+// Alphabet and Blanks lay out the checkerboard, as in checkerboard.Cipher, and the digits labeling its columns come from the keys.
+// The indicator is needed only to encipher, since it travels inside the message, as the group counted from the end by the last digit of the date.
+// Runes missing from the checkerboard are dropped, so that ciphertext consists only of digits.
+type Cipher struct {
+	Phrase         string
+	Date           string
+	PersonalNumber int
+	Indicator      string
+	Alphabet       string
+	Blanks         []int
+	Normalizer     normalize.Normalizer
+}
+
+// Keys derived from the settings of this cipher.
+func (c *Cipher) Keys() (*Keys, error) {
+	return Derive(c.Phrase, c.Date, c.PersonalNumber, c.Indicator)
+}
+
+// Checkerboard for the given keys.
+func (c *Cipher) checkerboard(k *Keys) *checkerboard.Cipher {
+	var b strings.Builder
+	for _, d := range k.S {
+		b.WriteRune('0' + rune(d))
+	}
+	return &checkerboard.Cipher{
+		Alphabet:   c.Alphabet,
+		Digits:     b.String(),
+		Blanks:     c.Blanks,
+		Strict:     true,
+		Normalizer: c.Normalizer,
+	}
+}
+
+// Position of the indicator group in a message of n runes, the indicator included.
+func (c *Cipher) indicatorAt(n int) int {
+	dd := digits(c.Date)
+	g := dd[len(dd)-1]
+	if g == 0 {
+		g = 10
+	}
+	if i := n - 5*g; i > 0 {
+		return i
+	}
+	return 0
+}
+
+// Encipher a message.
+func (c *Cipher) Encipher(s string) (string, error) {
+	out, _, err := c.EncipherTrace(s)
+	return out, err
+}
+
+// EncipherTrace enciphers a message and records how the checkerboard substituted each rune.
+// The transpositions that follow are not traced.
+func (c *Cipher) EncipherTrace(s string) (string, trace.Trace, error) {
+	k, err := c.Keys()
+	if err != nil {
+		return "", nil, err
+	}
+	sub, tr, err := c.checkerboard(k).EncipherTrace(s)
+	if err != nil {
+		return "", nil, err
+	}
+
+	rr := []rune(sub)
+	first, second := order(digitValues(k.Q)), order(digitValues(k.R))
+	rr = permute(rr, columns(len(rr), first))
+	rr = unpermute(rr, disruption(len(rr), second))
+	rr = permute(rr, columns(len(rr), second))
+
+	i := c.indicatorAt(len(rr) + 5)
+	out := string(rr[:i]) + c.Indicator + string(rr[i:])
+	return out, tr, nil
+}
+
+// Decipher a message.
+func (c *Cipher) Decipher(s string) (string, error) {
+	out, _, err := c.DecipherTrace(s)
+	return out, err
+}
+
+// DecipherTrace deciphers a message and records how the checkerboard substituted each rune.
+// The transpositions that come before are not traced.
+func (c *Cipher) DecipherTrace(s string) (string, trace.Trace, error) {
+	rr := []rune(s)
+	if len(rr) < 5 {
+		return "", nil, errors.New("Message is too short to hold an indicator")
+	}
+	for _, r := range rr {
+		if r < '0' || r > '9' {
+			return "", nil, fmt.Errorf("Message has non-digit %q", r)
+		}
+	}
+	if len(digits(c.Date)) == 0 {
+		return "", nil, errors.New("Date needs at least 5 digits, but has 0")
+	}
+	i := c.indicatorAt(len(rr))
+	indicator := string(rr[i : i+5])
+	if c.Indicator != "" && c.Indicator != indicator {
+		return "", nil, fmt.Errorf("Message indicator %q does not match %q", indicator, c.Indicator)
+	}
+	rr = append(rr[:i:i], rr[i+5:]...)
+
+	k, err := Derive(c.Phrase, c.Date, c.PersonalNumber, indicator)
+	if err != nil {
+		return "", nil, err
+	}
+	first, second := order(digitValues(k.Q)), order(digitValues(k.R))
+	rr = unpermute(rr, columns(len(rr), second))
+	rr = permute(rr, disruption(len(rr), second))
+	rr = unpermute(rr, columns(len(rr), first))
+
+	return c.checkerboard(k).DecipherTrace(string(rr))
+}
+
+// Labels that may appear in ciphertext.
+func (c *Cipher) Labels() (string, error) {
+	return checkerboard.Digits, nil
+}
+
+// Tableau showing the key derivation worksheet and the checkerboard.
+func (c *Cipher) Tableau() (string, error) {
+	k, err := c.Keys()
+	if err != nil {
+		return "", err
+	}
+	cb, err := c.checkerboard(k).Tableau()
+	if err != nil {
+		return "", err
+	}
+	return k.String() + "\n\n" + cb, nil
+}
+
+// TableauMatrix for the checkerboard.
+func (c *Cipher) TableauMatrix() ([][]string, error) {
+	k, err := c.Keys()
+	if err != nil {
+		return nil, err
+	}
+	return c.checkerboard(k).TableauMatrix()
+}
@@ -0,0 +1,200 @@
+// Copyright 2020 Andrew Merenbach
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vic
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCipher_Encipher(t *testing.T) {
+	testdata, err := ioutil.ReadFile(filepath.Join("testdata", "cipher_encipher.json"))
+	if err != nil {
+		t.Fatal("Could not read testdata fixture:", err)
+	}
+
+	var tables []struct {
+		Cipher
+
+		Input  string
+		Output string
+	}
+	if err := json.Unmarshal(testdata, &tables); err != nil {
+		t.Fatal("Could not unmarshal testdata:", err)
+	}
+
+	for _, table := range tables {
+		if out, err := table.Encipher(table.Input); err != nil {
+			t.Error("Could not encipher:", err)
+		} else if out != table.Output {
+			t.Errorf("Expected %q to encipher to %q, but instead got %q", table.Input, table.Output, out)
+		}
+	}
+}
+
+func TestCipher_Decipher(t *testing.T) {
+	testdata, err := ioutil.ReadFile(filepath.Join("testdata", "cipher_decipher.json"))
+	if err != nil {
+		t.Fatal("Could not read testdata fixture:", err)
+	}
+
+	var tables []struct {
+		Cipher
+
+		Input  string
+		Output string
+	}
+	if err := json.Unmarshal(testdata, &tables); err != nil {
+		t.Fatal("Could not unmarshal testdata:", err)
+	}
+
+	for _, table := range tables {
+		if out, err := table.Decipher(table.Input); err != nil {
+			t.Error("Could not decipher:", err)
+		} else if out != table.Output {
+			t.Errorf("Expected %q to decipher to %q, but instead got %q", table.Input, table.Output, out)
+		}
+	}
+}
+
+func TestCipher_errors(t *testing.T) {
+	c := Cipher{Phrase: "I DREAM OF JEANNIE WITH THE LIGHT BROWN HAIR", Date: "741776", PersonalNumber: 6, Indicator: "77651"}
+
+	bad := c
+	bad.Phrase = "I DREAM OF JEANNIE"
+	if _, err := bad.Encipher("HELLO"); err == nil {
+		t.Error("Expected error for short phrase")
+	}
+	bad = c
+	bad.Indicator = "7765"
+	if _, err := bad.Encipher("HELLO"); err == nil {
+		t.Error("Expected error for short indicator")
+	}
+	bad = c
+	bad.Date = "7/4"
+	if _, err := bad.Encipher("HELLO"); err == nil {
+		t.Error("Expected error for short date")
+	}
+	bad = c
+	bad.PersonalNumber = 30
+	if _, err := bad.Encipher("HELLO"); err == nil {
+		t.Error("Expected error for overlong transposition keys")
+	}
+
+	if _, err := c.Decipher("7765"); err == nil {
+		t.Error("Expected error for message without room for an indicator")
+	}
+	bad = c
+	bad.Indicator = "12345"
+	if _, err := bad.Decipher("776517974337007077767"); err == nil {
+		t.Error("Expected error for mismatched indicator")
+	}
+	if _, err := c.Decipher("776517 74337007077767"); err == nil {
+		t.Error("Expected error for non-digit ciphertext")
+	}
+}
+
+func TestDerive(t *testing.T) {
+	// Hayhanen's own phrase, date and personal number; the lines are regression vectors checked against an independent implementation of the procedure, not the published worksheet
+	k, err := Derive("ТОЛЬКО СЛЫШНО НА УЛИЦЕ ГДЕ-ТО ОДИНОКАЯ БРОДИТ ГАРМОНЬ", "391945", 13, "20818")
+	if err != nil {
+		t.Fatal("Could not derive keys:", err)
+	}
+
+	if k.D != "ТОЛЬКОСЛЫШНОНАУЛИЦЕГ" {
+		t.Errorf("Expected line D to be %q, but got %q", "ТОЛЬКОСЛЫШНОНАУЛИЦЕГ", k.D)
+	}
+
+	tables := []struct {
+		name     string
+		line     []int
+		expected string
+	}{
+		{"A", k.A, "20818"},
+		{"B", k.B, "39194"},
+		{"C", k.C, "91724"},
+		{"E", k.E, "74201563986871954032"},
+		{"F", k.F, "91724089641234567890"},
+		{"G", k.G, "6592554252"},
+		{"H", k.H, "5938991898"},
+		{"J", k.J, "3724891506"},
+		{"K", k.Block[0], "4217809772"},
+		{"L", k.Block[1], "6385896498"},
+		{"M", k.Block[2], "9133750377"},
+		{"N", k.Block[3], "0460253047"},
+		{"P", k.Block[4], "4062783411"},
+		{"Q", k.Q, "96033183664690475"},
+		{"R", k.R, "30274304287712"},
+		{"S", k.S, "5073894612"},
+	}
+	for _, table := range tables {
+		if !reflect.DeepEqual(table.line, digits(table.expected)) {
+			t.Errorf("Expected line %s to be %s, but got %v", table.name, table.expected, table.line)
+		}
+	}
+
+	// Line P ends 411, so the keys run to 13+4 and 13+1 digits
+	if len(k.Q) != 17 || len(k.R) != 14 {
+		t.Errorf("Expected keys of 17 and 14 digits, but got %d and %d", len(k.Q), len(k.R))
+	}
+}
+
+func TestDisruption(t *testing.T) {
+	// Worked by hand: the first area starts under the column keyed 1, and covers the rest of the table
+	rr := []rune("ABCDEFGHIJKL")
+	cols := order([]int{2, 1, 4, 3})
+	out := permute(unpermute(rr, disruption(len(rr), cols)), columns(len(rr), cols))
+	if string(out) != "GCEABDIKLHJF" {
+		t.Errorf("Expected %q, but got %q", "GCEABDIKLHJF", string(out))
+	}
+
+	// The second area starts in the row after the first covers nothing
+	got := disruption(12, []int{2, 0, 1})
+	expected := []int{0, 1, 3, 4, 5, 9, 2, 6, 7, 8, 10, 11}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, but got %v", expected, got)
+	}
+}
+
+func ExampleDerive() {
+	k, err := Derive("I DREAM OF JEANNIE WITH THE LIGHT BROWN HAIR", "741776", 6, "77651")
+	if err != nil {
+		fmt.Println("Error:", err)
+	}
+	fmt.Println(k)
+
+	// Output:
+	// A 77651
+	// B 74177
+	// C 03584
+	// D IDREAMOFJE ANNIEWITHT
+	// E 62031 89574 16742 05839
+	// F 03584 38327 12345 67890
+	// G 65515 17891
+	// H 02212 15831
+	// J 04516 28973
+	// K 24333 63143
+	// L 67669 94579
+	// M 33258 39262
+	// N 65731 21888
+	// P 12043 39669
+	// Q 36534 69323 39
+	// R 28947 35236 27039
+	// S 12053 48679
+}
@@ -15,7 +15,12 @@
 package vigenere
 
 import (
+	"io"
+
+	"github.com/merenbach/goldbug/internal/normalize"
 	"github.com/merenbach/goldbug/internal/pasc"
+	"github.com/merenbach/goldbug/internal/stream"
+	"github.com/merenbach/goldbug/internal/trace"
 )
 
 // An AutokeyOption determines the autokey setting for the cipher.
@@ -34,10 +39,11 @@ const (
 
 // Cipher implements a Vigenere cipher.
 type Cipher struct {
-	Alphabet string
-	Autokey  autokeyOption
-	Key      string
-	Strict   bool
+	Alphabet   string
+	Autokey    autokeyOption
+	Key        string
+	Strict     bool
+	Normalizer normalize.Normalizer
 }
 
 func (c *Cipher) maketableau() (*pasc.TabulaRecta, error) {
@@ -51,24 +57,39 @@ func (c *Cipher) maketableau() (*pasc.TabulaRecta, error) {
 		CtAlphabet:  alphabet,
 		KeyAlphabet: alphabet,
 		Strict:      c.Strict,
+		Normalizer:  c.Normalizer,
 	}, nil
 }
 
+// EncipherAutoclave returns the rune to append to the key after encipherment, or (-1) for none.
+func (c *Cipher) encipherAutoclave(original rune, translated rune) rune {
+	switch c.Autokey {
+	case TextAutokey:
+		return original
+	case KeyAutokey:
+		return translated
+	}
+	return (-1)
+}
+
+// DecipherAutoclave returns the rune to append to the key after decipherment, or (-1) for none.
+func (c *Cipher) decipherAutoclave(original rune, translated rune) rune {
+	switch c.Autokey {
+	case TextAutokey:
+		return translated
+	case KeyAutokey:
+		return original
+	}
+	return (-1)
+}
+
 // Encipher a message.
 func (c *Cipher) Encipher(s string) (string, error) {
 	t, err := c.maketableau()
 	if err != nil {
 		return "", err
 	}
-	return t.Encipher(s, c.Key, func(original rune, translated rune) rune {
-		switch c.Autokey {
-		case TextAutokey:
-			return original
-		case KeyAutokey:
-			return translated
-		}
-		return (-1)
-	})
+	return t.Encipher(s, c.Key, c.encipherAutoclave)
 }
 
 // Decipher a message.
@@ -77,22 +98,104 @@ func (c *Cipher) Decipher(s string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return t.Decipher(s, c.Key, func(original rune, translated rune) rune {
-		switch c.Autokey {
-		case TextAutokey:
-			return translated
-		case KeyAutokey:
-			return original
-		}
-		return (-1)
-	})
+	return t.Decipher(s, c.Key, c.decipherAutoclave)
+}
+
+// EncipherTrace enciphers a message and records how each rune was produced.
+func (c *Cipher) EncipherTrace(s string) (string, trace.Trace, error) {
+	t, err := c.maketableau()
+	if err != nil {
+		return "", nil, err
+	}
+	var tr trace.Trace
+	t.Trace = &tr
+	out, err := t.Encipher(s, c.Key, c.encipherAutoclave)
+	return out, tr, err
+}
+
+// DecipherTrace deciphers a message and records how each rune was produced.
+func (c *Cipher) DecipherTrace(s string) (string, trace.Trace, error) {
+	t, err := c.maketableau()
+	if err != nil {
+		return "", nil, err
+	}
+	var tr trace.Trace
+	t.Trace = &tr
+	out, err := t.Decipher(s, c.Key, c.decipherAutoclave)
+	return out, tr, err
+}
+
+// NewEncipherWriter returns a writer that enciphers text before writing it to w.
+// Close the writer to flush any incomplete rune at the end of the text.
+func (c *Cipher) NewEncipherWriter(w io.Writer) (io.WriteCloser, error) {
+	t, err := c.maketableau()
+	if err != nil {
+		return nil, err
+	}
+	f, err := t.Encipherer(c.Key, c.encipherAutoclave)
+	if err != nil {
+		return nil, err
+	}
+	return stream.NewWriter(w, stream.Transform(f)), nil
+}
+
+// NewDecipherReader returns a reader that deciphers text read from r.
+func (c *Cipher) NewDecipherReader(r io.Reader) (io.Reader, error) {
+	t, err := c.maketableau()
+	if err != nil {
+		return nil, err
+	}
+	f, err := t.Decipherer(c.Key, c.decipherAutoclave)
+	if err != nil {
+		return nil, err
+	}
+	return stream.NewReader(r, stream.Transform(f)), nil
+}
+
+// A Compiled cipher holds precomputed lookup tables.
+// A Compiled cipher is immutable and safe for concurrent use.
+type Compiled struct {
+	t      *pasc.Compiled
+	cipher Cipher
+}
+
+// Compile this cipher.
+func (c *Cipher) Compile() (*Compiled, error) {
+	t, err := c.maketableau()
+	if err != nil {
+		return nil, err
+	}
+	ct, err := t.Compile()
+	if err != nil {
+		return nil, err
+	}
+	return &Compiled{t: ct, cipher: *c}, nil
+}
+
+// Encipher a message.
+func (c *Compiled) Encipher(s string) (string, error) {
+	return c.t.Encipher(s, c.cipher.Key, c.cipher.encipherAutoclave)
+}
+
+// Decipher a message.
+func (c *Compiled) Decipher(s string) (string, error) {
+	return c.t.Decipher(s, c.cipher.Key, c.cipher.decipherAutoclave)
 }
 
 // Tableau for encipherment and decipherment.
-func (c *Cipher) tableau() (string, error) {
+func (c *Cipher) Tableau() (string, error) {
 	t, err := c.maketableau()
 	if err != nil {
 		return "", err
 	}
 	return t.Printable()
 }
+
+// TableauMatrix for encipherment and decipherment.
+func (c *Cipher) TableauMatrix() ([][]string, error) {
+	t, err := c.maketableau()
+	if err != nil {
+		return nil, err
+	}
+	return t.Matrix()
+}
@@ -19,7 +19,9 @@ import (
 	"fmt"
 	"io/ioutil"
 	"path/filepath"
+	"strings"
 	"testing"
+	"testing/iotest"
 )
 
 func TestCipher_Encipher(t *testing.T) {
@@ -72,9 +74,180 @@ func TestCipher_Decipher(t *testing.T) {
 	}
 }
 
-func ExampleCipher_tableau() {
+func TestCipher_NewEncipherWriter(t *testing.T) {
+	testdata, err := ioutil.ReadFile(filepath.Join("testdata", "cipher_encipher.json"))
+	if err != nil {
+		t.Fatal("Could not read testdata fixture:", err)
+	}
+
+	var tables []struct {
+		Cipher
+
+		Input  string
+		Output string
+	}
+	if err := json.Unmarshal(testdata, &tables); err != nil {
+		t.Fatal("Could not unmarshal testdata:", err)
+	}
+
+	for i, table := range tables {
+		var b strings.Builder
+		w, err := table.NewEncipherWriter(&b)
+		if err != nil {
+			t.Error("Error:", err)
+			continue
+		}
+
+		// Write a byte at a time to keep key state across writes
+		for _, c := range []byte(table.Input) {
+			if _, err := w.Write([]byte{c}); err != nil {
+				t.Error("Error:", err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Error("Error:", err)
+		}
+
+		if out := b.String(); out != table.Output {
+			t.Errorf("Test %d: streamed encipherment of %q was %q; expected %q", i+1, table.Input, out, table.Output)
+		}
+	}
+}
+
+func TestCipher_NewDecipherReader(t *testing.T) {
+	testdata, err := ioutil.ReadFile(filepath.Join("testdata", "cipher_decipher.json"))
+	if err != nil {
+		t.Fatal("Could not read testdata fixture:", err)
+	}
+
+	var tables []struct {
+		Cipher
+
+		Input  string
+		Output string
+	}
+	if err := json.Unmarshal(testdata, &tables); err != nil {
+		t.Fatal("Could not unmarshal testdata:", err)
+	}
+
+	for i, table := range tables {
+		r, err := table.NewDecipherReader(iotest.OneByteReader(strings.NewReader(table.Input)))
+		if err != nil {
+			t.Error("Error:", err)
+			continue
+		}
+
+		if out, err := ioutil.ReadAll(r); err != nil {
+			t.Error("Error:", err)
+		} else if string(out) != table.Output {
+			t.Errorf("Test %d: streamed decipherment of %q was %q; expected %q", i+1, table.Input, out, table.Output)
+		}
+	}
+}
+
+func TestCompiled_Encipher(t *testing.T) {
+	testdata, err := ioutil.ReadFile(filepath.Join("testdata", "cipher_encipher.json"))
+	if err != nil {
+		t.Fatal("Could not read testdata fixture:", err)
+	}
+
+	var tables []struct {
+		Cipher
+
+		Input  string
+		Output string
+	}
+	if err := json.Unmarshal(testdata, &tables); err != nil {
+		t.Fatal("Could not unmarshal testdata:", err)
+	}
+
+	for i, table := range tables {
+		c, err := table.Compile()
+		if err != nil {
+			t.Error("Could not compile:", err)
+			continue
+		}
+		if out, err := c.Encipher(table.Input); err != nil {
+			t.Error("Could not encipher:", err)
+		} else if out != table.Output {
+			t.Errorf("Test %d: compiled encipherment of %q was %q; expected %q", i+1, table.Input, out, table.Output)
+		}
+	}
+}
+
+func TestCompiled_Decipher(t *testing.T) {
+	testdata, err := ioutil.ReadFile(filepath.Join("testdata", "cipher_decipher.json"))
+	if err != nil {
+		t.Fatal("Could not read testdata fixture:", err)
+	}
+
+	var tables []struct {
+		Cipher
+
+		Input  string
+		Output string
+	}
+	if err := json.Unmarshal(testdata, &tables); err != nil {
+		t.Fatal("Could not unmarshal testdata:", err)
+	}
+
+	for i, table := range tables {
+		c, err := table.Compile()
+		if err != nil {
+			t.Error("Could not compile:", err)
+			continue
+		}
+		if out, err := c.Decipher(table.Input); err != nil {
+			t.Error("Could not decipher:", err)
+		} else if out != table.Output {
+			t.Errorf("Test %d: compiled decipherment of %q was %q; expected %q", i+1, table.Input, out, table.Output)
+		}
+	}
+}
+
+// Message for benchmarks.
+var benchmarkMessage = strings.Repeat("THE QUICK BROWN FOX JUMPS OVER THE LAZY DOG. ", 100)
+
+func BenchmarkCipher_Encipher(b *testing.B) {
+	c := Cipher{Key: "LEMON", Autokey: TextAutokey}
+	for i := 0; i < b.N; i++ {
+		if _, err := c.Encipher(benchmarkMessage); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCompiled_Encipher(b *testing.B) {
+	c, err := (&Cipher{Key: "LEMON", Autokey: TextAutokey}).Compile()
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := c.Encipher(benchmarkMessage); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCompiled_EncipherParallel(b *testing.B) {
+	c, err := (&Cipher{Key: "LEMON", Autokey: TextAutokey}).Compile()
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := c.Encipher(benchmarkMessage); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func ExampleCipher_Tableau() {
 	c := Cipher{}
-	out, err := c.tableau()
+	out, err := c.Tableau()
 	if err != nil {
 		fmt.Println("Error:", err)
 	}
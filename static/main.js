@@ -14,7 +14,284 @@
 
 (function () {
     "use strict";
+
+    var SVG_NS = "http://www.w3.org/2000/svg";
+    var DEFAULT_ALPHABET = "ABCDEFGHIJKLMNOPQRSTUVWXYZ";
+    var LIVE_DELAY = 250;
+
+    var ciphers = {};
+    var timer = null;
+
+    function $(id) {
+        return document.getElementById(id);
+    }
+
+    // Post a JSON payload and resolve with the decoded JSON response.
+    function post(url, payload) {
+        return fetch(url, {
+            method: "POST",
+            headers: {"Content-Type": "application/json"},
+            body: JSON.stringify(payload)
+        }).then(function (response) {
+            if (!response.ok) {
+                throw new Error(response.status + " " + response.statusText);
+            }
+            return response.json();
+        });
+    }
+
+    // Build form inputs for the settings of the selected cipher.
+    function renderParams() {
+        var fieldset = $("params");
+        var params = ciphers[$("cipher").value] || [];
+
+        while (fieldset.lastChild && fieldset.lastChild.nodeName !== "LEGEND") {
+            fieldset.removeChild(fieldset.lastChild);
+        }
+        fieldset.hidden = params.length === 0;
+
+        params.forEach(function (param) {
+            var label = document.createElement("label");
+            var input = document.createElement("input");
+            input.name = param.name;
+            input.dataset.type = param.type;
+
+            switch (param.type) {
+            case "boolean":
+                input.type = "checkbox";
+                label.className = "inline";
+                label.appendChild(input);
+                label.appendChild(document.createTextNode(" " + param.name));
+                break;
+            case "number":
+                input.type = "number";
+                input.value = param.default || "0";
+                label.appendChild(document.createTextNode(param.name));
+                label.appendChild(input);
+                break;
+            default:
+                input.type = "text";
+                input.value = param.default || "";
+                input.spellcheck = false;
+                label.appendChild(document.createTextNode(param.name));
+                label.appendChild(input);
+                if (param.options) {
+                    label.appendChild(datalist(input, param.options));
+                }
+            }
+            fieldset.appendChild(label);
+        });
+    }
+
+    // Attach a list of suggested values to a text input.
+    function datalist(input, options) {
+        var list = document.createElement("datalist");
+        list.id = "options-" + input.name;
+        options.forEach(function (option) {
+            var el = document.createElement("option");
+            el.value = option;
+            list.appendChild(el);
+        });
+        input.setAttribute("list", list.id);
+        return list;
+    }
+
+    // Collect the settings of the selected cipher into a payload.
+    function payload(reverse) {
+        var out = {message: $("message").value, reverse: reverse};
+        Array.prototype.forEach.call($("params").querySelectorAll("input"), function (input) {
+            switch (input.dataset.type) {
+            case "boolean":
+                out[input.name] = input.checked;
+                break;
+            case "number":
+                out[input.name] = parseInt(input.value, 10) || 0;
+                break;
+            case "json":
+                try {
+                    out[input.name] = JSON.parse(input.value || "null");
+                } catch (e) {
+                    out[input.name] = null;
+                }
+                break;
+            default:
+                out[input.name] = input.value;
+            }
+        });
+        return out;
+    }
+
+    function showError(message) {
+        var el = $("error");
+        el.textContent = message || "";
+        el.hidden = !message;
+    }
+
+    // Report whether an alphabet setting names a preset rather than listing characters.
+    function preset(alphabet) {
+        var list = $("options-alphabet");
+        return !!list && Array.prototype.some.call(list.options, function (option) {
+            return option.value === alphabet;
+        });
+    }
+
+    // Run the selected cipher in the given direction.
+    function run(reverse) {
+        var name = $("cipher").value;
+        var p = payload(reverse);
+
+        post("/api/" + encodeURIComponent(name), p).then(function (result) {
+            showError(result.error);
+            $("output").value = result.message;
+            renderAnalysis(p.message, result.message, preset(p.alphabet) ? "" : p.alphabet);
+        }).catch(function (err) {
+            showError(err.message);
+        });
+
+        post("/api/cipher/" + encodeURIComponent(name) + "/tableau", p).then(function (result) {
+            renderTableau(result);
+        }).catch(function () {
+            renderTableau({});
+        });
+    }
+
+    // Render a tableau as both a table and plain text.
+    function renderTableau(result) {
+        var container = $("tableau-matrix");
+        container.textContent = "";
+        $("tableau-text").textContent = result.text || result.error || "";
+
+        if (!result.matrix) {
+            return;
+        }
+
+        var table = document.createElement("table");
+        table.className = "tableau";
+        result.matrix.forEach(function (row, i) {
+            var tr = document.createElement("tr");
+            row.forEach(function (cell, j) {
+                var td = document.createElement(i === 0 || j === 0 ? "th" : "td");
+                td.textContent = cell;
+                tr.appendChild(td);
+            });
+            table.appendChild(tr);
+        });
+        container.appendChild(table);
+    }
+
+    // Count occurrences of alphabet letters in a string.
+    function frequencies(s, alphabet) {
+        var counts = {};
+        var total = 0;
+        Array.prototype.forEach.call(alphabet, function (c) {
+            counts[c] = 0;
+        });
+        Array.prototype.forEach.call(s, function (c) {
+            if (counts.hasOwnProperty(c)) {
+                counts[c]++;
+                total++;
+            }
+        });
+        return {counts: counts, total: total};
+    }
+
+    // Index of coincidence for a frequency table.
+    function coincidence(freq) {
+        var sum = 0;
+        if (freq.total < 2) {
+            return null;
+        }
+        Object.keys(freq.counts).forEach(function (c) {
+            sum += freq.counts[c] * (freq.counts[c] - 1);
+        });
+        return sum / (freq.total * (freq.total - 1));
+    }
+
+    // Draw a bar chart of letter frequencies into an SVG element.
+    function drawChart(svg, freq, alphabet) {
+        var letters = Array.from(alphabet);
+        var width = 20 * letters.length;
+        var height = 140;
+        var plot = height - 20;
+        var max = 0;
+
+        letters.forEach(function (c) {
+            max = Math.max(max, freq.counts[c]);
+        });
+
+        while (svg.lastChild) {
+            svg.removeChild(svg.lastChild);
+        }
+        svg.setAttribute("viewBox", "0 0 " + width + " " + height);
+
+        letters.forEach(function (c, i) {
+            var h = max ? plot * freq.counts[c] / max : 0;
+            var bar = document.createElementNS(SVG_NS, "rect");
+            bar.setAttribute("class", "bar");
+            bar.setAttribute("x", i * 20 + 3);
+            bar.setAttribute("y", plot - h);
+            bar.setAttribute("width", 14);
+            bar.setAttribute("height", h);
+
+            var title = document.createElementNS(SVG_NS, "title");
+            title.textContent = c + ": " + freq.counts[c];
+            bar.appendChild(title);
+
+            var label = document.createElementNS(SVG_NS, "text");
+            label.setAttribute("class", "label");
+            label.setAttribute("x", i * 20 + 10);
+            label.setAttribute("y", height - 5);
+            label.textContent = c;
+
+            svg.appendChild(bar);
+            svg.appendChild(label);
+        });
+    }
+
+    function renderAnalysis(input, output, alphabet) {
+        alphabet = alphabet || DEFAULT_ALPHABET;
+        [[input, "input"], [output, "output"]].forEach(function (pair) {
+            var freq = frequencies(pair[0], alphabet);
+            var ioc = coincidence(freq);
+            drawChart($(pair[1] + "-chart"), freq, alphabet);
+            $(pair[1] + "-ioc").textContent = ioc === null ? "" : "(IoC " + ioc.toFixed(4) + ")";
+        });
+    }
+
+    function schedule() {
+        if (!$("live").checked) {
+            return;
+        }
+        clearTimeout(timer);
+        timer = setTimeout(function () {
+            run($("reverse").checked);
+        }, LIVE_DELAY);
+    }
+
     window.onload = function () {
+        JSON.parse($("ciphers").textContent).forEach(function (c) {
+            ciphers[c.name] = c.params || [];
+        });
+
+        $("cipher").addEventListener("change", function () {
+            renderParams();
+            schedule();
+        });
+        $("params").addEventListener("input", schedule);
+        $("message").addEventListener("input", schedule);
+        $("reverse").addEventListener("change", schedule);
+        $("encipher").addEventListener("click", function () {
+            run(false);
+        });
+        $("decipher").addEventListener("click", function () {
+            run(true);
+        });
+        $("cipher-form").addEventListener("submit", function (e) {
+            e.preventDefault();
+            run($("reverse").checked);
+        });
 
+        renderParams();
+        renderAnalysis("", "");
     };
-}());
\ No newline at end of file
+}());